@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"api-gateway/metrics"
+	"api-gateway/redact"
+)
+
+// Format access log yang didukung (ACCESS_LOG_FORMAT)
+const (
+	accessLogJSON     = "json"     // Structured JSON, 1 object per baris (default)
+	accessLogCombined = "combined" // Apache Combined Log Format
+)
+
+// responseRecorder membungkus http.ResponseWriter untuk mencatat status & jumlah bytes
+// Bytes HANYA dihitung di Write(), bukan di Flush(), jadi response streaming
+// (yang flush berkali-kali) tidak terhitung dobel
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (rec *responseRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.status = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += int64(n)
+	return n, err
+}
+
+// Flush diteruskan ke writer asli supaya handler streaming tetap bisa flush
+func (rec *responseRecorder) Flush() {
+	if f, ok := rec.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap dipakai http.ResponseController untuk akses writer asli
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// accessLogEntry adalah 1 baris access log (format JSON)
+type accessLogEntry struct {
+	Time       string  `json:"time"`
+	Method     string  `json:"method"`
+	Path       string  `json:"path"`
+	Status     int     `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	DurationMs float64 `json:"duration_ms"`
+	ClientIP   string  `json:"client_ip"`
+	UserAgent  string  `json:"user_agent,omitempty"`
+}
+
+// accessLog adalah middleware yang menulis 1 baris log untuk setiap HTTP request
+// IP client di-resolve lewat resolver (X-Forwarded-For hanya dipercaya dari trusted proxy)
+func accessLog(format string, out io.Writer, resolver *ipResolver, next http.Handler) http.Handler {
+	var mu sync.Mutex // Satu baris log tidak boleh tercampur dengan baris lain
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		var line []byte
+		switch format {
+		case accessLogCombined:
+			line = []byte(formatCombined(r, rec, start, resolver.clientIP(r)))
+		default:
+			line, _ = json.Marshal(accessLogEntry{
+				Time:       start.UTC().Format(time.RFC3339),
+				Method:     r.Method,
+				Path:       logURI(r),
+				Status:     rec.status,
+				Bytes:      rec.bytes,
+				DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+				ClientIP:   resolver.clientIP(r),
+				UserAgent:  r.UserAgent(),
+			})
+		}
+
+		mu.Lock()
+		out.Write(append(line, '\n'))
+		mu.Unlock()
+	})
+}
+
+// formatCombined membuat baris Combined Log Format:
+// %h %l %u [%t] "%r" %>s %b "%{Referer}i" "%{User-agent}i" %D
+// %D (durasi dalam mikrodetik) ditambahkan di akhir, sama seperti LogFormat Apache yang umum dipakai
+func formatCombined(r *http.Request, rec *responseRecorder, start time.Time, clientIP string) string {
+	size := "-"
+	if rec.bytes > 0 {
+		size = fmt.Sprintf("%d", rec.bytes)
+	}
+
+	return fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %s %q %q %d`,
+		clientIP,
+		start.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method, logURI(r), r.Proto,
+		rec.status,
+		size,
+		orDash(r.Referer()),
+		orDash(r.UserAgent()),
+		time.Since(start).Microseconds(),
+	)
+}
+
+// logURI return path + query untuk access log, dengan query param PII sudah di-hash
+// (contoh: ?email=a@b.co atau ?name=... kalau "name" termasuk PII_LOG_FIELDS)
+func logURI(r *http.Request) string {
+	if r.URL.RawQuery == "" {
+		return redact.Text(r.URL.Path)
+	}
+	return redact.Text(r.URL.Path) + "?" + redact.Query(r.URL.Query()).Encode()
+}
+
+// orDash mengganti string kosong dengan "-" (konvensi CLF)
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// recordMetrics adalah middleware yang mencatat request/latency/error metrics
+// Dipasang tepat di luar router supaya r.Pattern (route yang match) sudah terisi
+func recordMetrics(m *metrics.Metrics, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		m.RecordRequest(r.Context(), r.Method, route, rec.status, time.Since(start))
+	})
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"api-gateway/redact"
+)
+
+// serveLogged menjalankan 1 request lewat accessLog dan return baris log-nya
+func serveLogged(t *testing.T, format string, handler http.HandlerFunc, req *http.Request) string {
+	t.Helper()
+	resolver, err := newIPResolver(nil)
+	if err != nil {
+		t.Fatalf("newIPResolver: %v", err)
+	}
+	var out bytes.Buffer
+	accessLog(format, &out, resolver, handler).ServeHTTP(httptest.NewRecorder(), req)
+
+	line := out.String()
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Fatalf("want exactly 1 log line, got %q", line)
+	}
+	return strings.TrimSuffix(line, "\n")
+}
+
+func notFoundHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotFound)
+	w.Write([]byte("user not found"))
+}
+
+func TestAccessLogJSON(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/u1?fields=name", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "curl/8.0")
+
+	var entry accessLogEntry
+	if err := json.Unmarshal([]byte(serveLogged(t, accessLogJSON, notFoundHandler, req)), &entry); err != nil {
+		t.Fatalf("log line is not JSON: %v", err)
+	}
+
+	if entry.Method != "GET" || entry.Path != "/users/u1?fields=name" || entry.Status != http.StatusNotFound ||
+		entry.Bytes != int64(len("user not found")) || entry.ClientIP != "203.0.113.7" || entry.UserAgent != "curl/8.0" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if entry.Time == "" || entry.DurationMs < 0 {
+		t.Fatalf("missing time/duration: %+v", entry)
+	}
+}
+
+func TestAccessLogCombined(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/u1", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "curl/8.0")
+	req.Header.Set("Referer", "https://app.example.com/")
+
+	line := serveLogged(t, accessLogCombined, notFoundHandler, req)
+
+	// %h %l %u [%t] "%r" %>s %b "%{Referer}i" "%{User-agent}i" %D
+	pattern := regexp.MustCompile(`^203\.0\.113\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /users/u1 HTTP/1\.1" 404 14 "https://app\.example\.com/" "curl/8\.0" \d+$`)
+	if !pattern.MatchString(line) {
+		t.Fatalf("combined log line = %q", line)
+	}
+}
+
+func TestAccessLogCombinedEmptyFields(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/users/u1", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	noContent := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }
+
+	line := serveLogged(t, accessLogCombined, noContent, req)
+	// Tanpa body → "-", tanpa Referer/User-Agent → "-"
+	if !strings.Contains(line, `"DELETE /users/u1 HTTP/1.1" 204 - "-" "-" `) {
+		t.Fatalf("combined log line = %q", line)
+	}
+}
+
+func TestAccessLogStreamingBytesCountedOnce(t *testing.T) {
+	chunks := []string{"data: 1\n\n", "data: 22\n\n", "data: 333\n\n"}
+	streaming := func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		for _, chunk := range chunks {
+			w.Write([]byte(chunk))
+			flusher.Flush()
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/users/stream", nil)
+	var entry accessLogEntry
+	if err := json.Unmarshal([]byte(serveLogged(t, accessLogJSON, streaming, req)), &entry); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if want := int64(len(strings.Join(chunks, ""))); entry.Bytes != want {
+		t.Fatalf("bytes = %d, want %d (flush must not add bytes)", entry.Bytes, want)
+	}
+	if entry.Status != http.StatusOK {
+		t.Fatalf("status = %d, want 200", entry.Status)
+	}
+}
+
+func TestAccessLogRedactsEmails(t *testing.T) {
+	redact.Enable("test-salt", []string{"email", "name"})
+	t.Cleanup(func() { redact.Enable("", nil) }) // Tanpa field PII = redaction tidak berefek
+
+	for _, format := range []string{accessLogJSON, accessLogCombined} {
+		req := httptest.NewRequest(http.MethodGet, "/users/by-email/Alice@Example.com?email=bob%40example.com&name=Bob&limit=5", nil)
+		line := serveLogged(t, format, notFoundHandler, req)
+
+		for _, raw := range []string{"Alice@Example.com", "alice@example.com", "bob@example.com", "bob%40example.com", "name=Bob"} {
+			if strings.Contains(line, raw) {
+				t.Fatalf("%s log line contains %q: %s", format, raw, line)
+			}
+		}
+		if !strings.Contains(line, redact.Field("email", "alice@example.com")) || !strings.Contains(line, "limit=5") {
+			t.Fatalf("%s log line = %s, want hashed email and untouched limit", format, line)
+		}
+	}
+}
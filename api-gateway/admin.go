@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readOnlyMessage harus sama dengan interceptor.ReadOnlyMessage di user-service
+const readOnlyMessage = "service in read-only mode"
+
+// requireAdmin adalah middleware untuk endpoint admin
+// 1. ADMIN_ENABLED=false → endpoint "tidak ada" (404), supaya tidak ketahuan dari luar
+// 2. Token wajib dikirim sebagai "Authorization: Bearer <ADMIN_TOKEN>"
+//
+// ADMIN_TOKEN hanya membuka endpoint di gateway. Kalau auth User Service aktif, RPC admin
+// butuh credential sendiri: "X-User-Authorization: Bearer <JWT role admin>" atau X-API-Key
+// (diteruskan handler lewat withAdminAuth)
+func (gw *APIGateway) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !gw.cfg.AdminEnabled {
+			http.NotFound(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			http.Error(w, "admin token required", http.StatusUnauthorized)
+			return
+		}
+
+		// Constant-time compare supaya tidak bocor lewat timing attack
+		if subtle.ConstantTimeCompare([]byte(token), []byte(gw.cfg.AdminToken)) != 1 {
+			http.Error(w, "invalid admin token", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// DebugConfigHandler menghandle GET /debug/config
+// Menampilkan konfigurasi efektif gateway (secret sudah di-redact),
+// berguna untuk cek env var mana yang benar-benar terpakai tanpa SSH ke server
+func (gw *APIGateway) DebugConfigHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gw.cfg.Redacted())
+}
+
+// ReadOnlyHandler menghandle POST /admin/read-only?enabled=true|false
+// Toggle safe-mode di User Service saat runtime (write ditolak, read tetap jalan)
+func (gw *APIGateway) ReadOnlyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	enabled, err := strconv.ParseBool(r.URL.Query().Get("enabled"))
+	if err != nil {
+		http.Error(w, "enabled must be true or false", http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(withAdminAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	resp, err := gw.userClient.SetReadOnly(ctx, &pb.SetReadOnlyRequest{Enabled: enabled})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("🔒 Read-only mode set to %v", resp.Enabled)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"readOnly": resp.Enabled,
+	})
+}
+
+// isReadOnlyError cek apakah write ditolak karena User Service sedang read-only
+// Error ini di-surface sebagai 503: masalahnya sementara, bukan salah client
+func isReadOnlyError(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.FailedPrecondition && st.Message() == readOnlyMessage
+}
+
+// writeReadOnlyError menulis response 503 untuk write yang ditolak read-only mode
+func writeReadOnlyError(w http.ResponseWriter) {
+	w.Header().Set("Retry-After", "60")
+	writeErrorEnvelope(w, http.StatusServiceUnavailable, codes.FailedPrecondition.String(), "service is in read-only mode, writes are temporarily disabled")
+}
+
+// CompactHandler menghandle POST /admin/compact
+// Memicu maintenance di User Service (rebuild secondary index) dan return hasilnya
+func (gw *APIGateway) CompactHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Rebuild jalan di bawah write lock, beri waktu lebih dari request biasa
+	ctx, cancel := context.WithTimeout(withAdminAuth(r.Context(), r), 30*time.Second)
+	defer cancel()
+
+	resp, err := gw.userClient.Compact(ctx, &pb.CompactRequest{})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("🧹 Compaction done: %d index entries", resp.IndexEntries)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"purgedRecords":       resp.PurgedRecords,
+		"indexEntries":        resp.IndexEntries,
+		"staleIndexEntries":   resp.StaleIndexEntries,
+		"missingIndexEntries": resp.MissingIndexEntries,
+	})
+}
+
+// VerifyIntegrityHandler menghandle POST /admin/verify-integrity?repair=true|false
+// Cek konsistensi secondary index di User Service; repair=true → index di-rebuild
+func (gw *APIGateway) VerifyIntegrityHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	repair := false
+	if raw := r.URL.Query().Get("repair"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			http.Error(w, "repair must be true or false", http.StatusBadRequest)
+			return
+		}
+		repair = v
+	}
+
+	ctx, cancel := context.WithTimeout(withAdminAuth(r.Context(), r), 30*time.Second)
+	defer cancel()
+
+	resp, err := gw.userClient.VerifyIntegrity(ctx, &pb.VerifyIntegrityRequest{Repair: repair})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	mismatches := make([]map[string]string, 0, len(resp.Mismatches))
+	for _, m := range resp.Mismatches {
+		mismatches = append(mismatches, map[string]string{
+			"index":      m.Index,
+			"key":        m.Key,
+			"indexedId":  m.IndexedId,
+			"expectedId": m.ExpectedId,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"consistent": len(resp.Mismatches) == 0,
+		"mismatches": mismatches,
+		"repaired":   resp.Repaired,
+	})
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testAdminToken = "gateway-admin-token" // ADMIN_TOKEN milik gateway
+	testAdminJWT   = "Bearer admin-jwt"    // Token user dengan role admin
+	testUserJWT    = "Bearer user-jwt"     // Token user tanpa role admin
+	testAPIKey     = "0123456789abcdef-api-key"
+)
+
+// adminBackend adalah User Service palsu untuk RPC admin
+type adminBackend struct {
+	pb.UnimplementedUserServiceServer
+
+	mu            sync.Mutex
+	authorization []string                // Metadata "authorization" yang diterima, untuk cek ADMIN_TOKEN tidak bocor
+	bulkDeletes   []*pb.BulkDeleteRequest // BulkDeleteUsers yang sampai ke User Service
+}
+
+func (b *adminBackend) SetReadOnly(ctx context.Context, req *pb.SetReadOnlyRequest) (*pb.SetReadOnlyResponse, error) {
+	return &pb.SetReadOnlyResponse{Enabled: req.Enabled}, nil
+}
+
+func (b *adminBackend) Compact(ctx context.Context, req *pb.CompactRequest) (*pb.CompactResponse, error) {
+	return &pb.CompactResponse{IndexEntries: 3}, nil
+}
+
+func (b *adminBackend) VerifyIntegrity(ctx context.Context, req *pb.VerifyIntegrityRequest) (*pb.VerifyIntegrityResponse, error) {
+	return &pb.VerifyIntegrityResponse{}, nil
+}
+
+func (b *adminBackend) BulkDeleteUsers(ctx context.Context, req *pb.BulkDeleteRequest) (*pb.BulkDeleteResponse, error) {
+	b.mu.Lock()
+	b.bulkDeletes = append(b.bulkDeletes, req)
+	b.mu.Unlock()
+	return &pb.BulkDeleteResponse{DeletedCount: 2}, nil
+}
+
+// authInterceptor meniru User Service dengan auth aktif: JWT (interceptor.Auth + Authorize
+// dengan policy admin) atau API key; tanpa credential → Unauthenticated, bukan admin → PermissionDenied
+func (b *adminBackend) authInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	b.mu.Lock()
+	b.authorization = append(b.authorization, md.Get("authorization")...)
+	b.mu.Unlock()
+
+	if keys := md.Get("x-api-key"); len(keys) > 0 {
+		if keys[0] != testAPIKey {
+			return nil, status.Error(codes.Unauthenticated, "invalid API key")
+		}
+		return handler(ctx, req)
+	}
+	switch tokens := md.Get("authorization"); {
+	case len(tokens) == 0:
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	case tokens[0] == testAdminJWT:
+		return handler(ctx, req)
+	case tokens[0] == testUserJWT:
+		return nil, status.Errorf(codes.PermissionDenied, "%s requires role admin", info.FullMethod)
+	default:
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+}
+
+func TestAdminRoutesForwardUpstreamCredential(t *testing.T) {
+	backend := &adminBackend{}
+	upstream := startUserService(t, backend, grpc.UnaryInterceptor(backend.authInterceptor))
+	cfg := testConfig(t, map[string]string{"ADMIN_ENABLED": "true", "ADMIN_TOKEN": testAdminToken})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+
+	routes := []struct{ method, target string }{
+		{http.MethodPost, "/admin/read-only?enabled=true"},
+		{http.MethodPost, "/admin/compact"},
+		{http.MethodPost, "/admin/verify-integrity?repair=true"},
+		{http.MethodDelete, "/users?domain=example.com&confirm=true"},
+	}
+	credentials := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"no upstream credential", http.Header{}, http.StatusUnauthorized},
+		{"non-admin JWT", http.Header{"X-User-Authorization": {testUserJWT}}, http.StatusForbidden},
+		{"admin JWT", http.Header{"X-User-Authorization": {testAdminJWT}}, http.StatusOK},
+		{"valid API key", http.Header{"X-Api-Key": {testAPIKey}}, http.StatusOK},
+		{"invalid API key", http.Header{"X-Api-Key": {"wrong-key-wrong-key"}}, http.StatusUnauthorized},
+	}
+
+	for _, route := range routes {
+		for _, cred := range credentials {
+			t.Run(route.method+" "+route.target+"/"+cred.name, func(t *testing.T) {
+				header := cred.header.Clone()
+				header.Set("Authorization", "Bearer "+testAdminToken)
+
+				rec := doRequest(router, route.method, route.target, "", header)
+				if rec.Code != cred.want {
+					t.Fatalf("status = %d, want %d (body: %s)", rec.Code, cred.want, rec.Body)
+				}
+			})
+		}
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	for _, token := range backend.authorization {
+		if token == "Bearer "+testAdminToken {
+			t.Fatal("ADMIN_TOKEN was forwarded to User Service")
+		}
+	}
+}
+
+func TestAdminRoutesRequireAdminToken(t *testing.T) {
+	upstream := startUserService(t, &adminBackend{})
+	cfg := testConfig(t, map[string]string{"ADMIN_ENABLED": "true", "ADMIN_TOKEN": testAdminToken})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+
+	tests := []struct {
+		name  string
+		token string
+		want  int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong", "Bearer nope", http.StatusForbidden},
+		{"valid", "Bearer " + testAdminToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.token != "" {
+				header.Set("Authorization", tt.token)
+			}
+			if rec := doRequest(router, http.MethodPost, "/admin/compact", "", header); rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestBulkDeleteRequiresConfirmAndFilter(t *testing.T) {
+	backend := &adminBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"ADMIN_ENABLED": "true", "ADMIN_TOKEN": testAdminToken})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+	admin := http.Header{"Authorization": {"Bearer " + testAdminToken}}
+
+	rejected := []string{
+		"/users?domain=example.com",               // Tanpa confirm
+		"/users?domain=example.com&confirm=false", // confirm harus persis "true"
+		"/users?domain=example.com&confirm=1",
+		"/users?confirm=true", // Tanpa filter
+	}
+	for _, target := range rejected {
+		if rec := doRequest(router, http.MethodDelete, target, "", admin); rec.Code != http.StatusBadRequest {
+			t.Fatalf("DELETE %s: status = %d, want 400", target, rec.Code)
+		}
+	}
+	if len(backend.bulkDeletes) != 0 {
+		t.Fatalf("rejected requests reached User Service: %v", backend.bulkDeletes)
+	}
+
+	rec := doRequest(router, http.MethodDelete, "/users?olderThan=2024-01-01T00:00:00Z&domain=example.com&confirm=true", "", admin)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("confirmed bulk delete: status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if got := strings.TrimSpace(rec.Body.String()); got != `{"deleted":2}` {
+		t.Fatalf("body = %s, want {\"deleted\":2}", got)
+	}
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.bulkDeletes) != 1 {
+		t.Fatalf("BulkDeleteUsers calls = %d, want 1", len(backend.bulkDeletes))
+	}
+	if req := backend.bulkDeletes[0]; req.OlderThan != "2024-01-01T00:00:00Z" || req.EmailDomain != "example.com" {
+		t.Fatalf("forwarded filter = %v", req)
+	}
+}
+
+func TestDebugConfigRedactsSecrets(t *testing.T) {
+	upstream := startUserService(t, &adminBackend{})
+	cfg := testConfig(t, map[string]string{
+		"ADMIN_ENABLED":     "true",
+		"ADMIN_TOKEN":       testAdminToken,
+		"PII_LOG_REDACTION": "true",
+		"PII_LOG_SALT":      "pii-salt-value",
+	})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+
+	if rec := doRequest(router, http.MethodGet, "/debug/config", "", nil); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("without admin token: status = %d, want 401", rec.Code)
+	}
+
+	rec := doRequest(router, http.MethodGet, "/debug/config", "", http.Header{"Authorization": {"Bearer " + testAdminToken}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	for _, secret := range []string{testAdminToken, "pii-salt-value"} {
+		if strings.Contains(rec.Body.String(), secret) {
+			t.Fatalf("secret %q leaked in /debug/config: %s", secret, rec.Body)
+		}
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["ADMIN_TOKEN"] != "[REDACTED]" || body["PII_LOG_SALT"] != "[REDACTED]" {
+		t.Fatalf("ADMIN_TOKEN = %v, PII_LOG_SALT = %v, want [REDACTED]", body["ADMIN_TOKEN"], body["PII_LOG_SALT"])
+	}
+	if body["ADMIN_ENABLED"] != true {
+		t.Fatalf("ADMIN_ENABLED = %v, want non-secret value visible", body["ADMIN_ENABLED"])
+	}
+}
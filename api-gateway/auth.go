@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+
+	pb "api-gateway/proto/user"
+	"api-gateway/redact"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// withAuth meneruskan header Authorization dan X-API-Key dari HTTP request ke metadata
+// "authorization" / "x-api-key" di gRPC call, supaya User Service bisa memverifikasinya
+// sesuai AUTH_MODE-nya (lihat interceptor.Auth dan interceptor.APIKey)
+// Header tidak ada → ctx dikembalikan apa adanya (endpoint publik tetap jalan)
+//
+// Endpoint admin (requireAdmin) memakai withAdminAuth: header Authorization di sana
+// berisi ADMIN_TOKEN milik gateway, bukan token user, jadi tidak boleh bocor ke backend
+func withAuth(ctx context.Context, r *http.Request) context.Context {
+	return forwardAuth(ctx, r.Header.Get("Authorization"), r.Header.Get("X-API-Key"))
+}
+
+// withAdminAuth adalah withAuth untuk endpoint admin (requireAdmin)
+// Bearer token user dengan role admin (AUTH_MODE=jwt) dikirim lewat header X-User-Authorization,
+// karena Authorization sudah dipakai untuk ADMIN_TOKEN; X-API-Key diteruskan sama seperti withAuth
+// Tanpa salah satunya, RPC admin ditolak User Service begitu auth-nya aktif (401/403)
+func withAdminAuth(ctx context.Context, r *http.Request) context.Context {
+	return forwardAuth(ctx, r.Header.Get("X-User-Authorization"), r.Header.Get("X-API-Key"))
+}
+
+// forwardAuth menambahkan metadata "authorization" / "x-api-key" yang tidak kosong ke ctx
+func forwardAuth(ctx context.Context, token, apiKey string) context.Context {
+	if token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", token)
+	}
+	if apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+	}
+	return ctx
+}
+
+// LoginHandler menghandle POST /auth/login dengan body {"email", "password"}
+// Return {"token": "..."}; token dipakai sebagai "Authorization: Bearer <token>" di request berikutnya
+// Email/password salah → 401 (pesan sama untuk keduanya, lihat Authenticate di User Service)
+func (gw *APIGateway) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. PARSE HTTP REQUEST BODY (JSON)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &pb.AuthenticateRequest{}
+	if err := decodeProtoJSON(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Password tidak pernah di-log
+	log.Printf("📥 Received Authenticate request: %s", redact.Field("email", req.Email))
+
+	// 2. CALL gRPC METHOD (Unary RPC)
+	// Tidak memakai withAuth: login justru dipanggil sebelum client punya token
+	ctx, cancel := context.WithTimeout(r.Context(), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	resp, err := gw.userClient.Authenticate(ctx, req)
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	// 3. RETURN RESPONSE (token tidak boleh di-cache browser/proxy)
+	w.Header().Set("Cache-Control", "no-store")
+	writeProtoJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// authMetadataBackend mencatat metadata "authorization" dan "x-api-key" dari setiap call
+type authMetadataBackend struct {
+	pb.UnimplementedUserServiceServer
+	mu      sync.Mutex
+	seen    [][]string
+	apiKeys [][]string
+}
+
+func (b *authMetadataBackend) record(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.seen = append(b.seen, md.Get("authorization"))
+	b.apiKeys = append(b.apiKeys, md.Get("x-api-key"))
+}
+
+func (b *authMetadataBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	b.record(ctx)
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func (b *authMetadataBackend) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	b.record(ctx)
+	return &pb.DeleteUserResponse{Deleted: true}, nil
+}
+
+func TestAuthorizationForwardedAsMetadata(t *testing.T) {
+	backend := &authMetadataBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	const token = "Bearer eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJ1MSJ9.c2ln"
+	auth := http.Header{"Authorization": {token}}
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", auth); rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if rec := doRequest(router, http.MethodDelete, "/users/u1", "", auth); rec.Code >= 300 {
+		t.Fatalf("DELETE status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	// Tanpa header: call tetap jalan, tanpa metadata authorization
+	if rec := doRequest(router, http.MethodGet, "/users/u2", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("GET without auth status = %d (body: %s)", rec.Code, rec.Body)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	want := [][]string{{token}, {token}, nil}
+	if !reflect.DeepEqual(backend.seen, want) {
+		t.Fatalf("authorization metadata = %q, want %q", backend.seen, want)
+	}
+}
+
+func TestAPIKeyForwardedAsMetadata(t *testing.T) {
+	backend := &authMetadataBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	const key = "service-api-key-0123456789"
+	header := http.Header{"X-Api-Key": {key}}
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", header); rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if rec := doRequest(router, http.MethodDelete, "/users/u1", "", header); rec.Code >= 300 {
+		t.Fatalf("DELETE status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	// Tanpa header: tidak ada metadata x-api-key kosong yang ikut terkirim
+	if rec := doRequest(router, http.MethodGet, "/users/u2", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("GET without key status = %d (body: %s)", rec.Code, rec.Body)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	want := [][]string{{key}, {key}, nil}
+	if !reflect.DeepEqual(backend.apiKeys, want) {
+		t.Fatalf("x-api-key metadata = %q, want %q", backend.apiKeys, want)
+	}
+	// API key tidak ikut dikirim sebagai authorization
+	if !reflect.DeepEqual(backend.seen, [][]string{nil, nil, nil}) {
+		t.Fatalf("authorization metadata = %q, want none", backend.seen)
+	}
+}
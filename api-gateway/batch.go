@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	pb "api-gateway/proto/user"
+)
+
+// BatchCreateUsersHandler menghandle POST /users/batch dengan body JSON array:
+//
+//	[{"name": "...", "email": "...", "age": 20, "status": "active"}, ...]
+//
+// Setiap item dikirim sebagai 1 message di Client Streaming RPC BatchCreateUsers,
+// lalu response tunggal dari server (created, failed, ids, errors) dikembalikan sebagai JSON
+func (gw *APIGateway) BatchCreateUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI HTTP METHOD
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE HTTP REQUEST BODY (JSON array)
+	// Array dipecah dulu, lalu tiap item di-decode ke CreateUserRequest lewat protojson
+	var items []json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(items) == 0 {
+		http.Error(w, "body must contain at least one user", http.StatusBadRequest)
+		return
+	}
+
+	// Semua item di-decode dulu: JSON/status tidak valid = request salah, bukan item gagal
+	reqs := make([]*pb.CreateUserRequest, len(items))
+	for i, item := range items {
+		reqs[i] = &pb.CreateUserRequest{}
+		if err := decodeProtoJSON(item, reqs[i]); err != nil {
+			http.Error(w, fmt.Sprintf("item %d: %v", i, err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	log.Printf("📥 Received BatchCreateUsers request (%d users)", len(reqs))
+
+	// 3. CONTEXT dengan TIMEOUT (batch besar butuh waktu lebih lama dari CreateUser tunggal)
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), 30*time.Second)
+	defer cancel()
+
+	// 4. CALL gRPC CLIENT STREAMING METHOD
+	resp, err := gw.batchCreateUsers(ctx, reqs)
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ Batch created: %d created, %d failed", resp.Created, resp.Failed)
+
+	// 5. RETURN HTTP RESPONSE (JSON)
+	// Sebagian item boleh gagal, jadi status tetap 200 dan detailnya ada di "errors"
+	writeProtoJSON(w, http.StatusOK, resp)
+}
+
+// batchCreateUsers mengirim semua request lewat 1 stream lalu menunggu response tunggal server
+func (gw *APIGateway) batchCreateUsers(ctx context.Context, reqs []*pb.CreateUserRequest) (*pb.BatchCreateUsersResponse, error) {
+	stream, err := gw.userClient.BatchCreateUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, req := range reqs {
+		// io.EOF dari Send = server sudah menutup stream (misal read-only mode)
+		// Error aslinya baru bisa dibaca lewat CloseAndRecv
+		if err := stream.Send(req); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+	}
+
+	// CloseAndRecv: tandai client selesai kirim (server menerima io.EOF), lalu tunggu response
+	return stream.CloseAndRecv()
+}
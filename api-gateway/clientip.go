@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ipResolver menentukan IP client yang sebenarnya
+// Di belakang load balancer, RemoteAddr adalah IP load balancer, jadi IP client
+// diambil dari X-Forwarded-For — TAPI hanya kalau request datang dari proxy yang dipercaya
+// (kalau tidak, client bisa memalsukan XFF sesukanya)
+type ipResolver struct {
+	trusted []*net.IPNet // TRUSTED_PROXIES
+}
+
+// newIPResolver parse daftar CIDR / IP tunggal (contoh: "10.0.0.0/8", "127.0.0.1")
+func newIPResolver(trustedProxies []string) (*ipResolver, error) {
+	res := &ipResolver{}
+	for _, entry := range trustedProxies {
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		res.trusted = append(res.trusted, network)
+	}
+	return res, nil
+}
+
+// isTrusted cek apakah IP termasuk trusted proxy
+func (res *ipResolver) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range res.trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP return IP client untuk logging, rate/connection limit, dll
+// X-Forwarded-For dibaca dari KANAN: hop paling kanan ditambahkan oleh proxy terdekat,
+// jadi IP pertama (dari kanan) yang BUKAN trusted proxy adalah client sebenarnya
+func (res *ipResolver) clientIP(r *http.Request) string {
+	peer := remoteHost(r.RemoteAddr)
+	if !res.isTrusted(peer) {
+		return peer
+	}
+
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if !res.isTrusted(hop) {
+			return hop
+		}
+	}
+	return peer // Semua hop trusted (atau XFF kosong)
+}
+
+// remoteHost mengambil host dari "host:port" (tanpa port)
+func remoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/stats"
+)
+
+// payloadStats mencatat ukuran message yang dikirim server: sebelum dan sesudah kompresi
+type payloadStats struct {
+	length     atomic.Int64
+	wireLength atomic.Int64
+}
+
+func (s *payloadStats) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context   { return ctx }
+func (s *payloadStats) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+func (s *payloadStats) HandleConn(context.Context, stats.ConnStats)                       {}
+
+func (s *payloadStats) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	if out, ok := rs.(*stats.OutPayload); ok {
+		s.length.Add(int64(out.Length))
+		s.wireLength.Add(int64(out.WireLength))
+	}
+}
+
+// streamLargeList men-stream ribuan user lewat gateway, return statistik payload di sisi server
+func streamLargeList(t *testing.T, compression string) *payloadStats {
+	t.Helper()
+	const n = 3000
+	backend := newListBackend(n, "")
+	for _, user := range backend.users {
+		user.Name += strings.Repeat(" with a long and very repetitive display name", 5)
+	}
+	payload := &payloadStats{}
+	upstream := startUserService(t, backend, grpc.StatsHandler(payload))
+	cfg := testConfig(t, map[string]string{"GRPC_COMPRESSION": compression})
+	srv := serveGateway(t, newTestGateway(t, cfg, upstream.addr))
+
+	resp := getStream(t, srv.URL+"/users/stream", protobufStreamContentType)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	users := readFrames(t, resp.Body)
+	if len(users) != n {
+		t.Fatalf("received %d users, want %d", len(users), n)
+	}
+	for i, user := range users {
+		if user.Id != backend.users[i].Id || user.Name != backend.users[i].Name {
+			t.Fatalf("user %d = %v, want %v", i, user, backend.users[i])
+		}
+	}
+	return payload
+}
+
+func TestGzipCompressionLargeStream(t *testing.T) {
+	payload := streamLargeList(t, "gzip")
+	if wire, raw := payload.wireLength.Load(), payload.length.Load(); wire*2 > raw {
+		t.Fatalf("wire bytes = %d for %d bytes of messages, want gzip to at least halve it", wire, raw)
+	}
+}
+
+func TestCompressionOffByDefault(t *testing.T) {
+	payload := streamLargeList(t, "")
+	if wire, raw := payload.wireLength.Load(), payload.length.Load(); wire < raw {
+		t.Fatalf("wire bytes = %d < %d message bytes, want no compression by default", wire, raw)
+	}
+}
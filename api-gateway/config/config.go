@@ -0,0 +1,512 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config menyimpan semua konfigurasi API Gateway
+// Semua nilai dibaca dari environment variable dengan default untuk development
+//
+// Struct tags:
+//   - env:"NAME"      → nama environment variable (dipakai juga sebagai key di /debug/config)
+//   - secret:"true"   → nilai selalu di-redact saat ditampilkan
+//   - secret:"url"    → hanya password di dalam URL/DSN yang di-redact
+type Config struct {
+	// Alamat yang dipakai gateway
+	HTTPPort         int      `env:"HTTP_PORT"`         // Port HTTP server gateway
+	UserServiceAddrs []string `env:"USER_SERVICE_ADDR"` // host:port User Service (gRPC), dipisah koma untuk banyak instance
+
+	// Stale-while-down: sajikan data GetUser terakhir yang diketahui saat upstream mati
+	StaleWhileDown bool `env:"STALE_WHILE_DOWN"`
+	StaleCacheSize int  `env:"STALE_CACHE_SIZE"` // Jumlah user yang diingat
+
+	// Response cache untuk GET /users/{id} (berbeda dengan stale cache: dipakai saat upstream sehat)
+	ResponseCacheTTL  time.Duration `env:"RESPONSE_CACHE_TTL"`  // 0 = disabled
+	ResponseCacheSize int           `env:"RESPONSE_CACHE_SIZE"` // Jumlah response maksimal (LRU)
+
+	// Request hedging untuk GetUser (read yang aman diulang)
+	HedgeDelay       time.Duration `env:"HEDGE_DELAY"`        // 0 = disabled
+	HedgeMaxAttempts int           `env:"HEDGE_MAX_ATTEMPTS"` // Total attempt termasuk yang pertama
+
+	// JSON schema validation untuk request body
+	SchemaValidation bool   `env:"SCHEMA_VALIDATION"`
+	SchemaDir        string `env:"SCHEMA_DIR"` // Kosong = pakai schema bawaan (embedded)
+
+	// Batas struktur JSON request body (proteksi payload nested/ribuan token)
+	JSONMaxDepth  int `env:"JSON_MAX_DEPTH"`  // Kedalaman nesting object/array maksimal, 0 = tanpa batas
+	JSONMaxTokens int `env:"JSON_MAX_TOKENS"` // Jumlah token JSON maksimal, 0 = tanpa batas
+
+	// Encoding protobuf message di response JSON (protojson)
+	JSONEmitDefaults  bool `env:"JSON_EMIT_DEFAULTS"`   // Tulis juga field bernilai default (0, "", false)
+	JSONUseProtoNames bool `env:"JSON_USE_PROTO_NAMES"` // Nama field snake_case (created_at) bukan camelCase (createdAt)
+
+	// Timeout unary gRPC call per HTTP request (di atas r.Context(): client disconnect juga membatalkan call)
+	// Stream/batch/export punya timeout sendiri yang lebih panjang
+	RPCTimeout time.Duration `env:"GATEWAY_RPC_TIMEOUT"`
+
+	// Ukuran message gRPC maksimal (terima & kirim), harus sama dengan setting User Service
+	GRPCMaxMsgSizeMB int `env:"GRPC_MAX_MSG_SIZE_MB"` // Default 4 (default gRPC)
+
+	// Keepalive koneksi gateway → User Service: ping saat idle supaya tidak diputus diam-diam oleh LB/NAT
+	// Rekomendasi production: 30s / 10s; KEEPALIVE_TIME tidak boleh lebih kecil dari
+	// KEEPALIVE_MIN_TIME di User Service (default 10s), kalau tidak koneksi diputus "too_many_pings"
+	KeepaliveTime    time.Duration `env:"KEEPALIVE_TIME"`    // Ping kalau koneksi idle selama ini (minimal 10s, batas gRPC)
+	KeepaliveTimeout time.Duration `env:"KEEPALIVE_TIMEOUT"` // Koneksi dianggap mati kalau ping tidak dibalas dalam waktu ini
+
+	// Probe User Service saat startup (grpc.NewClient lazy: tanpa probe gateway start walau backend mati)
+	RequireBackendReady bool          `env:"REQUIRE_BACKEND_READY"` // true = gagal start kalau backend tidak SERVING, false = hanya warning
+	BackendReadyTimeout time.Duration `env:"BACKEND_READY_TIMEOUT"` // Batas waktu menunggu backend SERVING, 0 = probe dimatikan
+
+	// Graceful shutdown: batas waktu menunggu request HTTP yang sedang jalan sebelum ditutup paksa
+	ShutdownDrainTimeout time.Duration `env:"SHUTDOWN_DRAIN_TIMEOUT"`
+
+	// Kompresi message gRPC gateway ↔ User Service: "identity" (default, tanpa kompresi) atau "gzip"
+	// gzip menghemat bandwidth untuk ListUsers/export besar, tapi menambah CPU untuk message kecil
+	GRPCCompression string `env:"GRPC_COMPRESSION"`
+
+	// Retry transparan oleh gRPC client (retryPolicy di service config) untuk GetUser & ListUsers
+	GRPCMaxRetries int `env:"GRPC_MAX_RETRIES"` // Jumlah retry setelah attempt pertama, 0 = tanpa retry
+
+	// Retry saat membuka stream gRPC (hanya sebelum ada data yang diterima)
+	StreamOpenAttempts int           `env:"STREAM_OPEN_ATTEMPTS"` // Total attempt termasuk yang pertama (1 = tanpa retry)
+	StreamOpenBackoff  time.Duration `env:"STREAM_OPEN_BACKOFF"`  // Jeda sebelum retry pertama, dikali 2 setiap retry
+
+	// Batching flush untuk response streaming (protobuf frames, CSV export)
+	StreamFlushRecords  int           `env:"STREAM_FLUSH_RECORDS"`  // Flush setiap N record (1 = per record)
+	StreamFlushInterval time.Duration `env:"STREAM_FLUSH_INTERVAL"` // ...atau setiap interval ini, mana yang duluan (0 = off)
+
+	// Proteksi slow client untuk response aggregated (JSON GET /users, /users/by-date)
+	AggregateWriteTimeout time.Duration `env:"AGGREGATE_WRITE_TIMEOUT"` // Batas waktu 1 chunk (32KB) terkirim, 0 = off
+	AggregateMaxHold      time.Duration `env:"AGGREGATE_MAX_HOLD"`      // Batas total response selesai terkirim, 0 = off
+
+	// POST /users/resolve: banyak GetUser dalam 1 request (fan-out)
+	ResolveMaxIDs      int `env:"RESOLVE_MAX_IDS"`     // Jumlah id maksimal per request
+	ResolveConcurrency int `env:"RESOLVE_CONCURRENCY"` // GetUser yang jalan bersamaan per request
+
+	// GET /users/by-ids: ditolak di gateway (400) tanpa round trip kalau melebihi batas
+	GetUsersByIDsMax int `env:"GET_USERS_BY_IDS_MAX"` // Jumlah id (unik) maksimal, samakan dengan User Service
+
+	// Client IP & per-client connection limit
+	TrustedProxies    []string `env:"TRUSTED_PROXIES"`      // CIDR/IP proxy yang X-Forwarded-For-nya dipercaya
+	MaxConnsPerClient int      `env:"MAX_CONNS_PER_CLIENT"` // Koneksi/request bersamaan per IP client, 0 = tanpa batas
+
+	// Admin endpoints (bulk delete, debug, dll) — disabled by default
+	AdminEnabled bool   `env:"ADMIN_ENABLED"`
+	AdminToken   string `env:"ADMIN_TOKEN" secret:"true"` // Dikirim client sebagai "Authorization: Bearer <token>"
+
+	// Access log
+	AccessLogFormat string `env:"ACCESS_LOG_FORMAT"` // "json" (default) | "combined"
+
+	// Format log aplikasi (log/slog), terpisah dari access log
+	LogFormat string `env:"LOG_FORMAT"` // "text" (default) | "json"
+
+	// PII redaction di log (email, dll diganti salted hash)
+	PIILogRedaction bool     `env:"PII_LOG_REDACTION"`
+	PIILogSalt      string   `env:"PII_LOG_SALT" secret:"true"` // Wajib kalau redaction aktif
+	PIILogFields    []string `env:"PII_LOG_FIELDS"`             // Nama field/query param PII, contoh: "email,name"
+
+	// Response header filtering
+	ResponseHeaderDenylist  []string `env:"RESPONSE_HEADER_DENYLIST"`  // Header yang dibuang
+	ResponseHeaderAllowlist []string `env:"RESPONSE_HEADER_ALLOWLIST"` // Kalau di-set HANYA header ini yang keluar
+	ServerHeader            string   `env:"SERVER_HEADER"`             // Nilai custom header "Server"
+
+	// CORS untuk browser client: origin yang boleh memanggil gateway, "*" = semua origin
+	// Kosong (default) = CORS disabled, browser hanya bisa memanggil dari origin yang sama
+	// Kalau RESPONSE_HEADER_ALLOWLIST dipakai, header Access-Control-* harus ikut di-allow
+	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS"`
+
+	// TLS ke User Service (wajib, kecuali INSECURE=true untuk development)
+	Insecure          bool   `env:"INSECURE"`             // Izinkan plaintext gRPC kalau TLS_CA_FILE tidak di-set
+	TLSCAFile         string `env:"TLS_CA_FILE"`          // CA untuk verifikasi server cert User Service
+	TLSServerName     string `env:"TLS_SERVER_NAME"`      // Override nama server di cert (SNI)
+	TLSClientCertFile string `env:"TLS_CLIENT_CERT_FILE"` // Client cert gateway untuk mTLS
+	TLSClientKeyFile  string `env:"TLS_CLIENT_KEY_FILE"`  // Path private key client cert (isinya tidak pernah dibaca ke config)
+
+	// Tracing (OpenTelemetry)
+	TraceSampleRate float64 `env:"TRACE_SAMPLE_RATE"`                        // 0.0 - 1.0 (1.0 = semua request)
+	OTLPEndpoint    string  `env:"OTEL_EXPORTER_OTLP_ENDPOINT" secret:"url"` // Kosong = span tidak di-export
+
+	// Metrics
+	MetricsExporters []string `env:"METRICS_EXPORTERS"` // "prometheus" (default), "otlp", atau keduanya
+}
+
+// Load membaca konfigurasi dari environment
+// Return error kalau ada nilai yang tidak valid, supaya salah konfigurasi ketahuan saat startup
+func Load() (*Config, error) {
+	cfg := &Config{}
+	var err error
+
+	if cfg.HTTPPort, err = getPort("HTTP_PORT", 8080); err != nil {
+		return nil, err
+	}
+	cfg.UserServiceAddrs = getList("USER_SERVICE_ADDR", []string{"localhost:50051"})
+	for _, addr := range cfg.UserServiceAddrs {
+		_, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, fmt.Errorf("USER_SERVICE_ADDR entries must look like host:port, got %q", addr)
+		}
+		if _, err = parsePort("USER_SERVICE_ADDR", port); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.StaleWhileDown, err = getBool("STALE_WHILE_DOWN", false); err != nil {
+		return nil, err
+	}
+	if cfg.StaleCacheSize, err = getInt("STALE_CACHE_SIZE", 1000); err != nil {
+		return nil, err
+	}
+
+	if cfg.ResponseCacheTTL, err = getDuration("RESPONSE_CACHE_TTL", 0); err != nil {
+		return nil, err
+	}
+	if cfg.ResponseCacheSize, err = getInt("RESPONSE_CACHE_SIZE", 1000); err != nil {
+		return nil, err
+	}
+
+	if cfg.HedgeDelay, err = getDuration("HEDGE_DELAY", 0); err != nil {
+		return nil, err
+	}
+	if cfg.HedgeMaxAttempts, err = getInt("HEDGE_MAX_ATTEMPTS", 2); err != nil {
+		return nil, err
+	}
+
+	if cfg.SchemaValidation, err = getBool("SCHEMA_VALIDATION", false); err != nil {
+		return nil, err
+	}
+	cfg.SchemaDir = getString("SCHEMA_DIR", "")
+
+	if cfg.JSONMaxDepth, err = getInt("JSON_MAX_DEPTH", 32); err != nil {
+		return nil, err
+	}
+	if cfg.JSONMaxTokens, err = getInt("JSON_MAX_TOKENS", 10000); err != nil {
+		return nil, err
+	}
+	if cfg.JSONEmitDefaults, err = getBool("JSON_EMIT_DEFAULTS", false); err != nil {
+		return nil, err
+	}
+	if cfg.JSONUseProtoNames, err = getBool("JSON_USE_PROTO_NAMES", false); err != nil {
+		return nil, err
+	}
+
+	if cfg.RPCTimeout, err = getDuration("GATEWAY_RPC_TIMEOUT", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.RPCTimeout == 0 {
+		return nil, fmt.Errorf("GATEWAY_RPC_TIMEOUT must be greater than 0")
+	}
+
+	if cfg.GRPCMaxMsgSizeMB, err = getMsgSizeMB("GRPC_MAX_MSG_SIZE_MB", 4); err != nil {
+		return nil, err
+	}
+
+	if cfg.KeepaliveTime, err = getDuration("KEEPALIVE_TIME", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.KeepaliveTime < 10*time.Second {
+		return nil, fmt.Errorf("KEEPALIVE_TIME must be at least 10s (gRPC client minimum), got %s", cfg.KeepaliveTime)
+	}
+	if cfg.KeepaliveTimeout, err = getDuration("KEEPALIVE_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+
+	if cfg.RequireBackendReady, err = getBool("REQUIRE_BACKEND_READY", false); err != nil {
+		return nil, err
+	}
+	// Default: probe hanya jalan kalau diwajibkan; BACKEND_READY_TIMEOUT saja = probe dengan warning
+	defaultReadyTimeout := time.Duration(0)
+	if cfg.RequireBackendReady {
+		defaultReadyTimeout = 10 * time.Second
+	}
+	if cfg.BackendReadyTimeout, err = getDuration("BACKEND_READY_TIMEOUT", defaultReadyTimeout); err != nil {
+		return nil, err
+	}
+	if cfg.RequireBackendReady && cfg.BackendReadyTimeout <= 0 {
+		return nil, fmt.Errorf("BACKEND_READY_TIMEOUT must be positive when REQUIRE_BACKEND_READY=true")
+	}
+
+	if cfg.ShutdownDrainTimeout, err = getDuration("SHUTDOWN_DRAIN_TIMEOUT", 15*time.Second); err != nil {
+		return nil, err
+	}
+
+	if cfg.StreamOpenAttempts, err = getInt("STREAM_OPEN_ATTEMPTS", 3); err != nil {
+		return nil, err
+	}
+	if cfg.StreamOpenAttempts < 1 {
+		return nil, fmt.Errorf("STREAM_OPEN_ATTEMPTS must be at least 1")
+	}
+
+	cfg.GRPCCompression = getString("GRPC_COMPRESSION", "identity")
+	if cfg.GRPCCompression != "identity" && cfg.GRPCCompression != "gzip" {
+		return nil, fmt.Errorf("GRPC_COMPRESSION must be identity or gzip, got %q", cfg.GRPCCompression)
+	}
+
+	if cfg.GRPCMaxRetries, err = getInt("GRPC_MAX_RETRIES", 2); err != nil {
+		return nil, err
+	}
+	// gRPC membatasi maxAttempts di 5 (attempt pertama + 4 retry)
+	if cfg.GRPCMaxRetries > 4 {
+		return nil, fmt.Errorf("GRPC_MAX_RETRIES must be at most 4, got %d", cfg.GRPCMaxRetries)
+	}
+	if cfg.StreamOpenBackoff, err = getDuration("STREAM_OPEN_BACKOFF", 100*time.Millisecond); err != nil {
+		return nil, err
+	}
+
+	if cfg.StreamFlushRecords, err = getInt("STREAM_FLUSH_RECORDS", 32); err != nil {
+		return nil, err
+	}
+	if cfg.StreamFlushRecords < 1 {
+		return nil, fmt.Errorf("STREAM_FLUSH_RECORDS must be at least 1")
+	}
+	if cfg.StreamFlushInterval, err = getDuration("STREAM_FLUSH_INTERVAL", 100*time.Millisecond); err != nil {
+		return nil, err
+	}
+
+	if cfg.AggregateWriteTimeout, err = getDuration("AGGREGATE_WRITE_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.AggregateMaxHold, err = getDuration("AGGREGATE_MAX_HOLD", 30*time.Second); err != nil {
+		return nil, err
+	}
+
+	if cfg.ResolveMaxIDs, err = getInt("RESOLVE_MAX_IDS", 100); err != nil {
+		return nil, err
+	}
+	if cfg.ResolveMaxIDs < 1 {
+		return nil, fmt.Errorf("RESOLVE_MAX_IDS must be at least 1")
+	}
+	if cfg.ResolveConcurrency, err = getInt("RESOLVE_CONCURRENCY", 8); err != nil {
+		return nil, err
+	}
+	if cfg.ResolveConcurrency < 1 {
+		return nil, fmt.Errorf("RESOLVE_CONCURRENCY must be at least 1")
+	}
+
+	if cfg.GetUsersByIDsMax, err = getInt("GET_USERS_BY_IDS_MAX", 100); err != nil {
+		return nil, err
+	}
+	if cfg.GetUsersByIDsMax < 1 {
+		return nil, fmt.Errorf("GET_USERS_BY_IDS_MAX must be at least 1")
+	}
+
+	cfg.TrustedProxies = getList("TRUSTED_PROXIES", nil)
+	if cfg.MaxConnsPerClient, err = getInt("MAX_CONNS_PER_CLIENT", 0); err != nil {
+		return nil, err
+	}
+
+	if cfg.AdminEnabled, err = getBool("ADMIN_ENABLED", false); err != nil {
+		return nil, err
+	}
+	cfg.AdminToken = getString("ADMIN_TOKEN", "")
+	if cfg.AdminEnabled && cfg.AdminToken == "" {
+		return nil, fmt.Errorf("ADMIN_TOKEN is required when ADMIN_ENABLED=true")
+	}
+
+	cfg.AccessLogFormat = getString("ACCESS_LOG_FORMAT", "json")
+	if cfg.AccessLogFormat != "json" && cfg.AccessLogFormat != "combined" {
+		return nil, fmt.Errorf("ACCESS_LOG_FORMAT must be json or combined, got %q", cfg.AccessLogFormat)
+	}
+
+	cfg.LogFormat = getString("LOG_FORMAT", "text")
+	if cfg.LogFormat != "text" && cfg.LogFormat != "json" {
+		return nil, fmt.Errorf("LOG_FORMAT must be text or json, got %q", cfg.LogFormat)
+	}
+
+	if cfg.PIILogRedaction, err = getBool("PII_LOG_REDACTION", false); err != nil {
+		return nil, err
+	}
+	cfg.PIILogSalt = getString("PII_LOG_SALT", "")
+	cfg.PIILogFields = getList("PII_LOG_FIELDS", []string{"email"})
+	if cfg.PIILogRedaction && cfg.PIILogSalt == "" {
+		return nil, fmt.Errorf("PII_LOG_SALT is required when PII_LOG_REDACTION=true")
+	}
+
+	cfg.ResponseHeaderDenylist = getList("RESPONSE_HEADER_DENYLIST", []string{
+		"Server", "X-Powered-By", "Traceparent", "Tracestate", "X-Upstream-Addr",
+	})
+	cfg.ResponseHeaderAllowlist = getList("RESPONSE_HEADER_ALLOWLIST", nil)
+	cfg.ServerHeader = getString("SERVER_HEADER", "")
+
+	cfg.CORSAllowedOrigins = getList("CORS_ALLOWED_ORIGINS", nil)
+	for _, origin := range cfg.CORSAllowedOrigins {
+		if err := validateOrigin(origin); err != nil {
+			return nil, fmt.Errorf("CORS_ALLOWED_ORIGINS: %w", err)
+		}
+	}
+
+	cfg.TLSCAFile = getString("TLS_CA_FILE", "")
+	cfg.TLSServerName = getString("TLS_SERVER_NAME", "")
+	cfg.TLSClientCertFile = getString("TLS_CLIENT_CERT_FILE", "")
+	cfg.TLSClientKeyFile = getString("TLS_CLIENT_KEY_FILE", "")
+	if (cfg.TLSClientCertFile == "") != (cfg.TLSClientKeyFile == "") {
+		return nil, fmt.Errorf("TLS_CLIENT_CERT_FILE and TLS_CLIENT_KEY_FILE must be set together")
+	}
+	if cfg.TLSClientCertFile != "" && cfg.TLSCAFile == "" {
+		return nil, fmt.Errorf("TLS_CLIENT_CERT_FILE requires TLS_CA_FILE (client certs are only sent over TLS)")
+	}
+	for _, f := range []struct{ key, path string }{
+		{"TLS_CA_FILE", cfg.TLSCAFile},
+		{"TLS_CLIENT_CERT_FILE", cfg.TLSClientCertFile},
+		{"TLS_CLIENT_KEY_FILE", cfg.TLSClientKeyFile},
+	} {
+		if err := requireFile(f.key, f.path); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Insecure, err = getBool("INSECURE", false); err != nil {
+		return nil, err
+	}
+	if cfg.TLSCAFile == "" && !cfg.Insecure {
+		return nil, fmt.Errorf("TLS_CA_FILE is required (set INSECURE=true to talk plaintext gRPC to User Service for local development)")
+	}
+
+	if cfg.TraceSampleRate, err = getRatio("TRACE_SAMPLE_RATE", 1.0); err != nil {
+		return nil, err
+	}
+	cfg.OTLPEndpoint = getString("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	cfg.MetricsExporters = getList("METRICS_EXPORTERS", []string{"prometheus"})
+
+	return cfg, nil
+}
+
+// validateOrigin cek 1 entry CORS_ALLOWED_ORIGINS: "*" atau scheme://host[:port] tanpa path
+// Browser mengirim header Origin persis dalam bentuk itu, jadi "https://app.com/" tidak akan pernah cocok
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") ||
+		u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return fmt.Errorf("origin must look like https://host[:port], got %q", origin)
+	}
+	return nil
+}
+
+// requireFile cek file yang dirujuk env var benar-benar ada (path kosong = tidak dipakai, dilewati)
+// Supaya typo path ketahuan saat startup dengan pesan yang jelas, bukan saat koneksi pertama
+func requireFile(key, path string) error {
+	if path == "" {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("%s: cannot read %q: %w", key, path, err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s: %q is a directory, expected a PEM file", key, path)
+	}
+	return nil
+}
+
+// getString ambil env var, atau fallback kalau kosong
+func getString(key, fallback string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// getBool parse env var sebagai boolean ("true", "1", "false", "0", dll)
+func getBool(key string, fallback bool) (bool, error) {
+	raw := getString(key, "")
+	if raw == "" {
+		return fallback, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("%s must be a boolean, got %q", key, raw)
+	}
+	return v, nil
+}
+
+// getInt parse env var sebagai integer non-negatif
+func getInt(key string, fallback int) (int, error) {
+	raw := getString(key, "")
+	if raw == "" {
+		return fallback, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", key, raw)
+	}
+	return v, nil
+}
+
+// getPort parse env var sebagai port TCP (1 - 65535)
+func getPort(key string, fallback int) (int, error) {
+	raw := getString(key, "")
+	if raw == "" {
+		return fallback, nil
+	}
+	return parsePort(key, raw)
+}
+
+// parsePort validasi string port, key hanya dipakai untuk pesan error
+func parsePort(key, raw string) (int, error) {
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 1 || v > 65535 {
+		return 0, fmt.Errorf("%s must be a port number between 1 and 65535, got %q", key, raw)
+	}
+	return v, nil
+}
+
+// getMsgSizeMB parse env var sebagai ukuran message dalam MB (1 - 2047, batas int32 gRPC)
+func getMsgSizeMB(key string, fallback int) (int, error) {
+	v, err := getInt(key, fallback)
+	if err != nil {
+		return 0, err
+	}
+	if v < 1 || v > 2047 {
+		return 0, fmt.Errorf("%s must be between 1 and 2047, got %d", key, v)
+	}
+	return v, nil
+}
+
+// getRatio parse env var sebagai float di range 0.0 - 1.0 (contoh: "0.1" = 10%)
+func getRatio(key string, fallback float64) (float64, error) {
+	raw := getString(key, "")
+	if raw == "" {
+		return fallback, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || v < 0 || v > 1 {
+		return 0, fmt.Errorf("%s must be a number between 0 and 1, got %q", key, raw)
+	}
+	return v, nil
+}
+
+// getList parse env var comma-separated menjadi slice (spasi di-trim, item kosong dibuang)
+func getList(key string, fallback []string) []string {
+	raw := getString(key, "")
+	if raw == "" {
+		return fallback
+	}
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// getDuration parse env var sebagai time.Duration (contoh: "500ms", "2s")
+func getDuration(key string, fallback time.Duration) (time.Duration, error) {
+	raw := getString(key, "")
+	if raw == "" {
+		return fallback, nil
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration (e.g. 2s), got %q", key, raw)
+	}
+	return v, nil
+}
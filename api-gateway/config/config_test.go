@@ -0,0 +1,144 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setEnv mengisi env var untuk 1 test (kosong = dianggap tidak di-set, lihat getString)
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{"HTTP_PORT", "USER_SERVICE_ADDR"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("INSECURE", "true")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+}
+
+func TestLoadAddressDefaults(t *testing.T) {
+	setEnv(t, nil)
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.HTTPPort != 8080 {
+		t.Errorf("HTTPPort = %d, want 8080", cfg.HTTPPort)
+	}
+	if want := []string{"localhost:50051"}; !reflect.DeepEqual(cfg.UserServiceAddrs, want) {
+		t.Errorf("UserServiceAddrs = %v, want %v", cfg.UserServiceAddrs, want)
+	}
+}
+
+func TestLoadAddressOverrides(t *testing.T) {
+	setEnv(t, map[string]string{"HTTP_PORT": "9090", "USER_SERVICE_ADDR": "user-a:50051, user-b:50052"})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.HTTPPort != 9090 {
+		t.Errorf("HTTPPort = %d, want 9090", cfg.HTTPPort)
+	}
+	if want := []string{"user-a:50051", "user-b:50052"}; !reflect.DeepEqual(cfg.UserServiceAddrs, want) {
+		t.Errorf("UserServiceAddrs = %v, want %v", cfg.UserServiceAddrs, want)
+	}
+}
+
+func TestLoadRejectsInvalidAddresses(t *testing.T) {
+	tests := []struct {
+		key, value string
+	}{
+		{"HTTP_PORT", "http"},
+		{"HTTP_PORT", "0"},
+		{"HTTP_PORT", "65536"},
+		{"USER_SERVICE_ADDR", "localhost"},
+		{"USER_SERVICE_ADDR", "localhost:abc"},
+		{"USER_SERVICE_ADDR", "user-a:50051,user-b:70000"},
+	}
+	for _, tt := range tests {
+		setEnv(t, map[string]string{tt.key: tt.value})
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), tt.key) {
+			t.Errorf("%s=%q: err = %v, want error naming %s", tt.key, tt.value, err, tt.key)
+		}
+	}
+}
+
+func TestLoadCORSAllowedOrigins(t *testing.T) {
+	setEnv(t, map[string]string{"CORS_ALLOWED_ORIGINS": "https://app.example.com, http://localhost:3000"})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if want := []string{"https://app.example.com", "http://localhost:3000"}; !reflect.DeepEqual(cfg.CORSAllowedOrigins, want) {
+		t.Fatalf("CORSAllowedOrigins = %v, want %v", cfg.CORSAllowedOrigins, want)
+	}
+
+	// Origin dengan path / tanpa scheme tidak akan pernah sama dengan header Origin dari browser
+	for _, origin := range []string{"https://app.example.com/", "app.example.com", "ftp://app.example.com"} {
+		setEnv(t, map[string]string{"CORS_ALLOWED_ORIGINS": origin})
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "CORS_ALLOWED_ORIGINS") {
+			t.Fatalf("origin %q: err = %v, want CORS_ALLOWED_ORIGINS error", origin, err)
+		}
+	}
+}
+
+func TestLoadBackendReadyProbe(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		wantRequire bool
+		wantTimeout time.Duration
+	}{
+		{"disabled by default", nil, false, 0},
+		{"required uses default timeout", map[string]string{"REQUIRE_BACKEND_READY": "true"}, true, 10 * time.Second},
+		{"warning-only probe", map[string]string{"BACKEND_READY_TIMEOUT": "3s"}, false, 3 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.RequireBackendReady != tt.wantRequire || cfg.BackendReadyTimeout != tt.wantTimeout {
+				t.Fatalf("RequireBackendReady = %v, BackendReadyTimeout = %s; want %v, %s",
+					cfg.RequireBackendReady, cfg.BackendReadyTimeout, tt.wantRequire, tt.wantTimeout)
+			}
+		})
+	}
+
+	setEnv(t, map[string]string{"REQUIRE_BACKEND_READY": "true", "BACKEND_READY_TIMEOUT": "0s"})
+	if _, err := Load(); err == nil {
+		t.Fatal("REQUIRE_BACKEND_READY with zero timeout: want error")
+	}
+}
+
+func TestLoadGetUsersByIDsMax(t *testing.T) {
+	setEnv(t, map[string]string{"GET_USERS_BY_IDS_MAX": ""})
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GetUsersByIDsMax != 100 {
+		t.Fatalf("default GetUsersByIDsMax = %d, want 100", cfg.GetUsersByIDsMax)
+	}
+
+	setEnv(t, map[string]string{"GET_USERS_BY_IDS_MAX": "25"})
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GetUsersByIDsMax != 25 {
+		t.Fatalf("GetUsersByIDsMax = %d, want 25", cfg.GetUsersByIDsMax)
+	}
+
+	for _, raw := range []string{"0", "lots"} {
+		setEnv(t, map[string]string{"GET_USERS_BY_IDS_MAX": raw})
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "GET_USERS_BY_IDS_MAX") {
+			t.Fatalf("GET_USERS_BY_IDS_MAX=%q: err = %v, want error naming GET_USERS_BY_IDS_MAX", raw, err)
+		}
+	}
+}
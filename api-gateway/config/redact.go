@@ -0,0 +1,66 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+)
+
+// redactedValue adalah pengganti nilai rahasia di output
+const redactedValue = "[REDACTED]"
+
+// Redacted mengembalikan konfigurasi efektif sebagai map env-name → value,
+// dengan semua field bertanda secret sudah di-redact
+// Aman untuk ditampilkan di endpoint debug atau log
+func (c *Config) Redacted() map[string]interface{} {
+	out := make(map[string]interface{})
+
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("env")
+		if key == "" {
+			key = field.Name
+		}
+		out[key] = redact(v.Field(i).Interface(), field.Tag.Get("secret"))
+	}
+	return out
+}
+
+// redact menerapkan aturan tag secret ke 1 nilai
+func redact(value interface{}, mode string) interface{} {
+	switch mode {
+	case "true":
+		// Kosong tetap kosong, supaya kelihatan kalau secret BELUM di-set
+		if s, ok := value.(string); ok && s == "" {
+			return ""
+		}
+		return redactedValue
+	case "url":
+		s, ok := value.(string)
+		if !ok {
+			return redactedValue
+		}
+		return redactURLPassword(s)
+	default:
+		return value
+	}
+}
+
+// redactURLPassword hanya menyembunyikan password di URL/DSN
+// contoh: postgres://app:s3cret@db:5432/users → postgres://app:[REDACTED]@db:5432/users
+func redactURLPassword(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		// Tidak bisa di-parse → jangan ambil risiko, redact semua
+		return redactedValue
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+		// url.String() meng-escape "[" dan "]", jadi decode supaya tetap terbaca
+		if out, err := url.PathUnescape(u.String()); err == nil {
+			return out
+		}
+	}
+	return raw
+}
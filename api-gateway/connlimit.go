@@ -0,0 +1,104 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// clientCounter menghitung koneksi/request yang sedang aktif per IP
+type clientCounter struct {
+	mu     sync.Mutex
+	limit  int
+	active map[string]int
+}
+
+func newClientCounter(limit int) *clientCounter {
+	return &clientCounter{limit: limit, active: make(map[string]int)}
+}
+
+// acquire return false kalau IP tersebut sudah mencapai limit
+func (c *clientCounter) acquire(ip string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.active[ip] >= c.limit {
+		return false
+	}
+	c.active[ip]++
+	return true
+}
+
+func (c *clientCounter) release(ip string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.active[ip] <= 1 {
+		delete(c.active, ip) // Jangan biarkan map tumbuh untuk IP yang sudah pergi
+		return
+	}
+	c.active[ip]--
+}
+
+// limitListener membatasi koneksi TCP bersamaan per IP peer (MAX_CONNS_PER_CLIENT)
+// Koneksi dari trusted proxy TIDAK dibatasi di sini (1 load balancer membawa banyak client);
+// client di belakang proxy dibatasi per request oleh limitPerClient
+type limitListener struct {
+	net.Listener
+	counter  *clientCounter
+	resolver *ipResolver
+}
+
+func (l *limitListener) Accept() (net.Conn, error) {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		ip := remoteHost(conn.RemoteAddr().String())
+		if l.resolver.isTrusted(ip) {
+			return conn, nil
+		}
+		if !l.counter.acquire(ip) {
+			log.Printf("🚫 Connection from %s rejected: more than %d concurrent connections", ip, l.counter.limit)
+			conn.Close()
+			continue
+		}
+		return &countedConn{Conn: conn, release: func() { l.counter.release(ip) }}, nil
+	}
+}
+
+// countedConn melepas slot koneksi tepat 1 kali saat koneksi ditutup
+type countedConn struct {
+	net.Conn
+	once    sync.Once
+	release func()
+}
+
+func (c *countedConn) Close() error {
+	c.once.Do(c.release)
+	return c.Conn.Close()
+}
+
+// limitPerClient membatasi request bersamaan per IP client yang datang lewat trusted proxy
+// IP client di-resolve dari X-Forwarded-For (lihat ipResolver), over limit → 429
+func limitPerClient(counter *clientCounter, resolver *ipResolver, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !resolver.isTrusted(remoteHost(r.RemoteAddr)) {
+			next.ServeHTTP(w, r) // Koneksi langsung sudah dibatasi di limitListener
+			return
+		}
+
+		ip := resolver.clientIP(r)
+		if !counter.acquire(ip) {
+			log.Printf("🚫 Request from %s rejected: more than %d concurrent requests via proxy", ip, counter.limit)
+			http.Error(w, "too many concurrent connections from this client", http.StatusTooManyRequests)
+			return
+		}
+		defer counter.release(ip)
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLimitListenerRejectsExcessConnections(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver, _ := newIPResolver(nil)
+	limited := &limitListener{Listener: lis, counter: newClientCounter(2), resolver: resolver}
+	defer limited.Close()
+
+	accepted := make(chan net.Conn, 10)
+	go func() {
+		for {
+			conn, err := limited.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	dial := func() net.Conn {
+		t.Helper()
+		conn, err := net.Dial("tcp", lis.Addr().String())
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	waitAccepted := func() net.Conn {
+		t.Helper()
+		select {
+		case conn := <-accepted:
+			return conn
+		case <-time.After(time.Second):
+			t.Fatal("connection within limit was not accepted")
+			return nil
+		}
+	}
+
+	dial()
+	dial()
+	first := waitAccepted()
+	waitAccepted()
+
+	// Koneksi ke-3 dari IP yang sama ditutup oleh server
+	third := dial()
+	third.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := third.Read(make([]byte, 1)); err == nil {
+		t.Fatal("third connection was not closed by the server")
+	}
+	select {
+	case <-accepted:
+		t.Fatal("connection over the limit was handed to the server")
+	default:
+	}
+
+	// Slot kembali setelah 1 koneksi ditutup
+	first.Close()
+	dial()
+	waitAccepted()
+}
+
+func TestLimitPerClientBehindTrustedProxy(t *testing.T) {
+	resolver, err := newIPResolver([]string{"192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	entered := make(chan struct{}, 1)
+	handler := limitPerClient(newClientCounter(1), resolver, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Block") != "" {
+			entered <- struct{}{}
+			<-release
+		}
+	}))
+
+	request := func(clientIP string, block bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		req.RemoteAddr = "192.0.2.1:4000"
+		req.Header.Set("X-Forwarded-For", clientIP)
+		if block {
+			req.Header.Set("X-Block", "1")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	done := make(chan struct{})
+	go func() {
+		request("203.0.113.5", true)
+		close(done)
+	}()
+	<-entered
+
+	if rec := request("203.0.113.5", false); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second concurrent request from same client = %d, want 429", rec.Code)
+	}
+	if rec := request("203.0.113.6", false); rec.Code != http.StatusOK {
+		t.Fatalf("request from another client behind the proxy = %d, want 200", rec.Code)
+	}
+
+	close(release)
+	<-done
+	if rec := request("203.0.113.5", false); rec.Code != http.StatusOK {
+		t.Fatalf("request after the first finished = %d, want 200", rec.Code)
+	}
+}
+
+func TestClientIPResolution(t *testing.T) {
+	resolver, err := newIPResolver([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, remoteAddr, xff, want string
+	}{
+		{"direct client", "203.0.113.5:1234", "", "203.0.113.5"},
+		{"spoofed XFF from untrusted peer", "203.0.113.5:1234", "1.2.3.4", "203.0.113.5"},
+		{"via trusted proxy", "192.0.2.1:1234", "203.0.113.7", "203.0.113.7"},
+		{"rightmost untrusted hop", "192.0.2.1:1234", "1.2.3.4, 203.0.113.7, 10.1.1.1", "203.0.113.7"},
+		{"all hops trusted", "192.0.2.1:1234", "10.1.1.1", "192.0.2.1"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.xff != "" {
+			req.Header.Set("X-Forwarded-For", tt.xff)
+		}
+		if got := resolver.clientIP(req); got != tt.want {
+			t.Errorf("%s: clientIP = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if _, err := newIPResolver([]string{"not-an-ip"}); err == nil {
+		t.Fatal("invalid trusted proxy: want error")
+	}
+}
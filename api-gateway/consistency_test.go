@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// consistencyBackend mencatat metadata "consistency" dari setiap GetUser
+type consistencyBackend struct {
+	pb.UnimplementedUserServiceServer
+	mu   sync.Mutex
+	seen []string
+}
+
+func (b *consistencyBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	b.mu.Lock()
+	b.seen = append(b.seen, strings.Join(md.Get("consistency"), ","))
+	b.mu.Unlock()
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func TestGetUserNoCacheRequestsStrongConsistency(t *testing.T) {
+	backend := &consistencyBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", http.Header{"Cache-Control": {"no-cache"}}); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if len(backend.seen) != 2 || backend.seen[0] != "" || backend.seen[1] != "strong" {
+		t.Fatalf("consistency metadata = %q, want [\"\" \"strong\"]", backend.seen)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsAllowedMethods adalah method yang dipakai endpoint gateway
+var corsAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+
+const (
+	// Header request yang boleh dikirim browser (dicek browser sendiri dari hasil preflight)
+	corsAllowedHeaders = "Authorization, Content-Type, X-API-Key, X-User-Authorization"
+	// Header response yang boleh dibaca JavaScript (selain header "simple" seperti Content-Type)
+	corsExposedHeaders = "X-Request-Id"
+	// Browser boleh cache hasil preflight selama ini (detik), supaya tidak ada OPTIONS per request
+	corsMaxAge = "600"
+)
+
+// corsPolicy menyimpan origin yang boleh memanggil gateway dari browser (CORS_ALLOWED_ORIGINS)
+type corsPolicy struct {
+	allowAll bool
+	origins  map[string]bool
+}
+
+func newCORSPolicy(origins []string) *corsPolicy {
+	p := &corsPolicy{origins: make(map[string]bool, len(origins))}
+	for _, origin := range origins {
+		if origin == "*" {
+			p.allowAll = true
+		}
+		p.origins[origin] = true
+	}
+	return p
+}
+
+// allowOrigin return nilai Access-Control-Allow-Origin untuk origin ini, kosong = tidak diizinkan
+func (p *corsPolicy) allowOrigin(origin string) string {
+	if p.allowAll {
+		return "*"
+	}
+	if p.origins[origin] {
+		return origin
+	}
+	return ""
+}
+
+// isPreflight: request OPTIONS yang dikirim browser sebelum request "non-simple"
+// (contoh: PUT, atau POST dengan Content-Type: application/json)
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// cors adalah middleware CORS di depan router
+//  1. Request tanpa header Origin (curl, service lain) diteruskan apa adanya
+//  2. Preflight dijawab langsung di sini (204, atau 403 kalau origin tidak diizinkan),
+//     jadi tidak pernah sampai ke handler / requireAdmin
+//  3. Request biasa dari origin yang diizinkan mendapat Access-Control-Allow-Origin;
+//     dari origin lain tetap diproses, tapi browser tidak akan memberikan response-nya ke JavaScript
+//
+// Credentials mode (cookie) tidak didukung: gateway memakai header Authorization (Bearer token)
+func cors(policy *corsPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowed := policy.allowOrigin(origin)
+		h := w.Header()
+		if !policy.allowAll {
+			// Response berbeda per Origin, jadi cache (browser/CDN) harus membedakannya
+			h.Add("Vary", "Origin")
+		}
+
+		if isPreflight(r) {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			if allowed == "" || !slices.Contains(corsAllowedMethods, r.Header.Get("Access-Control-Request-Method")) {
+				http.Error(w, "CORS preflight rejected", http.StatusForbidden)
+				return
+			}
+			h.Set("Access-Control-Allow-Origin", allowed)
+			h.Set("Access-Control-Allow-Methods", strings.Join(corsAllowedMethods, ", "))
+			h.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			h.Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if allowed != "" {
+			h.Set("Access-Control-Allow-Origin", allowed)
+			h.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
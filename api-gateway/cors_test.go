@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// corsRequest menjalankan 1 request lewat middleware cors; next mencatat apakah dipanggil
+func corsRequest(t *testing.T, origins []string, req *http.Request) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	reached := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	cors(newCORSPolicy(origins), next).ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func preflightRequest(origin, method string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/users/u1", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	return req
+}
+
+func TestCORSPreflightAllowedOrigin(t *testing.T) {
+	rec, reached := corsRequest(t, []string{"https://app.example.com"}, preflightRequest("https://app.example.com", http.MethodPut))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if reached {
+		t.Fatal("preflight reached the router")
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST, PUT, DELETE",
+		"Access-Control-Allow-Headers": corsAllowedHeaders,
+		"Access-Control-Max-Age":       corsMaxAge,
+	}
+	for header, value := range want {
+		if got := rec.Header().Get(header); got != value {
+			t.Fatalf("%s = %q, want %q", header, got, value)
+		}
+	}
+	if vary := rec.Header().Values("Vary"); len(vary) == 0 || vary[0] != "Origin" {
+		t.Fatalf("Vary = %v, want Origin first", vary)
+	}
+}
+
+func TestCORSPreflightRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{"disallowed origin", preflightRequest("https://evil.example.com", http.MethodPut)},
+		{"disallowed method", preflightRequest("https://app.example.com", http.MethodPatch)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, reached := corsRequest(t, []string{"https://app.example.com"}, tt.req)
+			if rec.Code != http.StatusForbidden || reached {
+				t.Fatalf("status = %d, reached router = %v; want 403 without reaching router", rec.Code, reached)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Fatalf("Access-Control-Allow-Origin = %q on rejected preflight", got)
+			}
+		})
+	}
+}
+
+func TestCORSSimpleRequests(t *testing.T) {
+	origins := []string{"https://app.example.com"}
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec, reached := corsRequest(t, origins, req)
+	if !reached || rec.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || rec.Header().Get("Access-Control-Expose-Headers") != corsExposedHeaders {
+		t.Fatalf("allowed origin: reached = %v, headers = %v", reached, rec.Header())
+	}
+
+	// Origin lain tetap diproses, tapi tanpa header CORS browser tidak memberikan response ke JavaScript
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec, reached = corsRequest(t, origins, req)
+	if !reached || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Fatalf("disallowed origin: reached = %v, Access-Control-Allow-Origin = %q", reached, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+
+	// Tanpa Origin (curl, service lain): tidak ada header CORS sama sekali
+	rec, reached = corsRequest(t, origins, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if !reached || len(rec.Header().Values("Vary")) != 0 {
+		t.Fatalf("no origin: reached = %v, headers = %v", reached, rec.Header())
+	}
+}
+
+func TestCORSWildcard(t *testing.T) {
+	rec, _ := corsRequest(t, []string{"*"}, preflightRequest("https://anything.example.com", http.MethodDelete))
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Fatalf("status = %d, Access-Control-Allow-Origin = %q; want 204 and *", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if vary := rec.Header().Values("Vary"); len(vary) > 0 && vary[0] == "Origin" {
+		t.Fatalf("Vary = %v, wildcard response does not depend on Origin", vary)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	pb "api-gateway/proto/user"
+)
+
+// CountUsersHandler menghandle GET /users/count?status=active
+// Return {"count": N} tanpa harus stream semua user lewat GET /users
+// status opsional (case-insensitive seperti di body create/update), kosong = semua status
+// Filter pencarian sama dengan GET /users (name_contains, email_domain, min_age, max_age)
+func (gw *APIGateway) CountUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE FILTER
+	userStatus, err := parseUserStatus(r.URL.Query().Get("status"))
+	if err != nil {
+		enumErr := err.(*enumError)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+			"field": enumErr.Field,
+			"valid": enumErr.Valid,
+		})
+		return
+	}
+	filter, err := parseUserFilterQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 3. CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD (Unary RPC)
+	resp, err := gw.userClient.CountUsers(ctx, &pb.CountUsersRequest{
+		Status:       userStatus,
+		NameContains: filter.NameContains,
+		EmailDomain:  filter.EmailDomain,
+		MinAge:       filter.MinAge,
+		MaxAge:       filter.MaxAge,
+	})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("🔢 Counted %d users (status: %s)", resp.Count, userStatus)
+
+	// 5. RETURN RESPONSE
+	// Ditulis manual (bukan writeProtoJSON): proto JSON mapping menulis int64 sebagai
+	// string ("5"), sedangkan dashboard butuh angka biasa
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]int64{"count": resp.Count})
+}
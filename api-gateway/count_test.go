@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+// countBackend: CountUsers return jumlah tetap dan mencatat request terakhir
+type countBackend struct {
+	pb.UnimplementedUserServiceServer
+	count int64
+	last  chan *pb.CountUsersRequest
+}
+
+func (b *countBackend) CountUsers(ctx context.Context, req *pb.CountUsersRequest) (*pb.CountUsersResponse, error) {
+	b.last <- req
+	return &pb.CountUsersResponse{Count: b.count}, nil
+}
+
+func TestCountUsersEndpoint(t *testing.T) {
+	backend := &countBackend{count: 42, last: make(chan *pb.CountUsersRequest, 1)}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/count?status=Active", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	// Angka JSON biasa, bukan string int64 ala protojson
+	if got := rec.Body.String(); got != "{\"count\":42}\n" {
+		t.Fatalf("body = %q, want {\"count\":42}", got)
+	}
+	if req := <-backend.last; req.Status != pb.UserStatus_USER_STATUS_ACTIVE {
+		t.Fatalf("forwarded status = %v, want USER_STATUS_ACTIVE", req.Status)
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/users/count?status=banned", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status: HTTP %d, want 400", rec.Code)
+	}
+}
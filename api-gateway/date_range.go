@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dateRangeCursor adalah isi page_token /users/by-date: posisi user terakhir halaman sebelumnya
+// RPC ListUsersByDateRange tidak punya page_token, jadi cursor dikelola gateway
+// (urutan server: created_at lalu id, sama dengan cursor ini)
+type dateRangeCursor struct {
+	CreatedAt time.Time `json:"createdAt"` // RFC3339Nano
+	ID        string    `json:"id"`
+}
+
+// errPageFull menghentikan pembacaan stream begitu 1 halaman (+1 penanda hasMore) terkumpul
+var errPageFull = errors.New("page full")
+
+// encodeDateRangeToken membuat page_token dari user terakhir di halaman
+func encodeDateRangeToken(last *pb.User) string {
+	raw, _ := json.Marshal(dateRangeCursor{CreatedAt: last.CreatedAt.AsTime(), ID: last.Id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeDateRangeToken membaca page_token dari encodeDateRangeToken
+func decodeDateRangeToken(token string) (*dateRangeCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	var cursor dateRangeCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.ID == "" {
+		return nil, errors.New("missing id")
+	}
+	return &cursor, nil
+}
+
+// after cek apakah user berada SETELAH posisi cursor (created_at lalu id)
+func (c *dateRangeCursor) after(user *pb.User) bool {
+	createdAt := user.CreatedAt.AsTime()
+	if !createdAt.Equal(c.CreatedAt) {
+		return createdAt.After(c.CreatedAt)
+	}
+	return user.Id > c.ID
+}
+
+// ListUsersByDateHandler menghandle GET /users/by-date?from=...&to=...&limit=10&page_token=...
+// from & to dalam format RFC3339 (contoh: 2024-01-01T00:00:00Z), keduanya inklusif
+// page_token = meta.nextPageToken dari halaman sebelumnya, dengan from & to yang sama
+func (gw *APIGateway) ListUsersByDateHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE RANGE & PAGE SIZE
+	from, err := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	if err != nil {
+		http.Error(w, "from must be an RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	to, err := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if err != nil {
+		http.Error(w, "to must be an RFC3339 timestamp", http.StatusBadRequest)
+		return
+	}
+	pageSize, err := parsePageSize(r)
+	if err != nil {
+		http.Error(w, "limit must be a positive integer", http.StatusBadRequest)
+		return
+	}
+	var cursor *dateRangeCursor
+	if token := r.URL.Query().Get("page_token"); token != "" {
+		cursor, err = decodeDateRangeToken(token)
+		if err != nil || cursor.CreatedAt.Before(from) || cursor.CreatedAt.After(to) {
+			http.Error(w, "invalid page_token", http.StatusBadRequest)
+			return
+		}
+	}
+
+	log.Printf("📥 Received ListUsersByDateRange request (%s - %s)", from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	// 3. CONTEXT dengan TIMEOUT (streaming)
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), 30*time.Second)
+	defer cancel()
+
+	// 4. CALL gRPC STREAMING METHOD (minta 1 lebih untuk penanda hasMore)
+	// Dengan cursor: mulai dari created_at cursor (inklusif) tanpa limit, karena jumlah user
+	// dengan created_at sama yang harus dilewati tidak diketahui; stream dihentikan begitu
+	// halaman penuh (cancel ctx)
+	// Validasi range di server (from > to) muncul di Recv pertama, yang dibaca openUserStream
+	req := &pb.DateRangeRequest{
+		From:  timestamppb.New(from),
+		To:    timestamppb.New(to),
+		Limit: int32(pageSize + 1),
+	}
+	if cursor != nil {
+		req.From, req.Limit = timestamppb.New(cursor.CreatedAt), 0
+	}
+	stream, err := gw.openUserStream(ctx, func(ctx context.Context) (userStream, error) {
+		return gw.userClient.ListUsersByDateRange(ctx, req)
+	})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	// 5. RECEIVE STREAM
+	var users []*pb.User
+	err = recvUsers(stream, func(user *pb.User) error {
+		if cursor != nil && !cursor.after(user) {
+			return nil // Sudah tersaji di halaman sebelumnya
+		}
+		users = append(users, user)
+		if len(users) > pageSize {
+			return errPageFull
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errPageFull) {
+		log.Printf("❌ Stream error: %v", err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	var nextPageToken string
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		nextPageToken = encodeDateRangeToken(users[pageSize-1])
+	}
+
+	// 6. RETURN COLLECTION
+	writeCollectionGuarded(w, r, gw.slowClientPolicy(), "users", protoValues(users), buildPageMeta(len(users), pageSize, nextPageToken, false))
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// dateRangeBackend meniru validasi range User Service dan mencatat request terakhir
+type dateRangeBackend struct {
+	pb.UnimplementedUserServiceServer
+	last *pb.DateRangeRequest
+}
+
+func (b *dateRangeBackend) ListUsersByDateRange(req *pb.DateRangeRequest, stream grpc.ServerStreamingServer[pb.UserResponse]) error {
+	b.last = req
+	if req.From.AsTime().After(req.To.AsTime()) {
+		return status.Error(codes.InvalidArgument, "from must be before or equal to to")
+	}
+	return stream.Send(&pb.UserResponse{User: &pb.User{Id: "u1"}})
+}
+
+func TestListUsersByDateHandler(t *testing.T) {
+	backend := &dateRangeBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/by-date?from=2024-01-01T00:00:00Z&to=2024-01-31T23:59:59Z", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	wantFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	wantTo := time.Date(2024, 1, 31, 23, 59, 59, 0, time.UTC)
+	if !backend.last.From.AsTime().Equal(wantFrom) || !backend.last.To.AsTime().Equal(wantTo) {
+		t.Fatalf("forwarded range = %s - %s", backend.last.From.AsTime(), backend.last.To.AsTime())
+	}
+
+	// from > to ditolak User Service dengan InvalidArgument → 400
+	if rec := doRequest(router, http.MethodGet, "/users/by-date?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("from > to: status = %d, want 400", rec.Code)
+	}
+	for _, target := range []string{"/users/by-date?to=2024-01-01T00:00:00Z", "/users/by-date?from=yesterday&to=2024-01-01T00:00:00Z"} {
+		if rec := doRequest(router, http.MethodGet, target, "", nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+// TestListUsersByDateFollowsPageToken mengikuti meta.nextPageToken sampai habis:
+// tiap user muncul tepat sekali, termasuk user dengan created_at kembar di batas halaman
+func TestListUsersByDateFollowsPageToken(t *testing.T) {
+	backend := newPageBackend(5)
+	backend.users[2].CreatedAt = backend.users[1].CreatedAt // u2 & u3 kembar, terpotong di batas halaman 1
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	const dateRange = "/users/by-date?from=2024-01-01T00:00:00Z&to=2030-01-01T00:00:00Z&limit=2"
+	var got []string
+	token := ""
+	for page := 0; page < 5; page++ {
+		target := dateRange
+		if token != "" {
+			target += "&page_token=" + url.QueryEscape(token)
+		}
+		rec := doRequest(router, http.MethodGet, target, "", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("page %d: status = %d (body: %s)", page, rec.Code, rec.Body)
+		}
+		var body struct {
+			Users []struct {
+				ID string `json:"id"`
+			} `json:"users"`
+			Meta PageMeta `json:"meta"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		for _, user := range body.Users {
+			got = append(got, user.ID)
+		}
+		token = body.Meta.NextPageToken
+		if token == "" {
+			break
+		}
+	}
+	if want := []string{"u1", "u2", "u3", "u4", "u5"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("users across pages = %v, want %v", got, want)
+	}
+	if token != "" {
+		t.Fatalf("last page still has nextPageToken %q", token)
+	}
+}
+
+func TestListUsersByDateRejectsBadPageToken(t *testing.T) {
+	backend := newPageBackend(3)
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	outside := encodeDateRangeToken(&pb.User{Id: "u1", CreatedAt: backend.users[0].CreatedAt})
+	for _, tt := range []struct{ name, target string }{
+		{"garbage", "/users/by-date?from=2024-01-01T00:00:00Z&to=2030-01-01T00:00:00Z&page_token=%25%25"},
+		{"cursor outside range", "/users/by-date?from=2025-01-01T00:00:00Z&to=2030-01-01T00:00:00Z&page_token=" + outside},
+	} {
+		if rec := doRequest(router, http.MethodGet, tt.target, "", nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", tt.name, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+)
+
+// blockingBackend: GetUser menunggu sampai context-nya selesai, lalu melaporkan alasannya
+type blockingBackend struct {
+	pb.UnimplementedUserServiceServer
+	started chan struct{}
+	ctxErr  chan error
+}
+
+func (b *blockingBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	close(b.started)
+	<-ctx.Done()
+	b.ctxErr <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestClientDisconnectCancelsGRPCCall(t *testing.T) {
+	backend := &blockingBackend{started: make(chan struct{}), ctxErr: make(chan error, 1)}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"GATEWAY_RPC_TIMEOUT": "30s", "GRPC_MAX_RETRIES": "0"})
+	srv := serveGateway(t, newTestGateway(t, cfg, upstream.addr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/users/u1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	select {
+	case <-backend.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetUser never reached the backend")
+	}
+	cancel() // Client HTTP disconnect
+	<-done
+
+	select {
+	case err := <-backend.ctxErr:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("backend context error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("gRPC call was not cancelled after the HTTP client went away")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	pb "api-gateway/proto/user"
+)
+
+// enumError dikembalikan kalau nilai enum dari client tidak dikenal
+// Berisi daftar nilai yang valid supaya client tahu harus kirim apa
+type enumError struct {
+	Field string
+	Value string
+	Valid []string
+}
+
+func (e *enumError) Error() string {
+	return fmt.Sprintf("invalid value %q for %s, valid values: %s", e.Value, e.Field, strings.Join(e.Valid, ", "))
+}
+
+// normalizeEnum menerjemahkan string dari client ke nilai enum proto secara case-insensitive
+// Semua bentuk ini diterima untuk USER_STATUS_ACTIVE:
+// "active", "Active", "ACTIVE", "user_status_active", "USER_STATUS_ACTIVE"
+// String kosong = nilai 0 (UNSPECIFIED), biar server yang menentukan default
+func normalizeEnum(field, raw, prefix string, values map[string]int32) (int32, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+
+	name := strings.ToUpper(strings.ReplaceAll(raw, "-", "_"))
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+
+	// UNSPECIFIED tidak boleh dikirim eksplisit oleh client
+	if v, ok := values[name]; ok && v != 0 {
+		return v, nil
+	}
+
+	return 0, &enumError{Field: field, Value: raw, Valid: enumChoices(prefix, values)}
+}
+
+// enumChoices membuat daftar nilai valid dalam bentuk pendek huruf kecil (contoh: "active")
+func enumChoices(prefix string, values map[string]int32) []string {
+	var out []string
+	for name, v := range values {
+		if v != 0 {
+			out = append(out, strings.ToLower(strings.TrimPrefix(name, prefix)))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// parseUserStatus khusus untuk enum UserStatus
+func parseUserStatus(raw string) (pb.UserStatus, error) {
+	v, err := normalizeEnum("status", raw, "USER_STATUS_", pb.UserStatus_value)
+	return pb.UserStatus(v), err
+}
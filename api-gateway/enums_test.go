@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+func TestParseUserStatus(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want pb.UserStatus
+	}{
+		{"active", pb.UserStatus_USER_STATUS_ACTIVE},
+		{"Pending", pb.UserStatus_USER_STATUS_PENDING},
+		{"SUSPENDED", pb.UserStatus_USER_STATUS_SUSPENDED},
+		{"sUsPeNdEd", pb.UserStatus_USER_STATUS_SUSPENDED},
+		{"user_status_active", pb.UserStatus_USER_STATUS_ACTIVE},
+		{"USER_STATUS_PENDING", pb.UserStatus_USER_STATUS_PENDING},
+		{" active ", pb.UserStatus_USER_STATUS_ACTIVE},
+		{"", pb.UserStatus_USER_STATUS_UNSPECIFIED}, // Kosong = server yang menentukan default
+	}
+	for _, tt := range tests {
+		got, err := parseUserStatus(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("parseUserStatus(%q) = %v, %v; want %v", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseUserStatusInvalid(t *testing.T) {
+	for _, raw := range []string{"deleted", "unspecified", "USER_STATUS_UNSPECIFIED", "act ive"} {
+		_, err := parseUserStatus(raw)
+		var enumErr *enumError
+		if !errors.As(err, &enumErr) {
+			t.Fatalf("parseUserStatus(%q) err = %v, want *enumError", raw, err)
+		}
+		if want := []string{"active", "pending", "suspended"}; !reflect.DeepEqual(enumErr.Valid, want) {
+			t.Fatalf("valid = %v, want %v", enumErr.Valid, want)
+		}
+	}
+}
+
+func TestCreateUserStatusCaseInsensitive(t *testing.T) {
+	backend := &writeBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	for _, status := range []string{"pending", "Pending", "PENDING", "user_status_pending"} {
+		body := `{"name":"Alice","email":"alice@example.com","status":"` + status + `"}`
+		if rec := doRequest(router, http.MethodPost, "/users", body, jsonHeader); rec.Code != http.StatusCreated {
+			t.Fatalf("status %q: HTTP %d (body: %s)", status, rec.Code, rec.Body)
+		}
+	}
+	for i, req := range backend.createRequests() {
+		if req.Status != pb.UserStatus_USER_STATUS_PENDING {
+			t.Fatalf("request %d forwarded status %v, want USER_STATUS_PENDING", i, req.Status)
+		}
+	}
+
+	rec := doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com","status":"banned"}`, jsonHeader)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid status: HTTP %d, want 400", rec.Code)
+	}
+	var body struct {
+		Field string   `json:"field"`
+		Valid []string `json:"valid"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v (body: %s)", err, rec.Body)
+	}
+	if body.Field != "status" || !reflect.DeepEqual(body.Valid, []string{"active", "pending", "suspended"}) {
+		t.Fatalf("error body = %+v, want field status with valid values", body)
+	}
+	if got := len(backend.createRequests()); got != 4 {
+		t.Fatalf("CreateUser calls = %d, want 4 (invalid status rejected at the gateway)", got)
+	}
+}
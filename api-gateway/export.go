@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	pb "api-gateway/proto/user"
+)
+
+// ExportUsersCSVHandler menghandle GET /users/export.csv
+// Streaming: setiap user dari gRPC stream langsung ditulis sebagai 1 baris CSV,
+// jadi export besar tidak perlu ditampung semua di memory gateway
+func (gw *APIGateway) ExportUsersCSVHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE FILTER (sama seperti GET /users, tapi default tanpa batas)
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		limit = v
+	}
+
+	log.Printf("📥 Received ExportUsersCSV request (limit: %d)", limit)
+
+	// 3. CONTEXT dengan TIMEOUT (export bisa lama)
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), 5*time.Minute)
+	defer cancel()
+
+	// 4. CALL gRPC STREAMING METHOD
+	stream, err := gw.openUserStream(ctx, func(ctx context.Context) (userStream, error) {
+		return gw.userClient.ListUsers(ctx, &pb.ListUsersRequest{
+			Limit: int32(limit),
+		})
+	})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	// 5. TULIS CSV SECARA INCREMENTAL
+	// encoding/csv otomatis meng-quote field yang mengandung koma, kutip, atau newline
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="users.csv"`)
+
+	// Flush per batch (STREAM_FLUSH_RECORDS / STREAM_FLUSH_INTERVAL):
+	// flush per baris terlalu boros, tanpa flush client harus nunggu semua data
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "name", "email", "age", "created_at"})
+	batch := newBatchFlusher(w, gw.flushPolicy(), writer.Flush)
+
+	rows := 0
+	err = recvUsers(stream, func(user *pb.User) error {
+		return batch.Do(func() error {
+			if err := writer.Write([]string{
+				user.Id,
+				user.Name,
+				user.Email,
+				strconv.Itoa(int(user.Age)),
+				pb.FormatTimestamp(user.CreatedAt),
+			}); err != nil {
+				return err
+			}
+			rows++
+			return writer.Error()
+		})
+	})
+
+	// Gagal sebelum ada baris data (misal upstream down) → belum ada yang terkirim,
+	// jadi masih bisa balas dengan error yang proper, bukan file CSV kosong
+	if err != nil && rows == 0 {
+		batch.Close() // Tidak ada record pending, jadi tidak ada yang ter-flush
+		log.Printf("❌ Stream error: %v", err)
+		w.Header().Del("Content-Disposition")
+		writeGRPCError(w, err)
+		return
+	}
+
+	// Batch terakhir (belum penuh) + header CSV tetap harus di-flush
+	batch.Close()
+	writer.Flush()
+
+	if err != nil {
+		// Header sudah terkirim, jadi hanya bisa log — file CSV di client akan terpotong
+		log.Printf("❌ CSV export aborted after %d rows: %v", rows, err)
+		return
+	}
+
+	log.Printf("✅ Exported %d users as CSV", rows)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/csv"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestExportUsersCSVRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)
+	backend := &listBackend{users: []*pb.User{
+		{Id: "u1", Name: "Plain", Email: "plain@example.com", Age: 30, CreatedAt: timestamppb.New(created)},
+		{Id: "u2", Name: "Doe, Jane", Email: "jane@example.com", Age: 41, CreatedAt: timestamppb.New(created)},
+		{Id: "u3", Name: `Bob "The Builder"`, Email: "bob@example.com", Age: 25},
+		{Id: "u4", Name: "Multi\nLine", Email: "ml@example.com", Age: 0},
+	}}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"STREAM_FLUSH_RECORDS": "2"})
+	srv := serveGateway(t, newTestGateway(t, cfg, upstream.addr))
+
+	resp := getStream(t, srv.URL+"/users/export.csv", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Disposition"); got != `attachment; filename="users.csv"` {
+		t.Fatalf("Content-Disposition = %q", got)
+	}
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Fatalf("Content-Type = %q", got)
+	}
+
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	want := [][]string{
+		{"id", "name", "email", "age", "created_at"},
+		{"u1", "Plain", "plain@example.com", "30", "2024-03-01T10:30:00Z"},
+		{"u2", "Doe, Jane", "jane@example.com", "41", "2024-03-01T10:30:00Z"},
+		{"u3", `Bob "The Builder"`, "bob@example.com", "25", ""},
+		{"u4", "Multi\nLine", "ml@example.com", "0", ""},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("CSV rows = %q, want %q", records, want)
+	}
+}
+
+func TestExportUsersCSVUpstreamError(t *testing.T) {
+	backend := &listBackend{err: status.Error(codes.PermissionDenied, "denied")}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	// Gagal sebelum baris pertama → error HTTP biasa, bukan file CSV kosong
+	rec := doRequest(router, http.MethodGet, "/users/export.csv", "", nil)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want 403", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != "" {
+		t.Fatalf("Content-Disposition = %q on error response", got)
+	}
+}
+
+func TestExportUsersCSVRejectsInvalidLimit(t *testing.T) {
+	upstream := startUserService(t, &listBackend{})
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	if rec := doRequest(router, http.MethodGet, "/users/export.csv?limit=-1", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// userFilterQuery adalah filter pencarian dari query string GET /users & /users/count
+// ?name_contains=al&email_domain=example.com&min_age=18&max_age=30
+// Validasi range (min_age > max_age, dll) dilakukan di User Service
+type userFilterQuery struct {
+	NameContains string
+	EmailDomain  string
+	MinAge       *int32 // nil = tidak di-set
+	MaxAge       *int32
+}
+
+// parseUserFilterQuery membaca parameter filter; error kalau umur bukan angka
+func parseUserFilterQuery(q url.Values) (userFilterQuery, error) {
+	f := userFilterQuery{
+		NameContains: q.Get("name_contains"),
+		EmailDomain:  q.Get("email_domain"),
+	}
+	var err error
+	if f.MinAge, err = parseAgeParam(q, "min_age"); err != nil {
+		return userFilterQuery{}, err
+	}
+	if f.MaxAge, err = parseAgeParam(q, "max_age"); err != nil {
+		return userFilterQuery{}, err
+	}
+	return f, nil
+}
+
+func parseAgeParam(q url.Values, key string) (*int32, error) {
+	raw := q.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an integer", key)
+	}
+	age := int32(v)
+	return &age, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+func TestParseUserFilterQuery(t *testing.T) {
+	q := url.Values{"name_contains": {"al"}, "email_domain": {"example.com"}, "min_age": {"18"}}
+	f, err := parseUserFilterQuery(q)
+	if err != nil {
+		t.Fatalf("parseUserFilterQuery: %v", err)
+	}
+	if f.NameContains != "al" || f.EmailDomain != "example.com" || f.MinAge == nil || *f.MinAge != 18 || f.MaxAge != nil {
+		t.Fatalf("filter = %+v", f)
+	}
+
+	for _, raw := range []string{"abc", "1.5", "99999999999"} {
+		if _, err := parseUserFilterQuery(url.Values{"max_age": {raw}}); err == nil {
+			t.Fatalf("max_age=%q: want error", raw)
+		}
+	}
+}
+
+func TestCountUsersForwardsFilters(t *testing.T) {
+	backend := &countBackend{count: 1, last: make(chan *pb.CountUsersRequest, 1)}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/count?name_contains=al&email_domain=example.com&min_age=18&max_age=30", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	req := <-backend.last
+	if req.NameContains != "al" || req.EmailDomain != "example.com" || req.GetMinAge() != 18 || req.GetMaxAge() != 30 {
+		t.Fatalf("forwarded request = %v", req)
+	}
+
+	// Umur bukan angka ditolak di gateway, tidak sampai ke backend
+	if rec := doRequest(router, http.MethodGet, "/users/count?min_age=old", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid min_age: HTTP %d, want 400", rec.Code)
+	}
+	if len(backend.last) != 0 {
+		t.Fatal("invalid min_age reached the backend")
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// flushPolicy menentukan kapan response streaming di-flush ke client:
+// setiap `records` record ATAU setiap `interval`, mana yang lebih dulu
+// (STREAM_FLUSH_RECORDS / STREAM_FLUSH_INTERVAL)
+type flushPolicy struct {
+	records  int           // Minimal 1 (1 = flush per record)
+	interval time.Duration // 0 = hanya berdasarkan jumlah record
+}
+
+// batchFlusher menggabungkan beberapa record sebelum flush, supaya tidak
+// ada 1 syscall/TCP packet per record kecil, tapi record juga tidak "nyangkut"
+// terlalu lama di buffer kalau stream-nya pelan (ditangani timer)
+//
+// Semua write HARUS lewat Do(): timer flush jalan di goroutine lain,
+// dan http.ResponseWriter tidak aman dipakai bersamaan
+type batchFlusher struct {
+	mu       sync.Mutex
+	w        http.ResponseWriter
+	policy   flushPolicy
+	flushBuf func() // Flush buffer tambahan sebelum flush HTTP (contoh: csv.Writer), boleh nil
+	pending  int
+	timer    *time.Timer
+	closed   bool
+}
+
+func newBatchFlusher(w http.ResponseWriter, policy flushPolicy, flushBuf func()) *batchFlusher {
+	if policy.records < 1 {
+		policy.records = 1
+	}
+	return &batchFlusher{w: w, policy: policy, flushBuf: flushBuf}
+}
+
+// Do menjalankan write untuk 1 record, lalu flush kalau batas record tercapai
+func (b *batchFlusher) Do(write func() error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := write(); err != nil {
+		return err
+	}
+
+	b.pending++
+	if b.pending >= b.policy.records {
+		b.flushLocked()
+		return nil
+	}
+
+	// Record pertama di batch ini: pasang timer supaya batch tetap terkirim walau stream pelan
+	if b.policy.interval > 0 && b.timer == nil {
+		b.timer = time.AfterFunc(b.policy.interval, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if !b.closed {
+				b.flushLocked()
+			}
+		})
+	}
+	return nil
+}
+
+// Close flush batch terakhir (yang belum penuh) dan mematikan timer
+// Wajib dipanggil di akhir stream, supaya record terakhir tidak hilang
+func (b *batchFlusher) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	if b.pending > 0 {
+		b.flushLocked()
+	}
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+}
+
+func (b *batchFlusher) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if b.flushBuf != nil {
+		b.flushBuf()
+	}
+	if f, ok := b.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	b.pending = 0
+}
+
+// flushPolicy return policy flush streaming dari konfigurasi gateway
+func (gw *APIGateway) flushPolicy() flushPolicy {
+	return flushPolicy{records: gw.cfg.StreamFlushRecords, interval: gw.cfg.StreamFlushInterval}
+}
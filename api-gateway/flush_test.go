@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flushCounter adalah ResponseRecorder yang menghitung berapa kali di-flush
+type flushCounter struct {
+	*httptest.ResponseRecorder
+	mu      sync.Mutex
+	flushes int
+}
+
+func (f *flushCounter) Flush() {
+	f.mu.Lock()
+	f.flushes++
+	f.mu.Unlock()
+	f.ResponseRecorder.Flush()
+}
+
+func (f *flushCounter) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.flushes
+}
+
+func TestBatchFlusherFlushesEveryNRecordsAndOnClose(t *testing.T) {
+	w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	b := newBatchFlusher(w, flushPolicy{records: 3}, nil)
+
+	for i := 0; i < 7; i++ {
+		if err := b.Do(func() error { _, err := w.WriteString("r\n"); return err }); err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+	}
+	if got := w.count(); got != 2 {
+		t.Fatalf("flushes after 7 records = %d, want 2 (every 3 records)", got)
+	}
+
+	// Batch terakhir (1 record) tetap ter-flush saat stream selesai
+	b.Close()
+	if got := w.count(); got != 3 {
+		t.Fatalf("flushes after Close = %d, want 3", got)
+	}
+	if got := strings.Count(w.Body.String(), "r\n"); got != 7 {
+		t.Fatalf("records written = %d, want 7", got)
+	}
+
+	// Close tanpa record pending tidak flush lagi
+	b.Close()
+	if got := w.count(); got != 3 {
+		t.Fatalf("flushes after second Close = %d, want 3", got)
+	}
+}
+
+func TestBatchFlusherFlushesSlowStreamOnInterval(t *testing.T) {
+	w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	b := newBatchFlusher(w, flushPolicy{records: 100, interval: 10 * time.Millisecond}, nil)
+	defer b.Close()
+
+	b.Do(func() error { return nil })
+	deadline := time.Now().Add(time.Second)
+	for w.count() == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w.count() != 1 {
+		t.Fatal("partial batch was not flushed by the interval timer")
+	}
+}
+
+func TestBatchFlusherFlushesBufferFirst(t *testing.T) {
+	w := &flushCounter{ResponseRecorder: httptest.NewRecorder()}
+	var bufFlushes int
+	b := newBatchFlusher(w, flushPolicy{records: 0}, func() { bufFlushes++ })
+
+	// records < 1 dianggap 1 → flush per record, buffer (contoh csv.Writer) ikut di-flush
+	b.Do(func() error { return nil })
+	if bufFlushes != 1 || w.count() != 1 {
+		t.Fatalf("buffer flushes = %d, HTTP flushes = %d, want 1 each", bufFlushes, w.count())
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"api-gateway/config"
+	"api-gateway/metrics"
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Helper bersama untuk test gateway: User Service palsu berjalan sebagai server gRPC sungguhan
+// di port lokal, dan gateway dibuat lewat NewAPIGateway, jadi request test melewati
+// client, interceptor, dan routing yang sama dengan production
+
+// testConfig membaca config.Load dengan env untuk test (plaintext gRPC), env lain bisa di-override
+func testConfig(t *testing.T, env map[string]string) *config.Config {
+	t.Helper()
+	t.Setenv("INSECURE", "true")
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	return cfg
+}
+
+// testBackend adalah 1 instance User Service palsu
+type testBackend struct {
+	addr   string
+	server *grpc.Server
+	health *health.Server // Status awal SERVING
+}
+
+// startUserService menjalankan srv (+ health service) di port localhost acak sampai test selesai
+func startUserService(t *testing.T, srv pb.UserServiceServer, opts ...grpc.ServerOption) *testBackend {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	server := grpc.NewServer(opts...)
+	pb.RegisterUserServiceServer(server, srv)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return &testBackend{addr: lis.Addr().String(), server: server, health: healthServer}
+}
+
+// writeBackend adalah User Service palsu untuk endpoint write:
+// mencatat request CreateUser/UpdateUser yang sampai, lalu membalas sukses
+type writeBackend struct {
+	pb.UnimplementedUserServiceServer
+
+	mu      sync.Mutex
+	creates []*pb.CreateUserRequest
+	updates []*pb.UpdateUserRequest
+}
+
+func (b *writeBackend) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.creates = append(b.creates, req)
+	user := &pb.User{Id: "new", Name: req.Name, Email: req.Email, Age: req.Age, Status: req.Status, Version: 1}
+	return &pb.CreateUserResponse{User: user, Success: true}, nil
+}
+
+func (b *writeBackend) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.updates = append(b.updates, req)
+	user := &pb.User{Id: req.Id, Name: req.Name, Email: req.Email, Age: req.Age, Version: req.ExpectedVersion + 1}
+	return &pb.UpdateUserResponse{User: user}, nil
+}
+
+// createRequests return salinan CreateUser yang sudah diterima
+func (b *writeBackend) createRequests() []*pb.CreateUserRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*pb.CreateUserRequest(nil), b.creates...)
+}
+
+// newTestGateway membuat gateway yang terhubung ke address User Service palsu
+func newTestGateway(t *testing.T, cfg *config.Config, addrs ...string) *APIGateway {
+	t.Helper()
+	m, err := metrics.Setup(context.Background(), "api-gateway-test", []string{metrics.ExporterPrometheus}, "")
+	if err != nil {
+		t.Fatalf("metrics.Setup: %v", err)
+	}
+	cfg.UserServiceAddrs = addrs
+	gw, err := NewAPIGateway(addrs, cfg, m)
+	if err != nil {
+		t.Fatalf("NewAPIGateway: %v", err)
+	}
+	t.Cleanup(func() { gw.conn.Close() })
+	return gw
+}
+
+// testRouter return router lengkap gateway (routes), sama dengan yang dipasang main
+func testRouter(t *testing.T, gw *APIGateway) http.Handler {
+	t.Helper()
+	m, err := metrics.Setup(context.Background(), "api-gateway-test", []string{metrics.ExporterPrometheus}, "")
+	if err != nil {
+		t.Fatalf("metrics.Setup: %v", err)
+	}
+	return gw.routes(m)
+}
+
+// serveGateway menjalankan router lengkap gateway di httptest.Server (untuk test streaming)
+func serveGateway(t *testing.T, gw *APIGateway) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(testRouter(t, gw))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// doRequest mengirim request ke handler lewat httptest.ResponseRecorder
+func doRequest(h http.Handler, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
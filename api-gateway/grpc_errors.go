@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"api-gateway/logging"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorEnvelope adalah format body error yang konsisten untuk semua handler
+//
+//	{"error": {"code": "NotFound", "message": "user with id xxx not found"}}
+//
+// Error validasi dari User Service (detail google.rpc.BadRequest) juga membawa daftar field:
+//
+//	{"error": {"code": "InvalidArgument", "message": "...", "fields": [{"field": "email", "message": "..."}]}}
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string       `json:"code"`             // Nama gRPC status code (sama seperti event error di /users/resolve)
+	Message string       `json:"message"`          // Pesan dari User Service, tanpa prefix "rpc error: ..."
+	Fields  []fieldError `json:"fields,omitempty"` // Field yang tidak valid (format sama dengan error schema validation)
+}
+
+
+// grpcToHTTPStatus menerjemahkan gRPC status code menjadi HTTP status code
+// Error yang bukan gRPC status (misal error lokal gateway) dianggap 500
+func grpcToHTTPStatus(err error) int {
+	st, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch st.Code() {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // Client Closed Request (konvensi nginx, tidak ada konstanta di net/http)
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default: // Unknown, Internal, DataLoss
+		return http.StatusInternalServerError
+	}
+}
+
+// logGRPCError mencatat gRPC call yang gagal dengan logger milik request
+// (request_id, method, path sudah terisi oleh middleware requestLogger)
+func logGRPCError(r *http.Request, err error, args ...any) {
+	args = append([]any{"status", status.Code(err).String(), "error", err}, args...)
+	logging.FromContext(r.Context()).Error("gRPC call failed", args...)
+}
+
+// writeGRPCError menulis error dari gRPC call sebagai JSON error envelope
+// dengan HTTP status hasil grpcToHTTPStatus
+// Write yang ditolak read-only mode tetap 503 + Retry-After (lihat writeReadOnlyError)
+func writeGRPCError(w http.ResponseWriter, err error) {
+	if isReadOnlyError(err) {
+		writeReadOnlyError(w)
+		return
+	}
+
+	st := status.Convert(err)
+	writeErrorBody(w, grpcToHTTPStatus(err), errorBody{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+		Fields:  fieldErrors(st),
+	})
+}
+
+// fieldErrors mengambil FieldViolation dari detail google.rpc.BadRequest (nil kalau tidak ada)
+func fieldErrors(st *status.Status) []fieldError {
+	var out []fieldError
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, v := range badRequest.GetFieldViolations() {
+			out = append(out, fieldError{Field: v.GetField(), Message: v.GetDescription()})
+		}
+	}
+	return out
+}
+
+// writeErrorEnvelope menulis body {"error": {"code", "message"}} dengan HTTP status tertentu
+func writeErrorEnvelope(w http.ResponseWriter, httpStatus int, code, message string) {
+	writeErrorBody(w, httpStatus, errorBody{Code: code, Message: message})
+}
+
+func writeErrorBody(w http.ResponseWriter, httpStatus int, body errorBody) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(errorEnvelope{Error: body})
+}
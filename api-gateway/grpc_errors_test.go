@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorBackend: GetUser selalu gagal dengan err
+type errorBackend struct {
+	pb.UnimplementedUserServiceServer
+	err error
+}
+
+func (b *errorBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	return nil, b.err
+}
+
+func TestGRPCToHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.OK, http.StatusOK},
+		{codes.Canceled, 499},
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.FailedPrecondition, http.StatusBadRequest},
+		{codes.OutOfRange, http.StatusBadRequest},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.Aborted, http.StatusConflict},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.Unimplemented, http.StatusNotImplemented},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+		{codes.Unknown, http.StatusInternalServerError},
+		{codes.Internal, http.StatusInternalServerError},
+		{codes.DataLoss, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := grpcToHTTPStatus(status.Error(tt.code, "x")); got != tt.want {
+			t.Errorf("grpcToHTTPStatus(%v) = %d, want %d", tt.code, got, tt.want)
+		}
+	}
+	if got := grpcToHTTPStatus(errors.New("local failure")); got != http.StatusInternalServerError {
+		t.Errorf("non-gRPC error = %d, want 500", got)
+	}
+}
+
+func TestGRPCErrorsMappedOverHTTP(t *testing.T) {
+	tests := []struct {
+		code codes.Code
+		want int
+	}{
+		{codes.NotFound, http.StatusNotFound},
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.Internal, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code.String(), func(t *testing.T) {
+			upstream := startUserService(t, &errorBackend{err: status.Error(tt.code, "upstream said no")})
+			cfg := testConfig(t, map[string]string{"GRPC_MAX_RETRIES": "0"})
+			router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+
+			rec := doRequest(router, http.MethodGet, "/users/u1", "", nil)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.want, rec.Body)
+			}
+			var body errorEnvelope
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode envelope: %v (body: %s)", err, rec.Body)
+			}
+			if body.Error.Code != tt.code.String() || body.Error.Message != "upstream said no" {
+				t.Fatalf("envelope = %+v, want code %s with the upstream message", body.Error, tt.code)
+			}
+		})
+	}
+}
+
+func TestGRPCErrorEnvelopeCarriesFieldViolations(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid user").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "email", Description: "email is required"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upstream := startUserService(t, &errorBackend{err: st.Err()})
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	var body errorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode envelope: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest || len(body.Error.Fields) != 1 || body.Error.Fields[0].Field != "email" {
+		t.Fatalf("status %d, envelope %+v; want 400 with the email field violation", rec.Code, body.Error)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+)
+
+// headerPolicy menentukan header response apa saja yang boleh keluar ke client
+type headerPolicy struct {
+	deny        map[string]bool // Header yang selalu dibuang
+	allow       map[string]bool // Kalau tidak kosong: HANYA header ini yang boleh keluar
+	serverValue string          // Nilai custom untuk header "Server" (kosong = tidak di-set)
+}
+
+// newHeaderPolicy membuat policy dari list nama header (case-insensitive)
+func newHeaderPolicy(deny, allow []string, serverValue string) *headerPolicy {
+	p := &headerPolicy{
+		deny:        make(map[string]bool, len(deny)),
+		allow:       make(map[string]bool, len(allow)),
+		serverValue: serverValue,
+	}
+	for _, h := range deny {
+		p.deny[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, h := range allow {
+		p.allow[http.CanonicalHeaderKey(h)] = true
+	}
+	return p
+}
+
+// apply membersihkan header sesuai policy (dipanggil tepat sebelum header dikirim)
+func (p *headerPolicy) apply(h http.Header) {
+	for key := range h {
+		if p.deny[key] || (len(p.allow) > 0 && !p.allow[key]) {
+			h.Del(key)
+		}
+	}
+	if p.serverValue != "" {
+		h.Set("Server", p.serverValue)
+	}
+}
+
+// headerFilterWriter menjalankan policy saat header pertama kali ditulis
+type headerFilterWriter struct {
+	http.ResponseWriter
+	policy      *headerPolicy
+	wroteHeader bool
+}
+
+func (fw *headerFilterWriter) WriteHeader(code int) {
+	if !fw.wroteHeader {
+		fw.policy.apply(fw.Header())
+		fw.wroteHeader = true
+	}
+	fw.ResponseWriter.WriteHeader(code)
+}
+
+func (fw *headerFilterWriter) Write(b []byte) (int, error) {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+	return fw.ResponseWriter.Write(b)
+}
+
+func (fw *headerFilterWriter) Flush() {
+	if !fw.wroteHeader {
+		fw.WriteHeader(http.StatusOK)
+	}
+	if f, ok := fw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (fw *headerFilterWriter) Unwrap() http.ResponseWriter {
+	return fw.ResponseWriter
+}
+
+// stripHeaders adalah middleware TERAKHIR (paling luar) sebelum response keluar
+// Mencegah header internal (Server upstream, trace header internal, dll) bocor ke client
+func stripHeaders(policy *headerPolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&headerFilterWriter{ResponseWriter: w, policy: policy}, r)
+	})
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// leakyHandler meniru handler yang memasang header internal
+func leakyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Server", "upstream/1.2.3")
+	w.Header().Set("X-Powered-By", "Go")
+	w.Header().Set("Traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w.Header().Set("X-Upstream-Addr", "10.0.0.5:50051")
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", "req-1")
+	w.Write([]byte("{}"))
+}
+
+func TestStripHeadersDenylist(t *testing.T) {
+	policy := newHeaderPolicy([]string{"server", "x-powered-by", "Traceparent", "X-Upstream-Addr"}, nil, "")
+	rec := httptest.NewRecorder()
+	stripHeaders(policy, http.HandlerFunc(leakyHandler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/u1", nil))
+
+	for _, h := range []string{"Server", "X-Powered-By", "Traceparent", "X-Upstream-Addr"} {
+		if got := rec.Header().Get(h); got != "" {
+			t.Errorf("%s = %q, want removed", h, got)
+		}
+	}
+	for _, h := range []string{"Content-Type", "X-Request-Id"} {
+		if rec.Header().Get(h) == "" {
+			t.Errorf("%s was removed, want kept", h)
+		}
+	}
+}
+
+func TestStripHeadersAllowlist(t *testing.T) {
+	policy := newHeaderPolicy(nil, []string{"Content-Type"}, "")
+	rec := httptest.NewRecorder()
+	stripHeaders(policy, http.HandlerFunc(leakyHandler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/u1", nil))
+
+	if len(rec.Header()) != 1 || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("headers = %v, want only Content-Type", rec.Header())
+	}
+}
+
+func TestStripHeadersCustomServer(t *testing.T) {
+	policy := newHeaderPolicy([]string{"Server"}, nil, "gateway")
+	rec := httptest.NewRecorder()
+	stripHeaders(policy, http.HandlerFunc(leakyHandler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/u1", nil))
+
+	if got := rec.Header().Get("Server"); got != "gateway" {
+		t.Fatalf("Server = %q, want gateway", got)
+	}
+}
+
+func TestStripHeadersOnStreamingFlush(t *testing.T) {
+	policy := newHeaderPolicy([]string{"X-Upstream-Addr"}, nil, "")
+	streaming := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Upstream-Addr", "10.0.0.5:50051")
+		w.(http.Flusher).Flush() // Header terkirim lewat Flush, bukan Write
+	}
+	rec := httptest.NewRecorder()
+	stripHeaders(policy, http.HandlerFunc(streaming)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/stream", nil))
+
+	if got := rec.Header().Get("X-Upstream-Addr"); got != "" {
+		t.Fatalf("X-Upstream-Addr = %q, want removed", got)
+	}
+}
+
+// TestStripHeadersDefaultConfig memastikan denylist default dari config dipakai
+func TestStripHeadersDefaultConfig(t *testing.T) {
+	cfg := testConfig(t, map[string]string{"SERVER_HEADER": "api-gateway"})
+	policy := newHeaderPolicy(cfg.ResponseHeaderDenylist, cfg.ResponseHeaderAllowlist, cfg.ServerHeader)
+	rec := httptest.NewRecorder()
+	stripHeaders(policy, http.HandlerFunc(leakyHandler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/u1", nil))
+
+	if got := rec.Header().Get("Server"); got != "api-gateway" {
+		t.Errorf("Server = %q, want api-gateway", got)
+	}
+	for _, h := range []string{"X-Powered-By", "Traceparent", "X-Upstream-Addr"} {
+		if got := rec.Header().Get(h); got != "" {
+			t.Errorf("%s = %q, want removed by default denylist", h, got)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// componentCheckTimeout adalah batas waktu 1 health check komponen
+// Dependency yang hang tidak boleh membuat /health/detail ikut hang
+const componentCheckTimeout = 2 * time.Second
+
+// HealthHandler menghandle GET /health
+// Bukan sekadar "proses gateway hidup": backend di-probe lewat grpc.health.v1.Health
+// (service "" = status server User Service secara keseluruhan), jadi /health
+// ikut 503 kalau User Service mati atau sedang shutdown (NOT_SERVING)
+func (gw *APIGateway) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	resp, err := gw.healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: ""})
+	if err != nil {
+		log.Printf("❌ Health check failed: %v", err)
+		http.Error(w, "UNHEALTHY: user service unreachable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		http.Error(w, "UNHEALTHY: user service "+resp.Status.String(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+}
+
+// waitForBackend menunggu User Service melaporkan SERVING, paling lama timeout
+// WaitForReady: Check tidak langsung gagal saat koneksi masih CONNECTING/TRANSIENT_FAILURE,
+// tapi ikut menunggu reconnect (backend yang start bersamaan dengan gateway tetap terdeteksi)
+func (gw *APIGateway) waitForBackend(ctx context.Context, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		resp, err := gw.healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: ""}, grpc.WaitForReady(true))
+		if err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING {
+			return nil
+		}
+		if err == nil {
+			// Backend hidup tapi belum SERVING (warmup): coba lagi sebentar lagi
+			err = fmt.Errorf("user service %s", resp.Status)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("user service not ready after %s: %w", timeout, err)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// ReadyzHandler menghandle GET /readyz
+// Gateway dianggap ready HANYA kalau User Service melaporkan SERVING
+// (misal selama warmup user-service melaporkan NOT_SERVING → 503)
+func (gw *APIGateway) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	resp, err := gw.healthClient.Check(ctx, &healthpb.HealthCheckRequest{
+		Service: pb.UserService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		log.Printf("❌ Readiness check failed: %v", err)
+		http.Error(w, "NOT READY: user service unreachable", http.StatusServiceUnavailable)
+		return
+	}
+
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		http.Error(w, "NOT READY: user service "+resp.Status.String(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("READY"))
+}
+
+// componentStatus adalah hasil health check 1 komponen (format JSON /health/detail)
+type componentStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"` // "ok", "error", atau "disabled"
+	LatencyMs int64  `json:"latencyMs"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// healthTracker menyimpan error terakhir tiap komponen antar request
+type healthTracker struct {
+	mu      sync.Mutex
+	lastErr map[string]string
+}
+
+func (t *healthTracker) record(name string, err error) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastErr == nil {
+		t.lastErr = make(map[string]string)
+	}
+	if err != nil {
+		t.lastErr[name] = err.Error()
+	}
+	return t.lastErr[name]
+}
+
+// HealthDetailHandler menghandle GET /health/detail (admin)
+// Diagnostic view untuk operator: status tiap komponen gateway + komponen User Service
+// Di-gate admin karena pesan error bisa berisi address/detail internal
+func (gw *APIGateway) HealthDetailHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var (
+		mu         sync.Mutex
+		wg         sync.WaitGroup
+		components []componentStatus
+	)
+	add := func(c ...componentStatus) {
+		mu.Lock()
+		components = append(components, c...)
+		mu.Unlock()
+	}
+
+	// Semua check jalan paralel, masing-masing dengan timeout sendiri
+	wg.Add(4)
+	go func() { defer wg.Done(); add(gw.runCheck(r.Context(), "grpc_connection", gw.checkConnection)) }()
+	go func() { defer wg.Done(); add(gw.checkUpstreamDetail(withAdminAuth(r.Context(), r))...) }()
+	go func() {
+		defer wg.Done()
+		if gw.staleUsers == nil {
+			add(componentStatus{Name: "stale_cache", Status: "disabled"})
+			return
+		}
+		add(componentStatus{Name: "stale_cache", Status: "ok"})
+	}()
+	go func() {
+		defer wg.Done()
+		if gw.responses == nil {
+			add(componentStatus{Name: "response_cache", Status: "disabled"})
+			return
+		}
+		add(componentStatus{Name: "response_cache", Status: "ok"})
+	}()
+	wg.Wait()
+
+	// Urutan goroutine tidak deterministik, sort supaya output stabil
+	sortComponents(components)
+
+	overall := "ok"
+	for _, c := range components {
+		if c.Status == "error" {
+			overall = "degraded"
+			break
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     overall,
+		"components": components,
+	})
+}
+
+// runCheck menjalankan 1 check dengan timeout dan mencatat latency + error terakhir
+func (gw *APIGateway) runCheck(ctx context.Context, name string, check func(context.Context) error) componentStatus {
+	ctx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1) // Buffered: goroutine check yang telat tidak bocor selamanya
+	go func() { errCh <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", componentCheckTimeout)
+	}
+
+	result := componentStatus{
+		Name:      name,
+		Status:    "ok",
+		LatencyMs: time.Since(start).Milliseconds(),
+		LastError: gw.health.record(name, err),
+	}
+	if err != nil {
+		result.Status = "error"
+	}
+	return result
+}
+
+// checkConnection cek state koneksi gRPC ke User Service
+// Idle/Connecting dianggap ok (koneksi gRPC memang lazy)
+func (gw *APIGateway) checkConnection(ctx context.Context) error {
+	switch state := gw.conn.GetState(); state {
+	case connectivity.TransientFailure, connectivity.Shutdown:
+		return fmt.Errorf("connection state %s", state)
+	default:
+		return nil
+	}
+}
+
+// checkUpstreamDetail memanggil RPC HealthDetail dan menggabungkan komponen User Service
+// Komponen upstream diberi prefix "user_service." supaya jelas asalnya
+func (gw *APIGateway) checkUpstreamDetail(ctx context.Context) []componentStatus {
+	var upstream []*pb.ComponentHealth
+
+	result := gw.runCheck(ctx, "user_service", func(ctx context.Context) error {
+		resp, err := gw.userClient.HealthDetail(ctx, &pb.HealthDetailRequest{})
+		if err != nil {
+			return err
+		}
+		upstream = resp.Components
+		return nil
+	})
+
+	out := []componentStatus{result}
+	if result.Status != "ok" {
+		return out
+	}
+	for _, c := range upstream {
+		out = append(out, componentStatus{
+			Name:      "user_service." + c.Name,
+			Status:    c.Status,
+			LatencyMs: c.LatencyMs,
+			LastError: c.LastError,
+		})
+	}
+	return out
+}
+
+// sortComponents mengurutkan komponen berdasarkan nama
+func sortComponents(components []componentStatus) {
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// unusedAddr return address yang tidak sedang di-listen siapa pun (backend mati)
+func unusedAddr(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := lis.Addr().String()
+	lis.Close()
+	return addr
+}
+
+func TestHealthReportsNotReadyWhenBackendDown(t *testing.T) {
+	gw := newTestGateway(t, testConfig(t, nil), unusedAddr(t))
+	router := testRouter(t, gw)
+
+	for _, path := range []string{"/health", "/readyz"} {
+		if rec := doRequest(router, http.MethodGet, path, "", nil); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s with backend down = %d, want 503 (body: %s)", path, rec.Code, rec.Body)
+		}
+	}
+
+	start := time.Now()
+	if err := gw.waitForBackend(context.Background(), 300*time.Millisecond); err == nil {
+		t.Fatal("waitForBackend with backend down: want error")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("waitForBackend took %s, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestHealthReflectsBackendServingStatus(t *testing.T) {
+	upstream := startUserService(t, &writeBackend{})
+	gw := newTestGateway(t, testConfig(t, nil), upstream.addr)
+	router := testRouter(t, gw)
+
+	upstream.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	if rec := doRequest(router, http.MethodGet, "/health", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/health while NOT_SERVING = %d, want 503", rec.Code)
+	}
+
+	// Backend selesai warmup saat gateway sedang menunggu → probe startup berhasil
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		upstream.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	}()
+	if err := gw.waitForBackend(context.Background(), 5*time.Second); err != nil {
+		t.Fatalf("waitForBackend: %v", err)
+	}
+	if rec := doRequest(router, http.MethodGet, "/health", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("/health while SERVING = %d, want 200 (body: %s)", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/status"
+)
+
+// hedgeResult adalah hasil 1 attempt GetUser
+type hedgeResult struct {
+	resp *pb.GetUserResponse
+	err  error
+}
+
+// getUser memanggil GetUser dengan request hedging (kalau diaktifkan)
+//
+// Cara kerja hedging:
+//  1. Kirim attempt pertama
+//  2. Kalau belum ada jawaban setelah HEDGE_DELAY → kirim attempt berikutnya
+//     (dengan load balancing round_robin, attempt baru jatuh ke backend lain)
+//  3. Jawaban sukses PERTAMA yang dipakai, attempt lain di-cancel
+//
+// HANYA untuk read yang aman diulang (GetUser). Jangan pakai untuk Create/Update/Delete!
+func (gw *APIGateway) getUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	delay, maxAttempts := gw.cfg.HedgeDelay, gw.cfg.HedgeMaxAttempts
+	if delay <= 0 || maxAttempts < 2 {
+		return gw.userClient.GetUser(ctx, req)
+	}
+
+	// Cancel context ini = cancel semua attempt yang kalah
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered supaya goroutine attempt yang kalah tidak nge-block (goroutine leak)
+	results := make(chan hedgeResult, maxAttempts)
+	launch := func() {
+		go func() {
+			resp, err := gw.userClient.GetUser(ctx, req)
+			results <- hedgeResult{resp: resp, err: err}
+		}()
+	}
+
+	launch()
+	launched, inflight := 1, 1
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case res := <-results:
+			inflight--
+			if res.err == nil {
+				return res.resp, nil
+			}
+
+			// Error bisnis (NotFound, InvalidArgument, ...) berlaku untuk semua backend,
+			// jadi tidak ada gunanya menunggu attempt lain
+			if !isUpstreamDown(res.err) {
+				return nil, res.err
+			}
+
+			if inflight > 0 {
+				continue // Masih ada attempt lain yang mungkin sukses
+			}
+			if launched >= maxAttempts {
+				return nil, res.err
+			}
+
+			// Semua attempt gagal karena upstream → langsung coba lagi tanpa nunggu timer
+			log.Printf("🪃 GetUser attempt failed, hedging immediately (attempt %d/%d)", launched+1, maxAttempts)
+			launch()
+			launched++
+			inflight++
+
+		case <-timer.C:
+			if launched < maxAttempts {
+				log.Printf("🪃 GetUser slow (> %s), sending hedged request (attempt %d/%d)", delay, launched+1, maxAttempts)
+				launch()
+				launched++
+				inflight++
+				timer.Reset(delay)
+			}
+
+		case <-ctx.Done():
+			// Status gRPC (bukan error context mentah), supaya timeout tetap jadi 504
+			// dan stale-while-down tetap jalan (lihat isUpstreamDown)
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
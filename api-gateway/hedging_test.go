@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// slowFirstBackend: GetUser pertama lambat (slowDelay, atau sampai di-cancel), call berikutnya langsung jawab
+type slowFirstBackend struct {
+	pb.UnimplementedUserServiceServer
+	slowDelay     time.Duration
+	allSlow       bool // true = semua call lambat, bukan hanya yang pertama
+	calls         atomic.Int32
+	slowCancelled atomic.Bool // true kalau attempt lambat di-cancel karena kalah
+	notFound      bool
+}
+
+func (b *slowFirstBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	if b.calls.Add(1) == 1 || b.allSlow {
+		select {
+		case <-time.After(b.slowDelay):
+		case <-ctx.Done():
+			b.slowCancelled.Store(true)
+			return nil, ctx.Err()
+		}
+	}
+	if b.notFound {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id, Name: "Alice"}}, nil
+}
+
+func newHedgeTestRouter(t *testing.T, backend *slowFirstBackend, env map[string]string) http.Handler {
+	t.Helper()
+	upstream := startUserService(t, backend)
+	env["GRPC_MAX_RETRIES"] = "0"
+	return testRouter(t, newTestGateway(t, testConfig(t, env), upstream.addr))
+}
+
+func TestHedgingKicksInForSlowBackend(t *testing.T) {
+	backend := &slowFirstBackend{slowDelay: 2 * time.Second}
+	router := newHedgeTestRouter(t, backend, map[string]string{"HEDGE_DELAY": "20ms", "HEDGE_MAX_ATTEMPTS": "2"})
+
+	start := time.Now()
+	rec := doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	elapsed := time.Since(start)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body)
+	}
+	if elapsed >= backend.slowDelay {
+		t.Fatalf("request took %s, want the hedged attempt to answer before the slow one (%s)", elapsed, backend.slowDelay)
+	}
+	if got := backend.calls.Load(); got != 2 {
+		t.Fatalf("GetUser calls = %d, want 2 (original + hedge)", got)
+	}
+	// Attempt yang kalah di-cancel
+	deadline := time.Now().Add(time.Second)
+	for !backend.slowCancelled.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !backend.slowCancelled.Load() {
+		t.Fatal("losing attempt was not cancelled")
+	}
+}
+
+func TestHedgingDisabledByDefault(t *testing.T) {
+	backend := &slowFirstBackend{slowDelay: 100 * time.Millisecond}
+	router := newHedgeTestRouter(t, backend, map[string]string{})
+
+	start := time.Now()
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if got := backend.calls.Load(); got != 1 {
+		t.Fatalf("GetUser calls = %d, want 1 without HEDGE_DELAY", got)
+	}
+	if elapsed := time.Since(start); elapsed < backend.slowDelay {
+		t.Fatalf("request took %s, want to wait for the only attempt", elapsed)
+	}
+}
+
+func TestHedgingBusinessErrorNotRetried(t *testing.T) {
+	backend := &slowFirstBackend{notFound: true}
+	router := newHedgeTestRouter(t, backend, map[string]string{"HEDGE_DELAY": "200ms", "HEDGE_MAX_ATTEMPTS": "3"})
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404", rec.Code)
+	}
+	if got := backend.calls.Load(); got != 1 {
+		t.Fatalf("GetUser calls = %d, want 1 (NotFound is not hedged)", got)
+	}
+}
+
+func TestHedgingTimeoutReturnsGatewayTimeout(t *testing.T) {
+	// Semua attempt lebih lambat dari GATEWAY_RPC_TIMEOUT → 504, bukan 500 dari error context mentah
+	backend := &slowFirstBackend{slowDelay: 2 * time.Second, allSlow: true}
+	router := newHedgeTestRouter(t, backend, map[string]string{
+		"HEDGE_DELAY": "10ms", "HEDGE_MAX_ATTEMPTS": "2", "GATEWAY_RPC_TIMEOUT": "50ms",
+	})
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d, want 504 (body: %s)", rec.Code, rec.Body)
+	}
+}
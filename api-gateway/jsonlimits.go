@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+)
+
+// errJSONLimit menandai body yang melanggar batas depth/token (→ 400)
+var errJSONLimit = errors.New("JSON limit exceeded")
+
+// jsonLimits adalah batas struktur JSON yang diizinkan (0 = tanpa batas)
+type jsonLimits struct {
+	maxDepth  int
+	maxTokens int
+}
+
+// checkJSONLimits men-scan body token per token (streaming, tanpa membangun tree)
+// dan berhenti begitu batas terlewati, jadi payload jahat tidak sempat
+// di-unmarshal penuh ke memory / ke proto message
+func checkJSONLimits(body []byte, limits jsonLimits) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // Angka tidak perlu di-parse ke float untuk sekadar dihitung
+
+	depth, tokens := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+
+		tokens++
+		if limits.maxTokens > 0 && tokens > limits.maxTokens {
+			return fmt.Errorf("%w: more than %d tokens", errJSONLimit, limits.maxTokens)
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+				if limits.maxDepth > 0 && depth > limits.maxDepth {
+					return fmt.Errorf("%w: nesting deeper than %d", errJSONLimit, limits.maxDepth)
+				}
+			case '}', ']':
+				depth--
+			}
+		}
+	}
+}
+
+// limitJSONBody adalah middleware paling luar untuk route dengan body JSON:
+//  1. Batas ukuran body (maxBodyBytes) → 413
+//  2. Batas depth & jumlah token → 400
+//
+// Dicek SEBELUM schema validation dan json.Decode di handler
+func (gw *APIGateway) limitJSONBody(next http.HandlerFunc) http.HandlerFunc {
+	limits := jsonLimits{maxDepth: gw.cfg.JSONMaxDepth, maxTokens: gw.cfg.JSONMaxTokens}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Method == http.MethodGet {
+			next(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			http.Error(w, "request body too large or unreadable", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err := checkJSONLimits(body, limits); err != nil {
+			if errors.Is(err, errJSONLimit) {
+				log.Printf("🚫 Request body rejected: %v", err)
+			}
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Body sudah dibaca habis → ganti dengan reader baru untuk middleware/handler berikutnya
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(w, r)
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func nestedJSON(depth int) string {
+	return `{"name":"Alice","email":"alice@example.com","extra":` + strings.Repeat("[", depth) + strings.Repeat("]", depth) + `}`
+}
+
+func TestCheckJSONLimits(t *testing.T) {
+	limits := jsonLimits{maxDepth: 8, maxTokens: 50}
+
+	tests := []struct {
+		name      string
+		body      string
+		wantLimit bool // true = errJSONLimit, false = lolos
+	}{
+		{"flat object", `{"name":"Alice","age":30}`, false},
+		{"depth at limit", nestedJSON(7), false},
+		{"depth over limit", nestedJSON(8), true},
+		{"too many tokens", `[` + strings.Repeat(`1,`, 60) + `1]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkJSONLimits([]byte(tt.body), limits)
+			if got := errors.Is(err, errJSONLimit); got != tt.wantLimit {
+				t.Fatalf("err = %v, want limit error = %v", err, tt.wantLimit)
+			}
+			if !tt.wantLimit && err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+		})
+	}
+
+	if err := checkJSONLimits([]byte(nestedJSON(1000)), jsonLimits{}); err != nil {
+		t.Fatalf("zero limits: err = %v, want no limit", err)
+	}
+}
+
+func TestDeeplyNestedBodyRejectedBeforeUpstream(t *testing.T) {
+	backend := &writeBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"JSON_MAX_DEPTH": "16"})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	rec := doRequest(router, http.MethodPost, "/users", nestedJSON(10000), jsonHeader)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("deeply nested body: status = %d, want 400", rec.Code)
+	}
+	if got := len(backend.createRequests()); got != 0 {
+		t.Fatalf("CreateUser calls = %d, want 0", got)
+	}
+
+	big := `{"name":"` + strings.Repeat("a", maxBodyBytes) + `"}`
+	if rec := doRequest(router, http.MethodPost, "/users", big, jsonHeader); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized body: status = %d, want 413", rec.Code)
+	}
+
+	if rec := doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com"}`, jsonHeader); rec.Code != http.StatusCreated {
+		t.Fatalf("normal body: status = %d (body: %s)", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+// Route lama berbasis query parameter (/users/get?id=x, dll) sebelum REST path (/users/{id})
+// Masih dilayani selama 1 release supaya client lama tidak langsung rusak, lalu dihapus.
+// Semua response route lama membawa header "Deprecation: true" + Link ke route pengganti
+
+// deprecated menandai response route lama
+func deprecated(w http.ResponseWriter, successor string) {
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", "<"+successor+">; rel=\"successor-version\"")
+}
+
+// legacyRedirect mengarahkan route lama ke route baru dengan 308 Permanent Redirect
+// 308 (bukan 301/302) supaya method & body dipertahankan: POST tetap POST, DELETE tetap DELETE
+// target menerima id dari query lama dan return path baru;
+// parameter query lain (fields, limit, dll) ikut diteruskan apa adanya
+func legacyRedirect(idRequired bool, target func(id string) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		id := query.Get("id")
+		if idRequired && id == "" {
+			http.Error(w, "id parameter required", http.StatusBadRequest)
+			return
+		}
+		query.Del("id")
+
+		location := target(id)
+		if encoded := query.Encode(); encoded != "" {
+			location += "?" + encoded
+		}
+
+		log.Printf("↪️  Deprecated route %s %s → %s", r.Method, r.URL.Path, location)
+		deprecated(w, location)
+		http.Redirect(w, r, location, http.StatusPermanentRedirect)
+	}
+}
+
+// legacyUpdate melayani PUT /users/update langsung (tanpa redirect):
+// id ada di body, jadi gateway tidak bisa menyusun /users/{id} tanpa membaca body
+func legacyUpdate(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		deprecated(w, "/users/{id}")
+		next(w, r)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	pb "api-gateway/proto/user"
+)
+
+// listUsersQuery adalah query string yang sama untuk GET /users dan /users/stream
+// ?limit=10&order_by=created_at&page_token=...&name_contains=al&include_deleted=true
+type listUsersQuery struct {
+	PageSize       int    // ?limit=, default 10, maksimal 100
+	PageToken      string // Dari meta.nextPageToken halaman sebelumnya (kosong = halaman pertama)
+	OrderBy        string // created_at | name, suffix _desc (divalidasi di server)
+	Filter         userFilterQuery
+	IncludeDeleted bool // User soft-deleted (SOFT_DELETE=true di User Service) hanya ikut kalau diminta
+}
+
+// parseListUsersQuery membaca & memvalidasi query string list; error → 400 di handler
+func parseListUsersQuery(r *http.Request) (listUsersQuery, error) {
+	q := listUsersQuery{
+		PageToken: r.URL.Query().Get("page_token"),
+		OrderBy:   r.URL.Query().Get("order_by"),
+	}
+
+	var err error
+	if q.PageSize, err = parsePageSize(r); err != nil {
+		return listUsersQuery{}, errors.New("limit must be a positive integer")
+	}
+	if q.Filter, err = parseUserFilterQuery(r.URL.Query()); err != nil {
+		return listUsersQuery{}, err
+	}
+	if raw := r.URL.Query().Get("include_deleted"); raw != "" {
+		if q.IncludeDeleted, err = strconv.ParseBool(raw); err != nil {
+			return listUsersQuery{}, errors.New("include_deleted must be true or false")
+		}
+	}
+	return q, nil
+}
+
+// ListUsersHandler menghandle GET /users lewat ListUsersPage (Unary RPC)
+// 1 halaman = 1 RPC, jadi lebih sederhana daripada aggregate stream; meta.totalSize
+// berisi jumlah semua user yang cocok filter. Client protobuf frames (Accept:
+// application/x-protobuf-stream) tetap dilayani oleh StreamUsersHandler
+func (gw *APIGateway) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if wantsProtobufStream(r) {
+		gw.StreamUsersHandler(w, r)
+		return
+	}
+
+	// 2. PARSE QUERY (page size, urutan, page token, filter)
+	q, err := parseListUsersQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received ListUsersPage request (limit: %d, order_by: %q)", q.PageSize, q.OrderBy)
+
+	// 3. CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD (Unary RPC)
+	resp, err := gw.userClient.ListUsersPage(ctx, &pb.ListUsersPageRequest{
+		PageSize:       int32(q.PageSize),
+		PageToken:      q.PageToken,
+		OrderBy:        q.OrderBy,
+		NameContains:   q.Filter.NameContains,
+		EmailDomain:    q.Filter.EmailDomain,
+		MinAge:         q.Filter.MinAge,
+		MaxAge:         q.Filter.MaxAge,
+		IncludeDeleted: q.IncludeDeleted,
+	})
+	if err != nil {
+		logGRPCError(r, err)
+		// page_token / order_by / filter tidak valid → 400
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ Users page received: %d of %d", len(resp.Users), resp.TotalSize)
+
+	// 5. RETURN COLLECTION: {"users": [...], "meta": {...}}, halaman kosong tetap "users": []
+	meta := buildPageMeta(len(resp.Users), q.PageSize, resp.NextPageToken, false)
+	meta.TotalSize = &resp.TotalSize
+	writeCollectionGuarded(w, r, gw.slowClientPolicy(), "users", protoValues(resp.Users), meta)
+}
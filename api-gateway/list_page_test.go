@@ -0,0 +1,87 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestListUsersUsesUnaryPage(t *testing.T) {
+	// pageBackend tidak mengimplementasikan ListUsers: GET /users harus lewat ListUsersPage
+	upstream := startUserService(t, newPageBackend(3))
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users?limit=2", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Fatalf("Content-Type = %q, want a single JSON document", got)
+	}
+	var body struct {
+		Users []struct {
+			ID string `json:"id"`
+		} `json:"users"`
+		Meta PageMeta `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Users) != 2 || body.Users[0].ID != "u1" || body.Meta.NextPageToken != "next-u2" {
+		t.Fatalf("body = %+v, want first page of 2 with next token", body)
+	}
+}
+
+func TestListUsersEmptyPage(t *testing.T) {
+	upstream := startUserService(t, newPageBackend(0))
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	// Halaman kosong tetap array, bukan null
+	if !strings.Contains(rec.Body.String(), `"users":[]`) {
+		t.Fatalf("body = %s, want \"users\":[]", rec.Body)
+	}
+	var body struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Meta["count"] != 0.0 || body.Meta["hasMore"] != false || body.Meta["totalSize"] != 0.0 {
+		t.Fatalf("meta = %v, want count 0, hasMore false, totalSize 0", body.Meta)
+	}
+}
+
+func TestStreamUsersUsesServerStream(t *testing.T) {
+	// listBackend hanya punya ListUsers (streaming)
+	upstream := startUserService(t, newListBackend(3, ""))
+	srv := serveGateway(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	resp := getStream(t, srv.URL+"/users/stream", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var users int
+	for _, ev := range readSSE(t, resp) {
+		if ev.name == "user" {
+			users++
+		}
+	}
+	if users != 3 {
+		t.Fatalf("user events = %d, want 3", users)
+	}
+}
+
+func TestLegacyListRedirectsToUsers(t *testing.T) {
+	upstream := startUserService(t, newPageBackend(0))
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/list?limit=5", "", nil)
+	if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != "/users?limit=5" {
+		t.Fatalf("status = %d, Location = %q; want 308 to /users?limit=5", rec.Code, rec.Header().Get("Location"))
+	}
+}
@@ -3,63 +3,172 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	// Import konfigurasi dari environment
+	"api-gateway/config"
+	// Import metrics (Prometheus + OpenTelemetry)
+	"api-gateway/metrics"
 	// Import proto (sama seperti di server)
 	pb "api-gateway/proto/user"
+	// Import structured logging (log/slog)
+	"api-gateway/logging"
+	// Import PII redaction untuk log
+	"api-gateway/redact"
+	// Import setup OpenTelemetry tracing
+	"api-gateway/tracing"
 
 	// gRPC client packages
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	// Instrumentasi OpenTelemetry untuk HTTP server dan gRPC client
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 // APIGateway struct menyimpan gRPC client connections
 // Pattern ini memungkinkan kita connect ke multiple microservices
 type APIGateway struct {
-	userClient pb.UserServiceClient // gRPC client untuk User Service
+	conn         *grpc.ClientConn      // Koneksi ke User Service (state-nya dicek di /health/detail)
+	userClient   pb.UserServiceClient  // gRPC client untuk User Service
+	healthClient healthpb.HealthClient // gRPC health client untuk cek readiness User Service
+	staleUsers   *staleCache           // Last-known users untuk fallback saat upstream down (nil = disabled)
+	responses    *responseCache        // Response cache untuk GET /users/{id} (nil = disabled)
+	schemas      *schemaValidator      // JSON schema per route (nil = validation disabled)
+	cfg          *config.Config        // Konfigurasi gateway (admin, dll)
+	health       healthTracker         // Error terakhir per komponen untuk /health/detail
+	rpcStatus    *rpcStatusTracker     // Last-success & last-error per gRPC method untuk /debug/rpc-status
 	// orderClient pb.OrderServiceClient // Contoh: service lain
 	// productClient pb.ProductServiceClient // Contoh: service lain
 }
 
 // NewAPIGateway adalah constructor yang membuat koneksi ke gRPC services
-// Parameter: address dari masing-masing service
-func NewAPIGateway(userServiceAddr string) (*APIGateway, error) {
-	log.Println("🔌 Connecting to User Service at", userServiceAddr)
+// Parameter: address instance User Service (1 atau lebih, di-load-balance round-robin)
+// + konfigurasi gateway + metrics
+func NewAPIGateway(userServiceAddrs []string, cfg *config.Config, m *metrics.Metrics) (*APIGateway, error) {
+	log.Println("🔌 Connecting to User Service at", strings.Join(userServiceAddrs, ", "))
+
+	creds, err := transportCredentials(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %v", err)
+	}
+
+	rpcStatus := &rpcStatusTracker{}
+
+	// Target + resolver untuk semua instance User Service (lihat upstream.go)
+	target, resolverOpt := upstreamTarget(userServiceAddrs)
 
 	// CREATE gRPC CLIENT CONNECTION
 	// grpc.NewClient() membuat connection (lazy connection)
 	// Actual connection dibuat saat first RPC call
+	// 1 ClientConn untuk semua instance: subconnection per backend dikelola balancer
 	conn, err := grpc.NewClient(
-		userServiceAddr, // Address service: "localhost:50051"
+		target, // contoh: "localhost:50051", atau "static:///user-service" untuk banyak instance
+		resolverOpt,
+
+		// Round-robin ke semua backend sehat + retry otomatis untuk read idempotent
+		// (GetUser, ListUsers) saat UNAVAILABLE, sebanyak GRPC_MAX_RETRIES
+		grpc.WithDefaultServiceConfig(serviceConfig(cfg.GRPCMaxRetries)),
 		
 		// WithTransportCredentials: cara authentication/encryption
-		// insecure.NewCredentials() = tanpa TLS (hanya untuk development!)
-		// Production: pakai credentials.NewClientTLSFromFile()
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		// TLS_CA_FILE → TLS, + TLS_CLIENT_CERT_FILE → mutual TLS (lihat tls.go)
+		// Plaintext hanya kalau INSECURE=true (development only!)
+		grpc.WithTransportCredentials(creds),
+
+		// Propagate trace-context (traceparent) ke User Service
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+
+		// Kompresi opsional (GRPC_COMPRESSION=gzip) untuk semua RPC, terutama stream besar
+		grpc.WithDefaultCallOptions(compressionCallOptions(cfg.GRPCCompression)...),
+
+		// Batas ukuran message (GRPC_MAX_MSG_SIZE_MB), harus sama dengan User Service
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(cfg.GRPCMaxMsgSizeMB<<20),
+			grpc.MaxCallSendMsgSize(cfg.GRPCMaxMsgSizeMB<<20),
+		),
+
+		// Keepalive: ping koneksi idle (juga saat tidak ada RPC aktif) supaya koneksi
+		// yang sudah diputus LB/NAT ketahuan sebelum request berikutnya memakainya
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                cfg.KeepaliveTime,
+			Timeout:             cfg.KeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+
+		// Catat hasil terakhir setiap method untuk /debug/rpc-status
+		grpc.WithChainUnaryInterceptor(rpcStatus.unaryInterceptor()),
+		grpc.WithChainStreamInterceptor(rpcStatus.streamInterceptor()),
+
+		// Teruskan X-Request-ID sebagai metadata "x-request-id" (korelasi log gateway ↔ User Service)
+		grpc.WithChainUnaryInterceptor(requestIDUnaryInterceptor()),
+		grpc.WithChainStreamInterceptor(requestIDStreamInterceptor()),
 		
 		// Options lain (opsional):
 		// grpc.WithBlock() - tunggu sampai connected (synchronous)
 		// grpc.WithTimeout() - timeout untuk connection
-		// grpc.WithDefaultCallOptions() - default options untuk semua RPC calls
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to user service: %v", err)
 	}
 
 	log.Println("✅ Connected to User Service")
+	log.Printf("📦 Max gRPC message size: %d MB", cfg.GRPCMaxMsgSizeMB)
 
 	// CREATE CLIENT STUB
 	// NewUserServiceClient() di-generate dari proto
 	// Stub ini berisi semua method yang bisa dipanggil
 	client := pb.NewUserServiceClient(conn)
 
-	return &APIGateway{
-		userClient: client,
-	}, nil
+	gw := &APIGateway{
+		conn:         conn,
+		userClient:   client,
+		healthClient: healthpb.NewHealthClient(conn),
+		cfg:          cfg,
+		rpcStatus:    rpcStatus,
+	}
+
+	// Stale-while-down (opsional): ingat user terakhir yang sukses dibaca
+	if cfg.StaleWhileDown {
+		gw.staleUsers = newStaleCache(cfg.StaleCacheSize)
+		log.Println("🧊 Stale-while-down enabled for GetUser")
+	}
+
+	// Response cache (opsional): jawab GET /users/{id} dari memory selama TTL
+	if cfg.ResponseCacheTTL > 0 {
+		responses, err := newResponseCache(cfg.ResponseCacheTTL, cfg.ResponseCacheSize, m.Meter())
+		if err != nil {
+			return nil, fmt.Errorf("failed to create response cache: %v", err)
+		}
+		gw.responses = responses
+		log.Printf("🗃️  Response cache enabled for GetUser (ttl: %s)", cfg.ResponseCacheTTL)
+	}
+
+	// JSON schema validation (opsional): tolak body invalid sebelum sampai ke gRPC
+	if cfg.SchemaValidation {
+		schemas, err := newSchemaValidator(cfg.SchemaDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load JSON schemas: %v", err)
+		}
+		gw.schemas = schemas
+		log.Printf("📐 JSON schema validation enabled (%d schemas)", len(schemas.schemas))
+	}
+
+	return gw, nil
 }
 
 // CreateUserHandler adalah HTTP handler yang mengkonversi HTTP request ke gRPC call
@@ -72,59 +181,77 @@ func (gw *APIGateway) CreateUserHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	// 2. PARSE HTTP REQUEST BODY (JSON)
-	// Struct anonymous untuk input dari client
-	var req struct {
-		Name  string `json:"name"`
-		Email string `json:"email"`
-		Age   int32  `json:"age"`
+	// Langsung ke proto message (proto JSON mapping: "name", "email", "age", "status")
+	// Enum status case-insensitive: "active", "PENDING", "USER_STATUS_ACTIVE", dll
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
-
-	// Decode JSON dari request body
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	req := &pb.CreateUserRequest{}
+	if err := decodeProtoJSON(body, req); err != nil {
+		var enumErr *enumError
+		if errors.As(err, &enumErr) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": err.Error(),
+				"field": enumErr.Field,
+				"valid": enumErr.Valid,
+			})
+			return
+		}
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("📥 Received CreateUser request: %s (%s)", req.Name, req.Email)
+	log.Printf("📥 Received CreateUser request: %s (%s)", redact.Field("name", req.Name), redact.Field("email", req.Email))
 
 	// 3. CREATE CONTEXT dengan TIMEOUT
 	// Context penting untuk:
 	// - Timeout: batalkan request jika terlalu lama
-	// - Cancellation: user cancel request
-	// - Deadline: hard deadline untuk request
+	// - Cancellation: user cancel request (diturunkan dari r.Context(): HTTP client
+	//   disconnect → gRPC call ikut dibatalkan dengan context.Canceled)
+	// - Deadline: hard deadline untuk request (GATEWAY_RPC_TIMEOUT)
 	// - Metadata: kirim extra info (auth token, trace ID, dll)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
 	defer cancel() // Cleanup context
 
 	// 4. CALL gRPC METHOD
 	// userClient.CreateUser() adalah blocking call
 	// Request: HTTP JSON → Protobuf binary
 	// Response: Protobuf binary → Go struct
-	resp, err := gw.userClient.CreateUser(ctx, &pb.CreateUserRequest{
-		Name:  req.Name,
-		Email: req.Email,
-		Age:   req.Age,
-	})
+	resp, err := gw.userClient.CreateUser(ctx, req)
 
 	// 5. ERROR HANDLING
 	if err != nil {
-		log.Printf("❌ gRPC call failed: %v", err)
-		
-		// Bisa check specific gRPC status codes:
-		// status.Code(err) == codes.NotFound
-		// status.Code(err) == codes.InvalidArgument
-		// status.Code(err) == codes.DeadlineExceeded
+		logGRPCError(r, err)
 		
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		// gRPC status code → HTTP status code (lihat grpcToHTTPStatus):
+		// InvalidArgument → 400, AlreadyExists (email terdaftar) → 409,
+		// read-only mode → 503 (sementara), dll
+		writeGRPCError(w, err)
 		return
 	}
 
 	log.Printf("✅ User created: %s", resp.User.Id)
 
+	// User baru juga langsung diingat untuk fallback GetUser
+	// (CreateUser sendiri TIDAK pernah disajikan stale — write harus fail fast)
+	if gw.staleUsers != nil {
+		gw.staleUsers.Put(principalKey(r), resp.User)
+	}
+
 	// 6. RETURN HTTP RESPONSE (JSON)
-	// Convert protobuf response → JSON untuk HTTP client
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	// Convert protobuf response → JSON (protojson) untuk HTTP client
+	// REST convention: 201 Created + Location yang menunjuk ke resource baru
+	w.Header().Set("Location", userLocation(resp.User.Id))
+	writeProtoJSON(w, http.StatusCreated, resp)
+}
+
+// userLocation return path resource 1 user: /users/{id} (dipakai untuk Location header)
+func userLocation(id string) string {
+	return "/users/" + url.PathEscape(id)
 }
 
 // GetUserHandler menghandle GET request untuk ambil user by ID
@@ -136,142 +263,430 @@ func (gw *APIGateway) GetUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 2. PARSE QUERY PARAMETER
-	// URL: /users/get?id=123
-	userId := r.URL.Query().Get("id")
+	// 2. PARSE PATH PARAMETER
+	// URL: /users/123 (route "GET /users/{id}")
+	userId := r.PathValue("id")
 	if userId == "" {
 		http.Error(w, "id parameter required", http.StatusBadRequest)
 		return
 	}
 
+	// Field mask opsional: ?fields=name,email → hanya field itu yang dikembalikan (divalidasi di server)
+	fieldMask := parseFieldList(r.URL.Query().Get("fields"))
+
 	log.Printf("📥 Received GetUser request: %s", userId)
 
 	// 3. CONTEXT dengan TIMEOUT
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
 	defer cancel()
 
-	// 4. CALL gRPC METHOD (Unary RPC)
-	resp, err := gw.userClient.GetUser(ctx, &pb.GetUserRequest{
-		Id: userId,
+	// Client minta data fresh (Cache-Control: no-cache) → bypass juga read cache di User Service
+	if strings.Contains(r.Header.Get("Cache-Control"), "no-cache") {
+		ctx = metadata.AppendToOutgoingContext(ctx, "consistency", "strong")
+	}
+
+	// 4. CALL gRPC METHOD (Unary RPC, dengan hedging kalau diaktifkan)
+	resp, err := gw.getUser(ctx, &pb.GetUserRequest{
+		Id:        userId,
+		FieldMask: fieldMask,
 	})
 
 	// 5. ERROR HANDLING
 	if err != nil {
-		log.Printf("❌ gRPC call failed: %v", err)
-		http.Error(w, err.Error(), http.StatusNotFound)
+		logGRPCError(r, err, "user_id", userId)
+
+		// Upstream down? Sajikan last-known data (kalau fitur aktif & ada di cache)
+		// Ditandai "stale": true supaya client tahu datanya mungkin sudah basi
+		// Hanya user yang pernah dibaca dengan credential yang sama (auth User Service tidak bisa
+		// dijalankan), dan tidak untuk ?fields=: cache berisi user lengkap, bukan hasil mask
+		if gw.staleUsers != nil && len(fieldMask) == 0 && isUpstreamDown(err) {
+			if user := gw.staleUsers.Get(principalKey(r), userId); user != nil {
+				log.Printf("🧊 Serving stale user: %s", userId)
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("Cache-Control", "no-store") // Data stale tidak boleh masuk response cache
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"user":  protoValue{msg: user},
+					"stale": true,
+				})
+				return
+			}
+		}
+
+		// NotFound → 404, nama field di ?fields= tidak dikenal → 400, dll
+		writeGRPCError(w, err)
 		return
 	}
 
-	log.Printf("✅ User found: %s", resp.User.Name)
+	log.Printf("✅ User found: %s", redact.Field("name", resp.User.Name))
+
+	// Simpan sebagai last-known version untuk fallback (hanya user lengkap, bukan hasil mask)
+	if gw.staleUsers != nil && len(fieldMask) == 0 {
+		gw.staleUsers.Put(principalKey(r), resp.User)
+	}
 
 	// 6. RETURN RESPONSE
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(resp)
+	if len(fieldMask) > 0 {
+		// Response cache hanya di-invalidate per ?id=, jadi variasi ?fields= tidak boleh disimpan
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	writeProtoJSON(w, http.StatusOK, resp)
 }
 
-// ListUsersHandler menghandle streaming response dari gRPC
-// Ini contoh bagaimana handle Server Streaming RPC
-func (gw *APIGateway) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+// parseFieldList memecah "name, email" menjadi ["name", "email"] (entry kosong dibuang)
+func parseFieldList(raw string) []string {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// StreamUsersHandler menghandle GET /users/stream lewat ListUsers (Server Streaming RPC)
+// Ini contoh bagaimana handle Server Streaming RPC: setiap user diteruskan secara incremental
+// sebagai Server-Sent Events, atau protobuf frames (Accept: application/x-protobuf-stream)
+// Query parameter sama dengan GET /users (lihat parseListUsersQuery)
+func (gw *APIGateway) StreamUsersHandler(w http.ResponseWriter, r *http.Request) {
 	// 1. VALIDASI METHOD
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	log.Println("📥 Received ListUsers request")
+	// 2. PARSE QUERY (page size, urutan, page token, filter)
+	q, err := parseListUsersQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received ListUsers request (limit: %d, order_by: %q)", q.PageSize, q.OrderBy)
 
-	// 2. CONTEXT dengan TIMEOUT (lebih lama untuk streaming)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 3. CONTEXT dengan TIMEOUT (lebih lama untuk streaming)
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), 30*time.Second)
 	defer cancel()
 
-	// 3. CALL gRPC STREAMING METHOD
+	// 4. CALL gRPC STREAMING METHOD
 	// Ini return stream object, bukan response langsung
-	stream, err := gw.userClient.ListUsers(ctx, &pb.ListUsersRequest{
-		Limit: 10,
+	// Server yang menentukan apakah masih ada halaman berikutnya (next_page_token)
+	// Open stream di-retry kalau backend sementara tidak tersedia (lihat stream_retry.go)
+	stream, err := gw.openUserStream(ctx, func(ctx context.Context) (userStream, error) {
+		return gw.userClient.ListUsers(ctx, &pb.ListUsersRequest{
+			Limit:          int32(q.PageSize),
+			PageToken:      q.PageToken,
+			OrderBy:        q.OrderBy,
+			NameContains:   q.Filter.NameContains,
+			EmailDomain:    q.Filter.EmailDomain,
+			MinAge:         q.Filter.MinAge,
+			MaxAge:         q.Filter.MaxAge,
+			IncludeDeleted: q.IncludeDeleted,
+		})
 	})
 
 	if err != nil {
-		log.Printf("❌ gRPC call failed: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logGRPCError(r, err)
+		// page_token / order_by tidak valid → 400 (validasi di server, muncul di Recv pertama)
+		writeGRPCError(w, err)
 		return
 	}
 
-	// 4. RECEIVE STREAMING DATA
-	var users []*pb.User
-	
-	// Loop untuk receive semua messages dari stream
-	for {
-		// stream.Recv() adalah blocking call
-		// Akan wait sampai message baru datang atau stream selesai
-		resp, err := stream.Recv()
-		
-		// EOF = End of File = stream selesai (sukses)
-		if err == io.EOF {
-			log.Println("✅ Stream finished")
-			break // Keluar dari loop
-		}
-		
-		// Error lain = ada masalah
-		if err != nil {
-			log.Printf("❌ Stream error: %v", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		
-		// Append user ke slice
-		users = append(users, resp.User)
-		log.Printf("📦 Received user: %s", resp.User.Name)
+	// 5a. MODE PROTOBUF STREAM: teruskan setiap user sebagai frame secara incremental
+	// (mode machine-to-machine, next page token dikirim sebagai trailer X-Next-Page-Token)
+	if wantsProtobufStream(r) {
+		streamProtobufFrames(w, stream, gw.flushPolicy())
+		return
 	}
 
-	log.Printf("✅ Total users received: %d", len(users))
+	// 5b. MODE SSE: setiap user dikirim sebagai event begitu diterima dari stream
+	// (hasil lengkap dalam 1 JSON tersedia di GET /users)
+	streamSSE(w, stream, cancel)
+}
 
-	// 5. RETURN AGGREGATED RESPONSE
-	// Convert semua streaming data menjadi 1 HTTP response
+// BulkDeleteUsersHandler menghandle DELETE /users?olderThan=...&domain=...&confirm=true
+// Endpoint admin untuk cleanup massal, dibungkus requireAdmin di routing
+func (gw *APIGateway) BulkDeleteUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE FILTER + KONFIRMASI
+	// confirm=true wajib supaya tidak ada mass deletion karena salah klik/salah script
+	query := r.URL.Query()
+	if query.Get("confirm") != "true" {
+		http.Error(w, "bulk delete requires confirm=true", http.StatusBadRequest)
+		return
+	}
+
+	olderThan := query.Get("olderThan")
+	domain := query.Get("domain")
+	if olderThan == "" && domain == "" {
+		http.Error(w, "at least one filter (olderThan, domain) is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received BulkDeleteUsers request (olderThan: %q, domain: %q)", olderThan, redact.Field("domain", domain))
+
+	// 3. CONTEXT dengan TIMEOUT (+ credential admin untuk User Service, lihat withAdminAuth)
+	ctx, cancel := context.WithTimeout(withAdminAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD
+	resp, err := gw.userClient.BulkDeleteUsers(ctx, &pb.BulkDeleteRequest{
+		OlderThan:   olderThan,
+		EmailDomain: domain,
+	})
+
+	// 5. ERROR HANDLING
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ Bulk deleted %d users", resp.DeletedCount)
+
+	// Gateway tidak tahu id mana saja yang terhapus → buang semua cached response,
+	// termasuk last-known users (yang terhapus tidak boleh tersaji lagi sebagai stale)
+	if gw.responses != nil && resp.DeletedCount > 0 {
+		gw.responses.Purge()
+	}
+	if gw.staleUsers != nil && resp.DeletedCount > 0 {
+		gw.staleUsers.Purge()
+	}
+
+	// 6. RETURN RESPONSE
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"users": users,
-		"count": len(users),
+		"deleted": resp.DeletedCount,
 	})
 }
 
+// routes memetakan HTTP endpoints ke handler functions
+// Pattern routing Go 1.22: "METHOD /path/{param}", param dibaca handler lewat r.PathValue
+// Method salah → 405 + header Allow otomatis dari mux. Route tetap (/users/count, dll)
+// wajib ikut memakai method, kalau tidak bentrok dengan /users/{id} (mux panic saat register)
+func (gw *APIGateway) routes(m *metrics.Metrics) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users", gw.limitJSONBody(gw.validateBody("create_user", gw.CreateUserHandler)))
+	mux.HandleFunc("GET /users", gw.ListUsersHandler)
+	mux.HandleFunc("GET /users/{id}", gw.cacheGET(gw.GetUserHandler))
+	mux.HandleFunc("PUT /users/{id}", gw.limitJSONBody(gw.validateBody("update_user", gw.UpdateUserHandler)))
+	mux.HandleFunc("DELETE /users/{id}", gw.DeleteUserHandler)
+	mux.HandleFunc("PUT /users/{id}/roles", gw.limitJSONBody(gw.SetUserRolesHandler))
+	mux.HandleFunc("POST /users/batch", gw.limitJSONBody(gw.BatchCreateUsersHandler))
+	mux.HandleFunc("GET /users/by-ids", gw.GetUsersByIdsHandler)
+	mux.HandleFunc("POST /users/restore", gw.RestoreUserHandler)
+	mux.HandleFunc("GET /users/stream", gw.StreamUsersHandler)
+	mux.HandleFunc("GET /users/count", gw.CountUsersHandler)
+	mux.HandleFunc("GET /users/by-date", gw.ListUsersByDateHandler)
+	mux.HandleFunc("GET /users/export.csv", gw.ExportUsersCSVHandler)
+	mux.HandleFunc("POST /users/resolve", gw.limitJSONBody(gw.ResolveUsersHandler))
+	mux.HandleFunc("POST /auth/login", gw.limitJSONBody(gw.LoginHandler))
+
+	// Route lama (deprecated, dihapus release berikutnya) → redirect 308 ke route REST (lihat legacy.go)
+	mux.HandleFunc("POST /users/create", legacyRedirect(false, func(string) string { return "/users" }))
+	mux.HandleFunc("GET /users/get", legacyRedirect(true, userLocation))
+	mux.HandleFunc("PUT /users/update", legacyUpdate(gw.limitJSONBody(gw.validateBody("update_user", gw.UpdateUserHandler))))
+	mux.HandleFunc("DELETE /users/delete", legacyRedirect(true, userLocation))
+	mux.HandleFunc("GET /users/list", legacyRedirect(false, func(string) string { return "/users" }))
+
+	// Admin endpoints (butuh ADMIN_ENABLED=true + ADMIN_TOKEN)
+	// Kalau auth User Service aktif, RPC-nya juga butuh X-User-Authorization (JWT role admin) atau X-API-Key
+	mux.HandleFunc("DELETE /users", gw.requireAdmin(gw.BulkDeleteUsersHandler))
+	mux.HandleFunc("/debug/config", gw.requireAdmin(gw.DebugConfigHandler))
+	mux.HandleFunc("/debug/rpc-status", gw.requireAdmin(gw.RPCStatusHandler))
+	mux.HandleFunc("/admin/read-only", gw.requireAdmin(gw.ReadOnlyHandler))
+	mux.HandleFunc("/admin/compact", gw.requireAdmin(gw.CompactHandler))
+	mux.HandleFunc("/admin/verify-integrity", gw.requireAdmin(gw.VerifyIntegrityHandler))
+	mux.HandleFunc("/health/detail", gw.requireAdmin(gw.HealthDetailHandler))
+
+	// Metrics endpoint untuk Prometheus scraping
+	mux.Handle("/metrics", m.Handler())
+
+	// Health check endpoint (untuk load balancer/monitoring), ikut probe User Service
+	mux.HandleFunc("/health", gw.HealthHandler)
+
+	// Readiness endpoint: 503 selama User Service belum SERVING (misal masih warmup)
+	mux.HandleFunc("/readyz", gw.ReadyzHandler)
+
+	return mux
+}
+
 func main() {
 	log.Println("🚀 Starting API Gateway...")
 
+	// 0. LOAD CONFIG
+	// Fail fast kalau ada environment variable yang tidak valid
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+
+	// Structured logging (log/slog): LOG_FORMAT=json untuk production, text untuk development
+	// PII redaction: log aplikasi & access log melewati redact.Writer (email → salted hash)
+	logging.Setup(cfg.LogFormat, redact.Writer(os.Stderr))
+	if cfg.PIILogRedaction {
+		redact.Enable(cfg.PIILogSalt, cfg.PIILogFields)
+		log.Printf("🙈 PII redaction enabled for log fields: %v", cfg.PIILogFields)
+	}
+
+	// Protobuf message di response ditulis dengan proto JSON mapping (lihat proto_json.go)
+	protoJSON = protojson.MarshalOptions{
+		EmitUnpopulated: cfg.JSONEmitDefaults,
+		UseProtoNames:   cfg.JSONUseProtoNames,
+	}
+
+	// Setup tracing dengan sampling rate dari config
+	// Request yang datang dengan traceparent "sampled" dari client selalu di-trace
+	shutdownTracing, err := tracing.Setup(context.Background(), "api-gateway", cfg.TraceSampleRate, cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("❌ Failed to setup tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	log.Printf("🔭 Tracing enabled (sample rate: %.2f)", cfg.TraceSampleRate)
+
+	// Setup metrics: instrument yang sama di-export ke semua exporter yang dipilih
+	gatewayMetrics, err := metrics.Setup(context.Background(), "api-gateway", cfg.MetricsExporters, cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("❌ Failed to setup metrics: %v", err)
+	}
+	defer gatewayMetrics.Shutdown(context.Background())
+
+	log.Printf("📊 Metrics enabled (exporters: %v)", cfg.MetricsExporters)
+
 	// 1. CONNECT TO gRPC SERVICES
-	// Ini biasanya dari environment variable atau config file
-	gateway, err := NewAPIGateway("localhost:50051")
+	// Alamat User Service dari USER_SERVICE_ADDR (default localhost:50051, boleh banyak dipisah koma)
+	gateway, err := NewAPIGateway(cfg.UserServiceAddrs, cfg, gatewayMetrics)
 	if err != nil {
 		log.Fatalf("❌ Failed to create gateway: %v", err)
 	}
 
 	log.Println("✅ All gRPC connections established")
 
-	// 2. SETUP HTTP ROUTES
-	// Map HTTP endpoints ke handler functions
-	http.HandleFunc("/users/create", gateway.CreateUserHandler)
-	http.HandleFunc("/users/get", gateway.GetUserHandler)
-	http.HandleFunc("/users/list", gateway.ListUsersHandler)
+	// Startup probe (opsional): koneksi gRPC lazy, jadi tanpa ini gateway tetap "start"
+	// walau User Service tidak bisa dihubungi. /health tetap melaporkan kondisi sebenarnya setelahnya
+	if cfg.BackendReadyTimeout > 0 {
+		log.Printf("⏳ Waiting up to %s for User Service to be ready...", cfg.BackendReadyTimeout)
+		if err := gateway.waitForBackend(context.Background(), cfg.BackendReadyTimeout); err != nil {
+			if cfg.RequireBackendReady {
+				log.Fatalf("❌ %v (REQUIRE_BACKEND_READY=true)", err)
+			}
+			log.Printf("⚠️  %v, starting anyway (/health reports 503 until it recovers)", err)
+		} else {
+			log.Println("✅ User Service is ready")
+		}
+	}
 
-	// Health check endpoint (untuk load balancer/monitoring)
-	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("OK"))
-	})
+	// 2. SETUP HTTP ROUTES (lihat routes)
+	mux := gateway.routes(gatewayMetrics)
 
 	// 3. PRINT ROUTES INFO
-	log.Println("🌐 API Gateway running on :8080")
+	log.Printf("🌐 API Gateway running on :%d", cfg.HTTPPort)
+	base := fmt.Sprintf("http://localhost:%d", cfg.HTTPPort)
 	log.Println("📍 Endpoints:")
-	log.Println("   POST   http://localhost:8080/users/create")
-	log.Println("   GET    http://localhost:8080/users/get?id=xxx")
-	log.Println("   GET    http://localhost:8080/users/list")
-	log.Println("   GET    http://localhost:8080/health")
+	log.Println("   POST   " + base + "/users")
+	log.Println("   POST   " + base + "/users/batch [{\"name\": ..., \"email\": ...}, ...] (client streaming)")
+	log.Println("   GET    " + base + "/users/{id}?fields=name,email")
+	log.Println("   GET    " + base + "/users/by-ids?ids=xxx,yyy")
+	log.Println("   PUT    " + base + "/users/{id} {\"name\": ..., \"email\": ..., \"age\": ..., \"expectedVersion\": 1}")
+	log.Println("   DELETE " + base + "/users/{id}")
+	log.Println("   PUT    " + base + "/users/{id}/roles {\"roles\": [\"admin\"]} (role admin)")
+	log.Println("   POST   " + base + "/users/restore?id=xxx (soft delete)")
+	log.Println("   GET    " + base + "/users?limit=10&order_by=created_at&page_token=...&name_contains=al&email_domain=...&min_age=18&max_age=30&include_deleted=false")
+	log.Println("   GET    " + base + "/users/stream?limit=10&... (server streaming via SSE, Accept: application/x-protobuf-stream untuk protobuf frames)")
+	log.Println("   GET    " + base + "/users/count?status=active&name_contains=al&min_age=18")
+	log.Println("   GET    " + base + "/users/by-date?from=2024-01-01T00:00:00Z&to=2024-12-31T23:59:59Z")
+	log.Println("   GET    " + base + "/users/export.csv?limit=0")
+	log.Println("   POST   " + base + "/users/resolve {\"ids\": [...]} (Server-Sent Events)")
+	log.Println("   POST   " + base + "/auth/login {\"email\": ..., \"password\": ...}")
+	log.Println("   DELETE " + base + "/users?olderThan=...&domain=...&confirm=true (admin)")
+	log.Println("   GET    " + base + "/debug/config (admin)")
+	log.Println("   GET    " + base + "/debug/rpc-status (admin)")
+	log.Println("   POST   " + base + "/admin/read-only?enabled=true (admin)")
+	log.Println("   POST   " + base + "/admin/compact (admin)")
+	log.Println("   POST   " + base + "/admin/verify-integrity?repair=false (admin)")
+	log.Println("   GET    " + base + "/metrics")
+	log.Println("   GET    " + base + "/health")
+	log.Println("   GET    " + base + "/readyz")
+	log.Println("   GET    " + base + "/health/detail (admin)")
+	log.Println("   (deprecated) /users/create, /users/get, /users/update, /users/delete, /users/list")
 	log.Println("⏳ Press Ctrl+C to stop")
 
 	// 4. START HTTP SERVER
-	// ListenAndServe adalah blocking call
-	if err := http.ListenAndServe(":8080", nil); err != nil {
+	// srv.Serve adalah blocking call, jadi dijalankan di goroutine sampai ada signal shutdown
+	// Middleware chain (dari luar ke dalam):
+	// strip headers → otelhttp (tracing) → request logger → access log → metrics → per-client limit → CORS → router
+	// otelhttp membaca traceparent dari request HTTP dan membuat span per request
+	// stripHeaders paling luar supaya bisa membersihkan header dari SEMUA layer di dalamnya
+	resolver, err := newIPResolver(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	var handler http.Handler = mux
+	if len(cfg.CORSAllowedOrigins) > 0 {
+		// Paling dalam: preflight tetap tercatat di access log & metrics, tapi dijawab sebelum router
+		handler = cors(newCORSPolicy(cfg.CORSAllowedOrigins), handler)
+		log.Printf("🌍 CORS enabled for origins: %s", strings.Join(cfg.CORSAllowedOrigins, ", "))
+	}
+	if cfg.MaxConnsPerClient > 0 {
+		// Client di belakang trusted proxy: dibatasi per request (IP dari X-Forwarded-For)
+		handler = limitPerClient(newClientCounter(cfg.MaxConnsPerClient), resolver, handler)
+	}
+	handler = recordMetrics(gatewayMetrics, handler)
+	handler = accessLog(cfg.AccessLogFormat, redact.Writer(os.Stdout), resolver, handler)
+	handler = requestLogger(handler)
+	handler = otelhttp.NewHandler(handler, "api-gateway")
+	handler = stripHeaders(newHeaderPolicy(cfg.ResponseHeaderDenylist, cfg.ResponseHeaderAllowlist, cfg.ServerHeader), handler)
+
+	var lis net.Listener
+	if lis, err = net.Listen("tcp", fmt.Sprintf(":%d", cfg.HTTPPort)); err != nil {
+		log.Fatalf("❌ Failed to listen: %v", err)
+	}
+	if cfg.MaxConnsPerClient > 0 {
+		// Client langsung: dibatasi per koneksi TCP, sebelum request apapun dibaca
+		lis = &limitListener{Listener: lis, counter: newClientCounter(cfg.MaxConnsPerClient), resolver: resolver}
+		log.Printf("🚧 Max %d concurrent connections per client", cfg.MaxConnsPerClient)
+	}
+
+	// http.Server (bukan http.Serve) supaya bisa di-Shutdown dengan graceful
+	srv := &http.Server{Handler: handler}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
 		log.Fatalf("❌ Failed to start server: %v", err)
+	case <-ctx.Done():
 	}
+
+	// 5. GRACEFUL SHUTDOWN (Ctrl+C / SIGTERM)
+	// Shutdown berhenti menerima koneksi baru lalu menunggu request yang sedang jalan selesai;
+	// lewat SHUTDOWN_DRAIN_TIMEOUT sisa koneksi ditutup paksa
+	log.Printf("🛑 Shutdown signal received, draining in-flight requests (timeout: %s)...", cfg.ShutdownDrainTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownDrainTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⏰ Drain timeout exceeded, closing remaining connections: %v", err)
+		srv.Close()
+	} else {
+		log.Println("✅ All in-flight requests finished")
+	}
+
+	// Koneksi gRPC ditutup terakhir: request yang sedang di-drain masih memakainya
+	gateway.conn.Close()
+	log.Println("👋 API Gateway stopped")
 }
 
 /*
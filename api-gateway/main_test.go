@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+func TestCreateUserReturnsCreatedWithLocation(t *testing.T) {
+	upstream := startUserService(t, &writeBackend{})
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com","age":30}`, http.Header{"Content-Type": {"application/json"}})
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201 (body: %s)", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Location"); got != "/users/new" {
+		t.Fatalf("Location = %q, want /users/new", got)
+	}
+
+	// Body JSON tetap dikirim
+	var body struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.User.ID != "new" {
+		t.Fatalf("body = %s, want created user (err: %v)", rec.Body, err)
+	}
+}
+
+func TestUserLocationEscapesID(t *testing.T) {
+	if got := userLocation("a/b c"); got != "/users/a%2Fb%20c" {
+		t.Fatalf("userLocation = %q", got)
+	}
+}
+
+// fieldMaskBackend mencatat field_mask dari GetUser terakhir
+type fieldMaskBackend struct {
+	pb.UnimplementedUserServiceServer
+	mu   sync.Mutex
+	mask []string
+}
+
+func (b *fieldMaskBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.mask = req.FieldMask
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func TestGetUserFieldsQueryBecomesFieldMask(t *testing.T) {
+	backend := &fieldMaskBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"/users/u1", nil},
+		{"/users/u1?fields=name,email", []string{"name", "email"}},
+		{"/users/u1?fields=name,%20,email,", []string{"name", "email"}}, // Spasi & item kosong dibuang
+	}
+	for _, tt := range tests {
+		if rec := doRequest(router, http.MethodGet, tt.target, "", nil); rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d (body: %s)", tt.target, rec.Code, rec.Body)
+		}
+		backend.mu.Lock()
+		got := backend.mask
+		backend.mu.Unlock()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: field_mask = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Exporter yang didukung (METRICS_EXPORTERS)
+const (
+	ExporterPrometheus = "prometheus" // Di-scrape lewat GET /metrics
+	ExporterOTLP       = "otlp"       // Di-push ke OpenTelemetry collector
+)
+
+// Metrics menyimpan instrument yang dipakai gateway
+// Instrument dibuat SEKALI dari 1 MeterProvider, lalu setiap exporter (reader)
+// membaca dari instrument yang sama → Prometheus dan OTLP selalu melaporkan angka yang sama
+type Metrics struct {
+	provider *sdkmetric.MeterProvider
+	registry *prometheus.Registry
+	meter    metric.Meter
+
+	requests metric.Int64Counter     // Total HTTP request
+	errors   metric.Int64Counter     // Total HTTP request dengan status 5xx
+	duration metric.Float64Histogram // Latency HTTP request (detik)
+}
+
+// Setup membuat MeterProvider dengan reader untuk setiap exporter yang dipilih
+// otlpEndpoint wajib diisi kalau exporter "otlp" dipakai
+func Setup(ctx context.Context, serviceName string, exporters []string, otlpEndpoint string) (*Metrics, error) {
+	m := &Metrics{registry: prometheus.NewRegistry()}
+
+	opts := []sdkmetric.Option{
+		sdkmetric.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+		)),
+	}
+
+	for _, name := range exporters {
+		switch name {
+		case ExporterPrometheus:
+			reader, err := otelprom.New(otelprom.WithRegisterer(m.registry))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+			}
+			opts = append(opts, sdkmetric.WithReader(reader))
+		case ExporterOTLP:
+			if otlpEndpoint == "" {
+				return nil, fmt.Errorf("otlp metrics exporter requires OTEL_EXPORTER_OTLP_ENDPOINT")
+			}
+			exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpointURL(otlpEndpoint))
+			if err != nil {
+				return nil, fmt.Errorf("failed to create otlp metrics exporter: %w", err)
+			}
+			opts = append(opts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)))
+		default:
+			return nil, fmt.Errorf("unknown metrics exporter %q", name)
+		}
+	}
+
+	m.provider = sdkmetric.NewMeterProvider(opts...)
+	m.meter = m.provider.Meter(serviceName)
+
+	var err error
+	if m.requests, err = m.meter.Int64Counter("http.server.requests",
+		metric.WithDescription("Total HTTP requests handled by the gateway")); err != nil {
+		return nil, err
+	}
+	if m.errors, err = m.meter.Int64Counter("http.server.errors",
+		metric.WithDescription("Total HTTP requests that ended with a 5xx status")); err != nil {
+		return nil, err
+	}
+	if m.duration, err = m.meter.Float64Histogram("http.server.duration",
+		metric.WithDescription("HTTP request latency"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10)); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Meter dipakai komponen lain yang ingin menambah instrument sendiri
+// (contoh: counter hit/miss cache) di provider yang sama
+func (m *Metrics) Meter() metric.Meter {
+	return m.meter
+}
+
+// Handler adalah endpoint GET /metrics untuk Prometheus scraping
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Shutdown flush metric yang tersisa ke exporter (penting untuk OTLP)
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	return m.provider.Shutdown(ctx)
+}
+
+// RecordRequest mencatat 1 HTTP request yang sudah selesai
+// route sebaiknya pattern (contoh "GET /users/{id}"), bukan path mentah, supaya cardinality label kecil
+func (m *Metrics) RecordRequest(ctx context.Context, method, route string, status int, elapsed time.Duration) {
+	attrs := metric.WithAttributes(
+		attribute.String("method", method),
+		attribute.String("route", route),
+		attribute.Int("status", status),
+	)
+	m.requests.Add(ctx, 1, attrs)
+	m.duration.Record(ctx, elapsed.Seconds(), attrs)
+	if status >= 500 {
+		m.errors.Add(ctx, 1, attrs)
+	}
+}
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeCollector adalah OTLP metrics collector palsu yang mencatat nama metric yang diterima
+type fakeCollector struct {
+	collectorpb.UnimplementedMetricsServiceServer
+
+	mu     sync.Mutex
+	values map[string]int64 // Nama metric → nilai sum terakhir (0 untuk histogram)
+}
+
+func (c *fakeCollector) Export(ctx context.Context, req *collectorpb.ExportMetricsServiceRequest) (*collectorpb.ExportMetricsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				var value int64
+				if sum := m.GetSum(); sum != nil {
+					for _, dp := range sum.DataPoints {
+						value += dp.GetAsInt()
+					}
+				}
+				c.values[m.Name] = value
+			}
+		}
+	}
+	return &collectorpb.ExportMetricsServiceResponse{}, nil
+}
+
+// startCollector menjalankan collector palsu, return endpoint URL untuk OTEL_EXPORTER_OTLP_ENDPOINT
+func startCollector(t *testing.T) (*fakeCollector, string) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	collector := &fakeCollector{values: make(map[string]int64)}
+	server := grpc.NewServer()
+	collectorpb.RegisterMetricsServiceServer(server, collector)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	return collector, "http://" + lis.Addr().String()
+}
+
+func TestOTLPAndPrometheusReportSameInstruments(t *testing.T) {
+	collector, endpoint := startCollector(t)
+	ctx := context.Background()
+
+	m, err := Setup(ctx, "api-gateway-test", []string{ExporterPrometheus, ExporterOTLP}, endpoint)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	m.RecordRequest(ctx, "GET", "GET /users/{id}", 200, 10*time.Millisecond)
+	m.RecordRequest(ctx, "GET", "GET /users/{id}", 200, 20*time.Millisecond)
+	m.RecordRequest(ctx, "POST", "POST /users", 503, 30*time.Millisecond)
+
+	// Prometheus dibaca sebelum Shutdown (provider sudah tutup setelahnya)
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	scrape, _ := io.ReadAll(rec.Body)
+
+	// Shutdown flush periodic reader → collector menerima export terakhir
+	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := m.Shutdown(shutdownCtx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	want := map[string]int64{"http.server.requests": 3, "http.server.errors": 1, "http.server.duration": 0}
+	for name, value := range want {
+		got, ok := collector.values[name]
+		if !ok {
+			t.Fatalf("OTLP collector did not receive %s (got %v)", name, collector.values)
+		}
+		if got != value {
+			t.Fatalf("OTLP %s = %d, want %d", name, got, value)
+		}
+	}
+
+	// Label otel_scope_* bergantung versi exporter, jadi hanya label milik gateway yang dicek
+	for _, pattern := range []string{
+		`http_server_requests_total\{[^}]*route="GET /users/\{id\}",status="200"\} 2\n`,
+		`http_server_requests_total\{[^}]*route="POST /users",status="503"\} 1\n`,
+		`http_server_errors_total\{[^}]*route="POST /users",status="503"\} 1\n`,
+		`http_server_duration_seconds_count\{[^}]*route="GET /users/\{id\}",status="200"\} 2\n`,
+	} {
+		if !regexp.MustCompile(pattern).Match(scrape) {
+			t.Fatalf("prometheus scrape does not match %s:\n%s", pattern, scrape)
+		}
+	}
+}
+
+func TestSetupRejectsInvalidExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), "test", []string{"statsd"}, ""); err == nil {
+		t.Fatal("unknown exporter: want error")
+	}
+	if _, err := Setup(context.Background(), "test", []string{ExporterOTLP}, ""); err == nil {
+		t.Fatal("otlp without endpoint: want error")
+	}
+}
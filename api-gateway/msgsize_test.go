@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"strings"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// streamOversizedUser men-stream 1 user yang sedikit lebih besar dari batas default gRPC (4MB)
+func streamOversizedUser(t *testing.T, maxMsgSizeMB string) *http.Response {
+	t.Helper()
+	backend := &listBackend{users: []*pb.User{{Id: "big", Name: strings.Repeat("x", 4<<20+1024)}}}
+	upstream := startUserService(t, backend, grpc.MaxSendMsgSize(8<<20))
+	cfg := testConfig(t, map[string]string{"GRPC_MAX_MSG_SIZE_MB": maxMsgSizeMB})
+	srv := serveGateway(t, newTestGateway(t, cfg, upstream.addr))
+	return getStream(t, srv.URL+"/users/stream", protobufStreamContentType)
+}
+
+func TestMaxMsgSizeRaisedAcceptsLargeMessage(t *testing.T) {
+	resp := streamOversizedUser(t, "8")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	// Reader frame di client juga perlu batas lebih dari 4MB (default protodelim)
+	var user pb.User
+	if err := (protodelim.UnmarshalOptions{MaxSize: 8 << 20}).UnmarshalFrom(bufio.NewReader(resp.Body), &user); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	if user.Id != "big" || len(user.Name) != 4<<20+1024 {
+		t.Fatalf("received user %q with %d byte name, want the large user intact", user.Id, len(user.Name))
+	}
+}
+
+func TestMaxMsgSizeDefaultRejectsLargeMessage(t *testing.T) {
+	resp := streamOversizedUser(t, "")
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429 (ResourceExhausted: message larger than max)", resp.StatusCode)
+	}
+}
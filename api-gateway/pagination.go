@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// defaultPageSize dipakai kalau client tidak mengirim ?limit=
+	defaultPageSize = 10
+	// maxPageSize membatasi ukuran 1 halaman supaya response tidak kebesaran
+	maxPageSize = 100
+)
+
+// PageMeta adalah kontrak pagination yang SAMA untuk semua collection endpoint
+// (list, search, recent, dll). Front-end cukup baca object "meta" ini
+// tanpa perlu tahu detail tiap endpoint.
+type PageMeta struct {
+	Count         int    `json:"count"`                   // Jumlah item di halaman ini
+	PageSize      int    `json:"pageSize"`                // Ukuran halaman yang diminta
+	NextPageToken string `json:"nextPageToken,omitempty"` // Token halaman berikutnya (kalau ada)
+	HasMore       bool   `json:"hasMore"`                 // Masih ada data setelah halaman ini?
+	TotalSize     *int64 `json:"totalSize,omitempty"`     // Total item semua halaman (hanya endpoint yang bisa menghitungnya)
+}
+
+// buildPageMeta adalah shared helper untuk membangun PageMeta
+// Kalau ada nextPageToken, otomatis hasMore = true
+func buildPageMeta(count, pageSize int, nextPageToken string, hasMore bool) PageMeta {
+	return PageMeta{
+		Count:         count,
+		PageSize:      pageSize,
+		NextPageToken: nextPageToken,
+		HasMore:       hasMore || nextPageToken != "",
+	}
+}
+
+// parsePageSize membaca ?limit= dari query string
+// Nilai kosong → defaultPageSize, nilai invalid → error (jadi 400 di handler)
+func parsePageSize(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultPageSize, nil
+	}
+
+	size, err := strconv.Atoi(raw)
+	if err != nil || size <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	return size, nil
+}
+
+// writeCollection menulis response collection dengan format standar:
+// {"<key>": [...], "meta": {...}}
+func writeCollection(w http.ResponseWriter, key string, items interface{}, meta PageMeta) {
+	writeCollectionExtra(w, key, items, meta, nil)
+}
+
+// writeCollectionExtra = writeCollection + field tambahan di level atas (contoh: missingIds)
+func writeCollectionExtra(w http.ResponseWriter, key string, items interface{}, meta PageMeta, extra map[string]interface{}) {
+	body := map[string]interface{}{
+		key:    items,
+		"meta": meta,
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
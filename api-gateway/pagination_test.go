@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// pageBackend adalah User Service palsu untuk collection endpoint:
+// ListUsersPage, ListUsersByDateRange & GetUsersByIds melayani n user "u1".."un"
+// (u1 dibuat 2024-06-01, u2 sehari kemudian, dst)
+type pageBackend struct {
+	pb.UnimplementedUserServiceServer
+	users []*pb.User
+}
+
+func newPageBackend(n int) *pageBackend {
+	b := &pageBackend{}
+	for i := 1; i <= n; i++ {
+		b.users = append(b.users, &pb.User{
+			Id:        fmt.Sprintf("u%d", i),
+			Name:      fmt.Sprintf("User %d", i),
+			CreatedAt: timestamppb.New(time.Date(2024, 6, i, 0, 0, 0, 0, time.UTC)),
+		})
+	}
+	return b
+}
+
+func (b *pageBackend) ListUsersPage(ctx context.Context, req *pb.ListUsersPageRequest) (*pb.ListUsersPageResponse, error) {
+	resp := &pb.ListUsersPageResponse{TotalSize: int64(len(b.users))}
+	size := int(req.PageSize)
+	if size >= len(b.users) {
+		resp.Users = b.users
+		return resp, nil
+	}
+	resp.Users = b.users[:size]
+	resp.NextPageToken = "next-" + b.users[size-1].Id
+	return resp, nil
+}
+
+func (b *pageBackend) ListUsersByDateRange(req *pb.DateRangeRequest, stream pb.UserService_ListUsersByDateRangeServer) error {
+	sent := 0
+	for _, user := range b.users {
+		if req.Limit > 0 && sent >= int(req.Limit) {
+			break
+		}
+		if user.CreatedAt.AsTime().Before(req.From.AsTime()) {
+			continue
+		}
+		if err := stream.Send(&pb.UserResponse{User: user}); err != nil {
+			return err
+		}
+		sent++
+	}
+	return nil
+}
+
+func (b *pageBackend) GetUsersByIds(ctx context.Context, req *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error) {
+	resp := &pb.GetUsersByIdsResponse{}
+	for _, id := range req.Ids {
+		found := false
+		for _, user := range b.users {
+			if user.Id == id {
+				resp.Users = append(resp.Users, user)
+				found = true
+			}
+		}
+		if !found {
+			resp.MissingIds = append(resp.MissingIds, id)
+		}
+	}
+	return resp, nil
+}
+
+func TestBuildPageMeta(t *testing.T) {
+	tests := []struct {
+		name      string
+		count     int
+		pageSize  int
+		nextToken string
+		hasMore   bool
+		want      PageMeta
+	}{
+		{"last page", 3, 10, "", false, PageMeta{Count: 3, PageSize: 10}},
+		{"next token implies hasMore", 10, 10, "tok", false, PageMeta{Count: 10, PageSize: 10, NextPageToken: "tok", HasMore: true}},
+		{"hasMore without token", 10, 10, "", true, PageMeta{Count: 10, PageSize: 10, HasMore: true}},
+		{"empty page", 0, 10, "", false, PageMeta{Count: 0, PageSize: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildPageMeta(tt.count, tt.pageSize, tt.nextToken, tt.hasMore); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("buildPageMeta = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCollectionEndpointsPageMeta memastikan semua collection endpoint memakai kontrak meta yang sama
+func TestCollectionEndpointsPageMeta(t *testing.T) {
+	upstream := startUserService(t, newPageBackend(3))
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	const dateRange = "/users/by-date?from=2024-01-01T00:00:00Z&to=2030-01-01T00:00:00Z"
+	tests := []struct {
+		name     string
+		target   string
+		wantMeta map[string]interface{}
+	}{
+		{"list first page", "/users?limit=2", map[string]interface{}{
+			"count": 2.0, "pageSize": 2.0, "nextPageToken": "next-u2", "hasMore": true, "totalSize": 3.0,
+		}},
+		{"list last page", "/users?limit=5", map[string]interface{}{
+			"count": 3.0, "pageSize": 5.0, "hasMore": false, "totalSize": 3.0,
+		}},
+		{"by-date with more", dateRange + "&limit=2", map[string]interface{}{
+			"count": 2.0, "pageSize": 2.0, "nextPageToken": encodeDateRangeToken(newPageBackend(2).users[1]), "hasMore": true,
+		}},
+		{"by-date last page", dateRange + "&limit=3", map[string]interface{}{
+			"count": 3.0, "pageSize": 3.0, "hasMore": false,
+		}},
+		{"by-date default page size", dateRange, map[string]interface{}{
+			"count": 3.0, "pageSize": float64(defaultPageSize), "hasMore": false,
+		}},
+		{"by-ids", "/users/by-ids?ids=u1,u3,nope", map[string]interface{}{
+			"count": 2.0, "pageSize": 3.0, "hasMore": false,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := doRequest(router, http.MethodGet, tt.target, "", nil)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body)
+			}
+
+			var body struct {
+				Users []map[string]interface{} `json:"users"`
+				Meta  map[string]interface{}   `json:"meta"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if !reflect.DeepEqual(body.Meta, tt.wantMeta) {
+				t.Fatalf("meta = %v, want %v", body.Meta, tt.wantMeta)
+			}
+			if got := len(body.Users); float64(got) != tt.wantMeta["count"] {
+				t.Fatalf("len(users) = %d, want meta.count %v", got, tt.wantMeta["count"])
+			}
+		})
+	}
+}
+
+func TestParsePageSize(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    int
+		wantErr bool
+	}{
+		{"", defaultPageSize, false},
+		{"limit=25", 25, false},
+		{"limit=1000", maxPageSize, false},
+		{"limit=0", 0, true},
+		{"limit=-1", 0, true},
+		{"limit=abc", 0, true},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/users?"+tt.query, nil)
+		got, err := parsePageSize(r)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePageSize(%q) = %d, %v; want %d, err %v", tt.query, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
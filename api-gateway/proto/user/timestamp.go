@@ -0,0 +1,17 @@
+package user
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FormatTimestamp menulis Timestamp sebagai RFC3339 (UTC), nil/tidak valid = string kosong
+// Dipakai output non-JSON seperti CSV export (JSON sudah ditangani protojson)
+// File ini tidak di-generate, jadi tidak tertimpa saat regenerate proto
+func FormatTimestamp(ts *timestamppb.Timestamp) string {
+	if !ts.IsValid() {
+		return ""
+	}
+	return ts.AsTime().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// protoJSON adalah opsi encoding semua protobuf message di response gateway
+// Default mengikuti proto JSON mapping: nama field camelCase, enum sebagai nama
+// ("USER_STATUS_ACTIVE"), Timestamp sebagai string RFC3339, field kosong tidak ditulis
+// Di-set sekali di main dari config (JSON_EMIT_DEFAULTS, JSON_USE_PROTO_NAMES)
+var protoJSON = protojson.MarshalOptions{}
+
+// protoJSONIn adalah opsi decoding request body ke protobuf message
+// Field yang tidak dikenal diabaikan (sama seperti encoding/json sebelumnya);
+// client yang butuh validasi ketat bisa memakai SCHEMA_VALIDATION
+var protoJSONIn = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// protoValue membungkus proto.Message supaya encoding/json menulisnya lewat protoJSON
+// Dipakai untuk payload campuran, contoh: {"users": [...], "meta": {...}}
+type protoValue struct {
+	msg proto.Message
+}
+
+func (v protoValue) MarshalJSON() ([]byte, error) {
+	return protoJSON.Marshal(v.msg)
+}
+
+// protoValues membungkus slice message (contoh: []*pb.User) untuk payload campuran
+func protoValues[T proto.Message](msgs []T) []protoValue {
+	out := make([]protoValue, len(msgs))
+	for i, m := range msgs {
+		out[i] = protoValue{msg: m}
+	}
+	return out
+}
+
+// writeProtoJSON menulis 1 protobuf message sebagai response JSON
+// Lewat encoding/json supaya output tetap compact + newline (protojson sengaja
+// mengacak whitespace, jadi output-nya tidak stabil kalau ditulis langsung)
+func writeProtoJSON(w http.ResponseWriter, httpStatus int, msg proto.Message) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(protoValue{msg: msg})
+}
+
+// decodeProtoJSON decode body JSON ke protobuf request message
+// Field enum "status" boleh ditulis longgar seperti sebelumnya ("active", "Pending", dll):
+// dinormalisasi dulu ke nama enum proto, nilai yang tidak dikenal → *enumError
+func decodeProtoJSON(data []byte, msg proto.Message) error {
+	statusField := msg.ProtoReflect().Descriptor().Fields().ByName("status")
+	if statusField != nil && statusField.Kind() == protoreflect.EnumKind {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		var raw string
+		if json.Unmarshal(fields["status"], &raw) == nil {
+			userStatus, err := parseUserStatus(raw)
+			if err != nil {
+				return err
+			}
+			fields["status"], _ = json.Marshal(userStatus.String())
+			if data, err = json.Marshal(fields); err != nil {
+				return err
+			}
+		}
+	}
+	return protoJSONIn.Unmarshal(data, msg)
+}
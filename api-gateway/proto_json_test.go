@@ -0,0 +1,120 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreatedAtProtoJSONRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123000000, time.UTC)
+	user := &pb.User{Id: "u1", Name: "Alice", CreatedAt: timestamppb.New(created)}
+
+	data, err := protojson.Marshal(user)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	// Timestamp ditulis sebagai string ISO 8601 / RFC3339 (UTC), bukan object {seconds, nanos}
+	if compact := strings.ReplaceAll(string(data), " ", ""); !strings.Contains(compact, `"createdAt":"2024-03-01T10:30:00.123Z"`) {
+		t.Fatalf("JSON = %s, want createdAt as RFC3339 string", data)
+	}
+
+	var decoded pb.User
+	if err := protojson.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !proto.Equal(&decoded, user) {
+		t.Fatalf("round trip = %v, want %v", &decoded, user)
+	}
+	if got := decoded.CreatedAt.AsTime(); !got.Equal(created) {
+		t.Fatalf("created_at = %s, want %s", got, created)
+	}
+}
+
+func TestCreatedAtProtoJSONAcceptsOffsets(t *testing.T) {
+	var user pb.User
+	if err := protojson.Unmarshal([]byte(`{"createdAt":"2024-03-01T17:30:00+07:00"}`), &user); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if got, want := user.CreatedAt.AsTime(), time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("created_at = %s, want %s", got, want)
+	}
+	if got := pb.FormatTimestamp(user.CreatedAt); got != "2024-03-01T10:30:00Z" {
+		t.Fatalf("FormatTimestamp = %q", got)
+	}
+	if got := pb.FormatTimestamp(nil); got != "" {
+		t.Fatalf("FormatTimestamp(nil) = %q, want empty", got)
+	}
+}
+
+// Golden document proto JSON mapping untuk GetUserResponse (opsi default gateway)
+// camelCase, enum sebagai nama, int64 sebagai string, Timestamp RFC3339, field kosong tidak ditulis
+const goldenUserJSON = `{"user":{"id":"u1","name":"Alice","email":"alice@example.com","age":30,"createdAt":"2024-03-01T10:30:00Z","status":"USER_STATUS_ACTIVE","updatedAt":"2024-03-02T08:00:00.500Z","version":"2","roles":["admin"]}}` + "\n"
+
+// Golden document dengan JSON_EMIT_DEFAULTS=true dan JSON_USE_PROTO_NAMES=true
+const goldenUserJSONDefaultsProtoNames = `{"user":{"id":"u1","name":"","email":"","age":0,"created_at":null,"status":"USER_STATUS_UNSPECIFIED","canonical_email":"","updated_at":null,"deleted_at":null,"version":"0","roles":[]}}` + "\n"
+
+func TestWriteProtoJSONGolden(t *testing.T) {
+	resp := &pb.GetUserResponse{User: &pb.User{
+		Id:        "u1",
+		Name:      "Alice",
+		Email:     "alice@example.com",
+		Age:       30,
+		CreatedAt: timestamppb.New(time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC)),
+		UpdatedAt: timestamppb.New(time.Date(2024, 3, 2, 8, 0, 0, 500000000, time.UTC)),
+		Status:    pb.UserStatus_USER_STATUS_ACTIVE,
+		Version:   2,
+		Roles:     []string{"admin"},
+	}}
+
+	rec := httptest.NewRecorder()
+	writeProtoJSON(rec, http.StatusOK, resp)
+	if got := rec.Body.String(); got != goldenUserJSON {
+		t.Fatalf("JSON =\n%s\nwant\n%s", got, goldenUserJSON)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type = %q", got)
+	}
+}
+
+func TestWriteProtoJSONGoldenDefaultsAndProtoNames(t *testing.T) {
+	previous := protoJSON
+	protoJSON = protojson.MarshalOptions{EmitUnpopulated: true, UseProtoNames: true}
+	t.Cleanup(func() { protoJSON = previous })
+
+	rec := httptest.NewRecorder()
+	writeProtoJSON(rec, http.StatusOK, &pb.GetUserResponse{User: &pb.User{Id: "u1"}})
+	if got := rec.Body.String(); got != goldenUserJSONDefaultsProtoNames {
+		t.Fatalf("JSON =\n%s\nwant\n%s", got, goldenUserJSONDefaultsProtoNames)
+	}
+}
+
+func TestDecodeProtoJSONFieldNames(t *testing.T) {
+	// Nama camelCase dan nama field proto sama-sama diterima; status boleh ditulis longgar
+	for _, body := range []string{
+		`{"name":"Alice","email":"alice@example.com","age":30,"status":"pending","unknown":1}`,
+		`{"name":"Alice","email":"alice@example.com","age":30,"status":"USER_STATUS_PENDING"}`,
+	} {
+		var req pb.CreateUserRequest
+		if err := decodeProtoJSON([]byte(body), &req); err != nil {
+			t.Fatalf("decode %s: %v", body, err)
+		}
+		want := &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com", Age: 30, Status: pb.UserStatus_USER_STATUS_PENDING}
+		if !proto.Equal(&req, want) {
+			t.Fatalf("decode %s = %v, want %v", body, &req, want)
+		}
+	}
+
+	var update pb.UpdateUserRequest
+	if err := decodeProtoJSON([]byte(`{"expected_version":"3"}`), &update); err != nil || update.ExpectedVersion != 3 {
+		t.Fatalf("proto name expected_version: %v, %v", update.ExpectedVersion, err)
+	}
+}
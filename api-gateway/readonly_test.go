@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readOnlyBackend: writeBackend + GetUser, dipasang di belakang interceptor yang meniru read-only mode User Service
+type readOnlyBackend struct {
+	writeBackend
+}
+
+func (b *readOnlyBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id, Name: "Alice"}}, nil
+}
+
+func TestReadOnlyWritesSurfaceAs503(t *testing.T) {
+	backend := &readOnlyBackend{}
+	rejectWrites := grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if info.FullMethod == pb.UserService_CreateUser_FullMethodName {
+			return nil, status.Error(codes.FailedPrecondition, readOnlyMessage)
+		}
+		return handler(ctx, req)
+	})
+	upstream := startUserService(t, backend, rejectWrites)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com"}`, http.Header{"Content-Type": {"application/json"}})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("write in read-only mode: status = %d, want 503 (body: %s)", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatal("read-only 503 without Retry-After")
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("read in read-only mode: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestOtherFailedPreconditionNotReadOnly(t *testing.T) {
+	if isReadOnlyError(status.Error(codes.FailedPrecondition, "email is immutable")) {
+		t.Fatal("unrelated FailedPrecondition treated as read-only")
+	}
+	if !isReadOnlyError(status.Error(codes.FailedPrecondition, readOnlyMessage)) {
+		t.Fatal("read-only error not recognised")
+	}
+}
@@ -0,0 +1,104 @@
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// emailPattern mendeteksi email di teks log bebas
+// Sengaja longgar: lebih baik ada false positive yang ter-hash daripada email bocor
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+
+// Redactor mengganti nilai PII dengan salted hash yang stabil
+// Nilai yang sama selalu menghasilkan hash yang sama (log tetap bisa dikorelasikan),
+// tapi nilai aslinya tidak bisa dibaca tanpa salt
+type Redactor struct {
+	salt   []byte
+	fields map[string]bool // Nama field PII (contoh: "email", "name")
+}
+
+// current adalah redactor global (nil = redaction off), di-set sekali saat startup
+var current atomic.Pointer[Redactor]
+
+// Enable mengaktifkan redaction global untuk field yang diberikan
+func Enable(salt string, fields []string) {
+	r := &Redactor{salt: []byte(salt), fields: make(map[string]bool, len(fields))}
+	for _, f := range fields {
+		r.fields[strings.ToLower(f)] = true
+	}
+	current.Store(r)
+}
+
+// Hash return salted hash (HMAC-SHA256, 16 hex char pertama) dengan prefix "pii:"
+func (r *Redactor) Hash(value string) string {
+	mac := hmac.New(sha256.New, r.salt)
+	mac.Write([]byte(value))
+	return "pii:" + hex.EncodeToString(mac.Sum(nil))[:16]
+}
+
+// Field me-redact value kalau name termasuk field PII yang dikonfigurasi
+// Dipakai di log.Printf untuk nilai yang diketahui jenisnya, contoh: redact.Field("name", req.Name)
+func Field(name, value string) string {
+	r := current.Load()
+	name = strings.ToLower(name)
+	if r == nil || value == "" || !r.fields[name] {
+		return value
+	}
+	if name == "email" {
+		value = strings.ToLower(value) // Sama dengan Text(), supaya hash email konsisten
+	}
+	return r.Hash(value)
+}
+
+// Text mengganti semua email di teks bebas dengan hash-nya (kalau "email" termasuk field PII)
+// Email di-lowercase dulu supaya A@x.com dan a@x.com menghasilkan hash yang sama
+func Text(s string) string {
+	r := current.Load()
+	if r == nil || !r.fields["email"] {
+		return s
+	}
+	return emailPattern.ReplaceAllStringFunc(s, func(email string) string {
+		return r.Hash(strings.ToLower(email))
+	})
+}
+
+// Query return salinan query string dengan parameter PII di-hash
+func Query(q url.Values) url.Values {
+	r := current.Load()
+	if r == nil {
+		return q
+	}
+	out := make(url.Values, len(q))
+	for key, values := range q {
+		redacted := make([]string, len(values))
+		for i, v := range values {
+			redacted[i] = Text(Field(key, v))
+		}
+		out[key] = redacted
+	}
+	return out
+}
+
+// Writer membungkus output log supaya setiap baris melewati Text() sebelum ditulis
+// Package log menulis 1 baris per Write(), jadi email tidak pernah terpotong di tengah
+func Writer(w io.Writer) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		if current.Load() == nil {
+			return w.Write(p)
+		}
+		if _, err := io.WriteString(w, Text(string(p))); err != nil {
+			return 0, err
+		}
+		return len(p), nil // Panjang input, bukan output (hash bisa lebih panjang/pendek)
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,79 @@
+package redact
+
+import (
+	"bytes"
+	"log"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// enable mengaktifkan redaction untuk 1 test, lalu mematikannya lagi (state global)
+func enable(t *testing.T, salt string, fields ...string) {
+	t.Helper()
+	Enable(salt, fields)
+	t.Cleanup(func() { current.Store(nil) })
+}
+
+func TestDisabledByDefault(t *testing.T) {
+	if got := Field("email", "alice@example.com"); got != "alice@example.com" {
+		t.Fatalf("Field = %q, want value unchanged when redaction is off", got)
+	}
+	if got := Text("login alice@example.com"); got != "login alice@example.com" {
+		t.Fatalf("Text = %q, want text unchanged when redaction is off", got)
+	}
+}
+
+func TestFieldHashIsStableAndSalted(t *testing.T) {
+	enable(t, "salt-1", "email", "name")
+
+	first := Field("email", "Alice@Example.com")
+	if !strings.HasPrefix(first, "pii:") || strings.Contains(first, "alice") {
+		t.Fatalf("Field = %q, want pii hash", first)
+	}
+	if again := Field("EMAIL", "alice@example.com"); again != first {
+		t.Fatalf("same email hashed to %q and %q", first, again)
+	}
+	if Text("user Alice@Example.com logged in") != "user "+first+" logged in" {
+		t.Fatal("Text and Field hash the same email differently")
+	}
+	if got := Field("age", "30"); got != "30" {
+		t.Fatalf("non-PII field = %q, want unchanged", got)
+	}
+
+	Enable("salt-2", []string{"email"})
+	if other := Field("email", "alice@example.com"); other == first {
+		t.Fatal("different salt produced the same hash")
+	}
+}
+
+func TestRawEmailNeverReachesLogOutput(t *testing.T) {
+	enable(t, "salt", "email", "name")
+
+	var out bytes.Buffer
+	logger := log.New(Writer(&out), "", 0)
+	logger.Printf("created user Alice (alice.smith+tag@example.co.uk), notify bob@example.com")
+	logger.Printf("name=%s", Field("name", "Alice Smith"))
+
+	got := out.String()
+	for _, raw := range []string{"alice.smith+tag@example.co.uk", "bob@example.com", "Alice Smith"} {
+		if strings.Contains(got, raw) {
+			t.Fatalf("log output contains %q: %s", raw, got)
+		}
+	}
+	if strings.Count(got, "\n") != 2 {
+		t.Fatalf("log output = %q, want 2 lines", got)
+	}
+}
+
+func TestQueryRedactsPIIParams(t *testing.T) {
+	enable(t, "salt", "email", "name")
+
+	q := Query(url.Values{"email": {"alice@example.com"}, "name": {"Alice"}, "limit": {"10"}})
+	if q.Get("email") == "alice@example.com" || q.Get("name") == "Alice" {
+		t.Fatalf("PII params not redacted: %v", q)
+	}
+	if q.Get("limit") != "10" {
+		t.Fatalf("limit = %q, want unchanged", q.Get("limit"))
+	}
+}
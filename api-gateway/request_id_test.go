@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDBackend mengirim balik metadata "x-request-id" yang diterima lewat channel
+type requestIDBackend struct {
+	pb.UnimplementedUserServiceServer
+	ids chan string
+}
+
+func (b *requestIDBackend) received(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	b.ids <- md.Get("x-request-id")[0]
+}
+
+func (b *requestIDBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	b.received(ctx)
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func (b *requestIDBackend) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+	b.received(stream.Context())
+	return nil
+}
+
+func TestRequestIDFlowsToBackendMetadata(t *testing.T) {
+	backend := &requestIDBackend{ids: make(chan string, 1)}
+	upstream := startUserService(t, backend)
+	router := requestLogger(testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr)))
+
+	for _, target := range []string{"/users/u1", "/users/stream"} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-Request-ID", "trace-abc-123")
+		router.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("X-Request-ID"); got != "trace-abc-123" {
+			t.Fatalf("%s: response X-Request-ID = %q, want inbound id echoed", target, got)
+		}
+		if got := <-backend.ids; got != "trace-abc-123" {
+			t.Fatalf("%s: backend x-request-id = %q, want trace-abc-123", target, got)
+		}
+	}
+}
+
+func TestRequestIDGeneratedWhenMissingOrInvalid(t *testing.T) {
+	backend := &requestIDBackend{ids: make(chan string, 1)}
+	upstream := startUserService(t, backend)
+	router := requestLogger(testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr)))
+	generated := regexp.MustCompile(`^[0-9a-f]{16}$`)
+
+	for _, inbound := range []string{"", "bad id\nwith newline", strings.Repeat("a", maxRequestIDLen+1)} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/u1", nil)
+		if inbound != "" {
+			req.Header.Set("X-Request-ID", inbound)
+		}
+		router.ServeHTTP(rec, req)
+
+		id := rec.Header().Get("X-Request-ID")
+		if !generated.MatchString(id) {
+			t.Fatalf("inbound %q: response X-Request-ID = %q, want generated 16 hex chars", inbound, id)
+		}
+		if got := <-backend.ids; got != id {
+			t.Fatalf("inbound %q: backend x-request-id = %q, want %q", inbound, got, id)
+		}
+	}
+}
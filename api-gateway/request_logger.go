@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"api-gateway/logging"
+	"api-gateway/redact"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// maxRequestIDLen membatasi X-Request-ID dari client (id dari LB/proxy biasanya UUID, 36 char)
+const maxRequestIDLen = 128
+
+// newRequestID membuat id acak 16 hex char untuk korelasi log 1 request
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validRequestID cek X-Request-ID dari client sebelum dipakai
+// Hanya huruf, angka, dan "-_.:" supaya tidak bisa dipakai menyisipkan baris/field palsu ke log
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+type requestIDKey struct{}
+
+// requestIDFromContext mengambil request id yang dipasang requestLogger ("" kalau tidak ada)
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestLogger adalah middleware yang membuat logger per request (request_id, method, path)
+// dan menyimpannya di context; handler mengambilnya lewat logging.FromContext(r.Context())
+// Request id juga dikirim balik di header X-Request-ID supaya client bisa melaporkannya
+//
+// X-Request-ID dari client (atau LB/proxy di depan gateway) dipakai kalau valid,
+// jadi 1 id bisa diikuti dari log client → gateway → User Service (lihat requestIDUnaryInterceptor)
+func requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set("X-Request-ID", id)
+
+		logger := slog.Default().With(
+			"request_id", id,
+			"method", r.Method,
+			"path", redact.Text(r.URL.Path),
+		)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(logging.WithLogger(ctx, logger)))
+	})
+}
+
+// withRequestID menambahkan metadata "x-request-id" ke gRPC call (dibaca interceptor.Logging di User Service)
+func withRequestID(ctx context.Context) context.Context {
+	if id := requestIDFromContext(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, "x-request-id", id)
+	}
+	return ctx
+}
+
+// requestIDUnaryInterceptor & requestIDStreamInterceptor meneruskan request id ke SEMUA RPC,
+// jadi handler tidak perlu ingat memanggil withRequestID satu per satu (beda dengan withAuth)
+func requestIDUnaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withRequestID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func requestIDStreamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withRequestID(ctx), desc, cc, method, opts...)
+	}
+}
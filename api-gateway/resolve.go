@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/status"
+)
+
+// resolveResult adalah hasil lookup 1 id dari fan-out
+type resolveResult struct {
+	id   string
+	user *pb.User
+	err  error
+}
+
+// ResolveUsersHandler menghandle POST /users/resolve dengan body {"ids": ["a", "b", ...]}
+// Response berupa Server-Sent Events: setiap user dikirim SEGERA setelah GetUser-nya selesai
+// (urutan = urutan selesai, bukan urutan request), jadi client bisa render incremental
+//
+//	event: user    data: {"id": "...", "user": {...}}
+//	event: error   data: {"id": "...", "code": "NotFound", "error": "..."}
+//	event: done    data: {"resolved": 2, "failed": 1}
+func (gw *APIGateway) ResolveUsersHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE & VALIDASI BODY
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+
+	// Id duplikat cukup di-lookup sekali
+	ids := make([]string, 0, len(req.IDs))
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		http.Error(w, "ids must contain at least one id", http.StatusBadRequest)
+		return
+	}
+	if len(ids) > gw.cfg.ResolveMaxIDs {
+		http.Error(w, fmt.Sprintf("too many ids: %d (max %d)", len(ids), gw.cfg.ResolveMaxIDs), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received ResolveUsers request (%d ids)", len(ids))
+
+	// 3. BOUNDED FAN-OUT
+	// Context dari request: client disconnect → semua GetUser yang masih jalan ikut di-cancel
+	ctx, cancel := context.WithCancel(withAuth(r.Context(), r))
+	defer cancel()
+
+	results := make(chan resolveResult)
+	sem := make(chan struct{}, gw.cfg.ResolveConcurrency) // Maksimal N GetUser bersamaan
+	var wg sync.WaitGroup
+
+	go func() {
+		defer close(results)
+		defer wg.Wait()
+
+		for _, id := range ids {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				callCtx, callCancel := context.WithTimeout(ctx, gw.cfg.RPCTimeout)
+				defer callCancel()
+
+				resp, err := gw.getUser(callCtx, &pb.GetUserRequest{Id: id})
+				result := resolveResult{id: id, err: err}
+				if err == nil {
+					result.user = resp.User
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}(id)
+		}
+	}()
+
+	// 4. STREAM HASIL SEBAGAI SSE
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Flush per event: setiap event = 1 RPC selesai, jadi tidak ada gunanya di-batch
+	batch := newBatchFlusher(w, flushPolicy{records: 1}, nil)
+
+	resolved, failed := 0, 0
+	for result := range results {
+		var err error
+		if result.err != nil {
+			failed++
+			st := status.Convert(result.err)
+			err = batch.Do(func() error {
+				return writeSSE(w, "error", map[string]string{
+					"id":    result.id,
+					"code":  st.Code().String(),
+					"error": st.Message(),
+				})
+			})
+		} else {
+			resolved++
+			err = batch.Do(func() error {
+				return writeSSE(w, "user", map[string]interface{}{"id": result.id, "user": protoValue{msg: result.user}})
+			})
+		}
+		if err != nil {
+			log.Printf("❌ Client gone while streaming resolve results: %v", err)
+			cancel()
+			for range results {
+				// Kuras channel supaya goroutine fan-out bisa selesai
+			}
+			batch.Close()
+			return
+		}
+	}
+
+	batch.Do(func() error {
+		return writeSSE(w, "done", map[string]int{"resolved": resolved, "failed": failed})
+	})
+	batch.Close()
+
+	log.Printf("✅ Resolved %d users (%d failed)", resolved, failed)
+}
+
+// writeSSE menulis 1 Server-Sent Event dengan data JSON
+func writeSSE(w http.ResponseWriter, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return err
+}
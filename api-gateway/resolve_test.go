@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resolveBackend: GetUser lambat sebentar (supaya fan-out bersamaan terlihat),
+// id berawalan "missing" → NotFound; mencatat jumlah call dan concurrency maksimal
+type resolveBackend struct {
+	pb.UnimplementedUserServiceServer
+	calls    atomic.Int32
+	inFlight atomic.Int32
+
+	mu          sync.Mutex
+	maxInFlight int32
+}
+
+func (b *resolveBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	b.calls.Add(1)
+	n := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	b.mu.Lock()
+	if n > b.maxInFlight {
+		b.maxInFlight = n
+	}
+	b.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+	if strings.HasPrefix(req.Id, "missing") {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id, Name: "User " + req.Id}}, nil
+}
+
+type sseEvent struct {
+	name string
+	data map[string]interface{}
+}
+
+// readSSE membaca semua event dari body SSE sampai stream selesai
+func readSSE(t *testing.T, resp *http.Response) []sseEvent {
+	t.Helper()
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			current.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &current.data); err != nil {
+				t.Fatalf("event data is not JSON: %q", line)
+			}
+		case line == "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read stream: %v", err)
+	}
+	return events
+}
+
+func TestResolveUsersStreamsAllIDs(t *testing.T) {
+	backend := &resolveBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"RESOLVE_CONCURRENCY": "2"})
+	srv := serveGateway(t, newTestGateway(t, cfg, upstream.addr))
+
+	body := `{"ids":["u1","u2","missing-1","u3","u1","u4"]}`
+	resp, err := http.Post(srv.URL+"/users/resolve", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /users/resolve: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, Content-Type = %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := readSSE(t, resp)
+	if len(events) == 0 || events[len(events)-1].name != "done" {
+		t.Fatalf("events = %+v, want done as last event", events)
+	}
+
+	var resolved, failed []string
+	for _, ev := range events[:len(events)-1] {
+		switch ev.name {
+		case "user":
+			resolved = append(resolved, ev.data["id"].(string))
+		case "error":
+			failed = append(failed, ev.data["id"].(string))
+			if ev.data["code"] != "NotFound" {
+				t.Fatalf("error event = %+v, want NotFound", ev.data)
+			}
+		default:
+			t.Fatalf("unexpected event %q", ev.name)
+		}
+	}
+	sort.Strings(resolved)
+	if strings.Join(resolved, ",") != "u1,u2,u3,u4" || strings.Join(failed, ",") != "missing-1" {
+		t.Fatalf("resolved = %v, failed = %v", resolved, failed)
+	}
+	if done := events[len(events)-1].data; done["resolved"] != float64(4) || done["failed"] != float64(1) {
+		t.Fatalf("done = %+v", done)
+	}
+
+	// Id duplikat di-lookup sekali, concurrency tidak melewati RESOLVE_CONCURRENCY
+	if got := backend.calls.Load(); got != 5 {
+		t.Fatalf("GetUser calls = %d, want 5 (duplicate u1 resolved once)", got)
+	}
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+	if backend.maxInFlight > 2 {
+		t.Fatalf("max concurrent GetUser = %d, want <= 2", backend.maxInFlight)
+	}
+}
+
+func TestResolveUsersRejectsInvalidBatch(t *testing.T) {
+	upstream := startUserService(t, &resolveBackend{})
+	router := testRouter(t, newTestGateway(t, testConfig(t, map[string]string{"RESOLVE_MAX_IDS": "2"}), upstream.addr))
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	for _, body := range []string{`{"ids":["a","b","c"]}`, `{"ids":[]}`, `{"ids":`} {
+		if rec := doRequest(router, http.MethodPost, "/users/resolve", body, jsonHeader); rec.Code != http.StatusBadRequest {
+			t.Fatalf("body %s: status = %d, want 400", body, rec.Code)
+		}
+	}
+	// Duplikat tidak dihitung dua kali terhadap batas
+	if rec := doRequest(router, http.MethodPost, "/users/resolve", `{"ids":["a","b","a"]}`, jsonHeader); rec.Code != http.StatusOK {
+		t.Fatalf("deduplicated batch: status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// responseCache adalah HTTP response cache in-process untuk GET yang idempotent
+// Berbeda dengan staleCache (fallback saat upstream down), cache ini benar-benar
+// menjawab request dari memory selama TTL belum habis → mengurangi beban User Service
+//
+// Entry dipisah per credential (lihat principalKey): response yang diizinkan untuk 1 token
+// tidak pernah disajikan ke request dengan token lain atau tanpa token
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	items   map[string]*list.Element
+	order   *list.List // Front = paling baru dipakai (LRU)
+
+	lookups metric.Int64Counter // Hit/miss/bypass counter (atribut "result")
+}
+
+// cachedResponse adalah 1 response yang disimpan
+type cachedResponse struct {
+	key     string
+	path    string // Untuk Invalidate (semua principal & query dari 1 path)
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// newResponseCache membuat cache LRU dengan TTL; meter dipakai untuk hit/miss metrics
+func newResponseCache(ttl time.Duration, maxSize int, meter metric.Meter) (*responseCache, error) {
+	lookups, err := meter.Int64Counter("http.cache.lookups",
+		metric.WithDescription("Gateway response cache lookups by result (hit, miss, bypass)"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &responseCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+		lookups: lookups,
+	}, nil
+}
+
+// cacheKey = principal + path + query yang sudah dinormalisasi (url.Values.Encode mengurutkan key)
+// jadi ?id=1&x=2 dan ?x=2&id=1 memakai entry yang sama
+func cacheKey(principal, path string, query url.Values) string {
+	return principal + "|" + path + "?" + query.Encode()
+}
+
+// principalKey mengidentifikasi caller dari header Authorization & X-API-Key ("" = tanpa credential)
+// Disimpan sebagai hash, supaya token/API key tidak ikut tersimpan mentah di memory cache
+// Credential dianggap valid karena response 200-nya sudah lolos auth User Service; token yang
+// dicabut/expired masih bisa mendapat entry miliknya sampai RESPONSE_CACHE_TTL habis
+func principalKey(r *http.Request) string {
+	authorization, apiKey := r.Header.Get("Authorization"), r.Header.Get("X-API-Key")
+	if authorization == "" && apiKey == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(authorization + "\x00" + apiKey))
+	return hex.EncodeToString(sum[:16])
+}
+
+func (c *responseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+	entry := elem.Value.(*cachedResponse)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return entry
+}
+
+func (c *responseCache) put(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[entry.key] = c.order.PushFront(entry)
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+// Invalidate membuang response GET untuk 1 user (dipanggil setelah write sukses ke id tsb),
+// untuk semua principal (r.URL.Path sudah di-unescape, jadi id dipakai apa adanya)
+func (c *responseCache) Invalidate(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := "/users/" + id
+	for key, elem := range c.items {
+		if elem.Value.(*cachedResponse).path == path {
+			c.order.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// Purge membuang semua entry (dipakai write massal yang id-nya tidak diketahui gateway)
+func (c *responseCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+func (c *responseCache) record(ctx context.Context, result string) {
+	c.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// cacheRecorder meneruskan response ke client sambil menyalin status + body
+type cacheRecorder struct {
+	http.ResponseWriter
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rec *cacheRecorder) WriteHeader(code int) {
+	if rec.header == nil {
+		rec.status = code
+		rec.header = rec.ResponseWriter.Header().Clone()
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *cacheRecorder) Write(b []byte) (int, error) {
+	if rec.header == nil {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// cacheGET adalah middleware response cache untuk route GET
+//   - Cache-Control: no-cache di request → lewati cache, ambil fresh (hasil tetap disimpan)
+//   - Entry dipisah per credential (Authorization / X-API-Key), jadi cache tetap jalan saat auth aktif
+//   - Hanya response 200 yang disimpan; response dengan Cache-Control: no-store
+//     (contoh: data stale saat upstream down) tidak pernah disimpan
+func (gw *APIGateway) cacheGET(next http.HandlerFunc) http.HandlerFunc {
+	if gw.responses == nil {
+		return next // Response cache tidak diaktifkan
+	}
+	c := gw.responses
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next(w, r)
+			return
+		}
+
+		// Response bisa berbeda per token (atau ditolak User Service), jadi key ikut principal
+		key := cacheKey(principalKey(r), r.URL.Path, r.URL.Query())
+
+		if strings.Contains(r.Header.Get("Cache-Control"), "no-cache") {
+			c.record(r.Context(), "bypass")
+			w.Header().Set("X-Cache", "BYPASS")
+		} else if entry := c.get(key); entry != nil {
+			c.record(r.Context(), "hit")
+			for k, v := range entry.header {
+				w.Header()[k] = v
+			}
+			w.Header().Set("X-Cache", "HIT")
+			w.WriteHeader(entry.status)
+			w.Write(entry.body)
+			return
+		} else {
+			c.record(r.Context(), "miss")
+			w.Header().Set("X-Cache", "MISS")
+		}
+
+		rec := &cacheRecorder{ResponseWriter: w}
+		next(rec, r)
+
+		if rec.status != http.StatusOK || strings.Contains(rec.header.Get("Cache-Control"), "no-store") {
+			return
+		}
+		rec.header.Del("X-Cache")
+		// Header CORS di-set middleware cors per Origin request, jangan ikut di-replay ke origin lain
+		for k := range rec.header {
+			if k == "Vary" || strings.HasPrefix(k, "Access-Control-") {
+				rec.header.Del(k)
+			}
+		}
+		c.put(&cachedResponse{
+			key:     key,
+			path:    r.URL.Path,
+			status:  rec.status,
+			header:  rec.header,
+			body:    rec.body.Bytes(),
+			expires: time.Now().Add(c.ttl),
+		})
+	}
+}
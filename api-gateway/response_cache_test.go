@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"go.opentelemetry.io/otel/metric/noop"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// cacheBackend adalah User Service palsu untuk test response cache:
+// GetUser menghitung call dan mengisi Name dengan credential caller
+type cacheBackend struct {
+	pb.UnimplementedUserServiceServer
+	getCalls atomic.Int32
+}
+
+func (b *cacheBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	b.getCalls.Add(1)
+	md, _ := metadata.FromIncomingContext(ctx)
+	caller := strings.Join(append(md.Get("authorization"), md.Get("x-api-key")...), ",")
+	if caller == "Bearer denied" {
+		return nil, status.Error(codes.PermissionDenied, "denied")
+	}
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id, Name: "seen-by:" + caller}}, nil
+}
+
+func (b *cacheBackend) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	return &pb.DeleteUserResponse{Deleted: true}, nil
+}
+
+func (b *cacheBackend) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	return &pb.UpdateUserResponse{User: &pb.User{Id: req.Id, Name: req.Name, Version: req.ExpectedVersion + 1}}, nil
+}
+
+func newCacheTestRouter(t *testing.T) (http.Handler, *cacheBackend) {
+	t.Helper()
+	backend := &cacheBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"RESPONSE_CACHE_TTL": "1m"})
+	return testRouter(t, newTestGateway(t, cfg, upstream.addr)), backend
+}
+
+func TestResponseCacheKeyedByCredential(t *testing.T) {
+	router, backend := newCacheTestRouter(t)
+
+	get := func(header http.Header) (string, string) {
+		t.Helper()
+		rec := doRequest(router, http.MethodGet, "/users/u1", "", header)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body)
+		}
+		return rec.Header().Get("X-Cache"), rec.Body.String()
+	}
+
+	alice := http.Header{"Authorization": {"Bearer alice"}}
+	bob := http.Header{"Authorization": {"Bearer bob"}}
+	apiKey := http.Header{"X-Api-Key": {"0123456789abcdef"}}
+
+	if xCache, _ := get(alice); xCache != "MISS" {
+		t.Fatalf("first alice request X-Cache = %q, want MISS", xCache)
+	}
+	xCache, body := get(alice)
+	if xCache != "HIT" || !strings.Contains(body, "seen-by:Bearer alice") {
+		t.Fatalf("second alice request X-Cache = %q body = %s, want HIT with alice's response", xCache, body)
+	}
+
+	// Principal lain tidak boleh mendapat entry milik alice
+	for name, header := range map[string]http.Header{"bob": bob, "api key": apiKey, "anonymous": {}} {
+		xCache, body := get(header)
+		if xCache != "MISS" || strings.Contains(body, "alice") {
+			t.Fatalf("%s: X-Cache = %q body = %s, want MISS without alice's response", name, xCache, body)
+		}
+	}
+	if got := backend.getCalls.Load(); got != 4 {
+		t.Fatalf("GetUser calls = %d, want 4 (1 per principal)", got)
+	}
+
+	// Write membuang entry untuk SEMUA principal
+	if rec := doRequest(router, http.MethodDelete, "/users/u1", "", alice); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	for name, header := range map[string]http.Header{"alice": alice, "bob": bob, "api key": apiKey, "anonymous": {}} {
+		if xCache, _ := get(header); xCache != "MISS" {
+			t.Fatalf("%s after delete: X-Cache = %q, want MISS", name, xCache)
+		}
+	}
+}
+
+func TestResponseCacheSkipsRejectedCredential(t *testing.T) {
+	router, backend := newCacheTestRouter(t)
+	denied := http.Header{"Authorization": {"Bearer denied"}}
+
+	for i := 0; i < 2; i++ {
+		rec := doRequest(router, http.MethodGet, "/users/u1", "", denied)
+		if rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want 403", rec.Code)
+		}
+	}
+	if got := backend.getCalls.Load(); got != 2 {
+		t.Fatalf("GetUser calls = %d, want 2 (errors are never cached)", got)
+	}
+}
+
+func TestResponseCacheHitMissInvalidation(t *testing.T) {
+	router, backend := newCacheTestRouter(t)
+
+	get := func(header http.Header) string {
+		t.Helper()
+		rec := doRequest(router, http.MethodGet, "/users/u1", "", header)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body)
+		}
+		return rec.Header().Get("X-Cache")
+	}
+
+	if xCache := get(nil); xCache != "MISS" {
+		t.Fatalf("first request X-Cache = %q, want MISS", xCache)
+	}
+	if xCache := get(nil); xCache != "HIT" {
+		t.Fatalf("second request X-Cache = %q, want HIT", xCache)
+	}
+	if got := backend.getCalls.Load(); got != 1 {
+		t.Fatalf("GetUser calls = %d, want 1 (second request served from cache)", got)
+	}
+
+	// Cache-Control: no-cache → selalu ke upstream
+	if xCache := get(http.Header{"Cache-Control": {"no-cache"}}); xCache != "BYPASS" {
+		t.Fatalf("no-cache request X-Cache = %q, want BYPASS", xCache)
+	}
+	if got := backend.getCalls.Load(); got != 2 {
+		t.Fatalf("GetUser calls = %d, want 2 after no-cache", got)
+	}
+
+	// Update sukses ke id yang sama membuang entry
+	body := `{"name":"Alice","email":"alice@example.com","expectedVersion":1}`
+	if rec := doRequest(router, http.MethodPut, "/users/u1", body, http.Header{"Content-Type": {"application/json"}}); rec.Code != http.StatusOK {
+		t.Fatalf("update status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if xCache := get(nil); xCache != "MISS" {
+		t.Fatalf("after update X-Cache = %q, want MISS", xCache)
+	}
+
+	// Write ke id lain tidak menyentuh entry u1
+	if rec := doRequest(router, http.MethodDelete, "/users/u2", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	if xCache := get(nil); xCache != "HIT" {
+		t.Fatalf("after write to other id X-Cache = %q, want HIT", xCache)
+	}
+}
+
+func TestResponseCacheTTLAndEviction(t *testing.T) {
+	c, err := newResponseCache(20*time.Millisecond, 2, noop.NewMeterProvider().Meter("test"))
+	if err != nil {
+		t.Fatalf("newResponseCache: %v", err)
+	}
+	put := func(key string) {
+		c.put(&cachedResponse{key: key, path: "/users/" + key, status: http.StatusOK, expires: time.Now().Add(c.ttl)})
+	}
+
+	put("a")
+	put("b")
+	c.get("a") // a jadi paling baru dipakai
+	put("c")   // maxSize 2 → b (LRU) dibuang
+	if c.get("b") != nil {
+		t.Fatal("least recently used entry was not evicted")
+	}
+	if c.get("a") == nil || c.get("c") == nil {
+		t.Fatal("recent entries evicted")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if c.get("a") != nil {
+		t.Fatal("expired entry still served")
+	}
+}
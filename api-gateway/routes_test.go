@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+// routeBackend mencatat RPC & id yang diterima, untuk memastikan path param sampai ke gRPC
+type routeBackend struct {
+	pb.UnimplementedUserServiceServer
+	mu    sync.Mutex
+	calls []string // "GetUser:<id>", dst
+}
+
+func (b *routeBackend) record(call string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, call)
+}
+
+func (b *routeBackend) lastCall() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.calls) == 0 {
+		return ""
+	}
+	return b.calls[len(b.calls)-1]
+}
+
+func (b *routeBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	b.record("GetUser:" + req.Id)
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func (b *routeBackend) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	b.record("UpdateUser:" + req.Id)
+	return &pb.UpdateUserResponse{User: &pb.User{Id: req.Id, Version: req.ExpectedVersion + 1}}, nil
+}
+
+func (b *routeBackend) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	b.record("DeleteUser:" + req.Id)
+	return &pb.DeleteUserResponse{Deleted: true}, nil
+}
+
+func (b *routeBackend) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	b.record("CreateUser")
+	return &pb.CreateUserResponse{User: &pb.User{Id: "new"}, Success: true}, nil
+}
+
+func (b *routeBackend) ListUsersPage(ctx context.Context, req *pb.ListUsersPageRequest) (*pb.ListUsersPageResponse, error) {
+	b.record("ListUsersPage")
+	return &pb.ListUsersPageResponse{}, nil
+}
+
+func TestRoutesExtractPathID(t *testing.T) {
+	backend := &routeBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	updateBody := `{"name":"Alice","email":"alice@example.com","expectedVersion":1}`
+
+	tests := []struct {
+		method, target, body string
+		wantStatus           int
+		wantCall             string
+	}{
+		{http.MethodGet, "/users/abc", "", http.StatusOK, "GetUser:abc"},
+		{http.MethodGet, "/users/a%20b", "", http.StatusOK, "GetUser:a b"},
+		{http.MethodPut, "/users/abc", updateBody, http.StatusOK, "UpdateUser:abc"},
+		{http.MethodDelete, "/users/abc", "", http.StatusOK, "DeleteUser:abc"},
+		{http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com"}`, http.StatusCreated, "CreateUser"},
+		{http.MethodGet, "/users", "", http.StatusOK, "ListUsersPage"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.target, func(t *testing.T) {
+			rec := doRequest(router, tt.method, tt.target, tt.body, jsonHeader)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := backend.lastCall(); got != tt.wantCall {
+				t.Fatalf("backend call = %q, want %q", got, tt.wantCall)
+			}
+		})
+	}
+}
+
+func TestUpdateUserBodyIDMustMatchPath(t *testing.T) {
+	backend := &routeBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	body := `{"id":"other","name":"Alice","email":"alice@example.com","expectedVersion":1}`
+	rec := doRequest(router, http.MethodPut, "/users/abc", body, http.Header{"Content-Type": {"application/json"}})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+	if got := backend.lastCall(); got != "" {
+		t.Fatalf("backend call = %q, want none", got)
+	}
+}
+
+func TestLegacyQueryRoutes(t *testing.T) {
+	backend := &routeBackend{}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	redirects := []struct {
+		method, target, wantLocation string
+	}{
+		{http.MethodGet, "/users/get?id=abc&fields=name", "/users/abc?fields=name"},
+		{http.MethodGet, "/users/get?id=a/b", "/users/a%2Fb"},
+		{http.MethodDelete, "/users/delete?id=abc", "/users/abc"},
+		{http.MethodPost, "/users/create", "/users"},
+	}
+	for _, tt := range redirects {
+		rec := doRequest(router, tt.method, tt.target, "", nil)
+		if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != tt.wantLocation {
+			t.Fatalf("%s %s: status = %d, Location = %q; want 308 to %s", tt.method, tt.target, rec.Code, rec.Header().Get("Location"), tt.wantLocation)
+		}
+		if rec.Header().Get("Deprecation") != "true" {
+			t.Fatalf("%s %s: missing Deprecation header", tt.method, tt.target)
+		}
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/users/get", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("legacy get without id: status = %d, want 400", rec.Code)
+	}
+
+	// PUT /users/update dilayani langsung (id di body), tetap ditandai deprecated
+	body := `{"id":"abc","name":"Alice","email":"alice@example.com","expectedVersion":1}`
+	rec := doRequest(router, http.MethodPut, "/users/update", body, http.Header{"Content-Type": {"application/json"}})
+	if rec.Code != http.StatusOK || rec.Header().Get("Deprecation") != "true" {
+		t.Fatalf("legacy update: status = %d, Deprecation = %q (body: %s)", rec.Code, rec.Header().Get("Deprecation"), rec.Body)
+	}
+	if got := backend.lastCall(); got != "UpdateUser:abc" {
+		t.Fatalf("backend call = %q, want UpdateUser:abc", got)
+	}
+}
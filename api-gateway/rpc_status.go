@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rpcEvent adalah 1 hasil panggilan RPC (immutable, di-swap secara atomic)
+type rpcEvent struct {
+	At      time.Time `json:"at"`
+	Code    string    `json:"code"`
+	Message string    `json:"message,omitempty"`
+}
+
+// methodStatus menyimpan hasil terakhir 1 gRPC method
+// Pointer di-swap atomic, jadi request yang sedang jalan tidak perlu lock bersama
+type methodStatus struct {
+	lastSuccess atomic.Pointer[rpcEvent]
+	lastError   atomic.Pointer[rpcEvent]
+}
+
+// rpcStatusTracker mencatat last-success & last-error per method (dilihat dari sisi gateway)
+// Menjawab "CreateUser sekarang jalan atau tidak?" tanpa perlu grep log
+type rpcStatusTracker struct {
+	methods sync.Map // full method name → *methodStatus
+}
+
+func (t *rpcStatusTracker) record(method string, err error) {
+	v, _ := t.methods.LoadOrStore(method, &methodStatus{})
+	ms := v.(*methodStatus)
+
+	st := status.Convert(err)
+	if st.Code() == codes.Canceled {
+		return // Dibatalkan oleh gateway sendiri (contoh: hedge attempt yang kalah), bukan kegagalan service
+	}
+	event := &rpcEvent{At: time.Now().UTC(), Code: st.Code().String()}
+	if err == nil {
+		ms.lastSuccess.Store(event)
+		return
+	}
+	event.Message = st.Message()
+	ms.lastError.Store(event)
+}
+
+// unaryInterceptor mencatat hasil setiap unary RPC
+func (t *rpcStatusTracker) unaryInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		t.record(method, err)
+		return err
+	}
+}
+
+// streamInterceptor mencatat hasil streaming RPC
+// Hasil stream baru diketahui di akhir: io.EOF = sukses, error lain = gagal
+func (t *rpcStatusTracker) streamInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			t.record(method, err)
+			return nil, err
+		}
+		return &trackedStream{ClientStream: cs, method: method, tracker: t}, nil
+	}
+}
+
+// trackedStream mencatat hasil stream tepat 1 kali, saat RecvMsg selesai/gagal
+type trackedStream struct {
+	grpc.ClientStream
+	method  string
+	tracker *rpcStatusTracker
+	once    sync.Once
+}
+
+func (s *trackedStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				s.tracker.record(s.method, nil)
+			} else {
+				s.tracker.record(s.method, err)
+			}
+		})
+	}
+	return err
+}
+
+// rpcStatusEntry adalah format JSON /debug/rpc-status untuk 1 method
+type rpcStatusEntry struct {
+	Method      string    `json:"method"`
+	LastSuccess *rpcEvent `json:"lastSuccess"`
+	LastError   *rpcEvent `json:"lastError"`
+}
+
+// snapshot return status semua method yang pernah dipanggil, urut nama method
+func (t *rpcStatusTracker) snapshot() []rpcStatusEntry {
+	var out []rpcStatusEntry
+	t.methods.Range(func(key, value interface{}) bool {
+		ms := value.(*methodStatus)
+		out = append(out, rpcStatusEntry{
+			Method:      key.(string),
+			LastSuccess: ms.lastSuccess.Load(),
+			LastError:   ms.lastError.Load(),
+		})
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Method < out[j].Method })
+	return out
+}
+
+// RPCStatusHandler menghandle GET /debug/rpc-status (admin)
+func (gw *APIGateway) RPCStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"methods": gw.rpcStatus.snapshot(),
+	})
+}
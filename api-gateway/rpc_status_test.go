@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRPCStatusTrackerRecords(t *testing.T) {
+	tracker := &rpcStatusTracker{}
+	method := pb.UserService_CreateUser_FullMethodName
+
+	tracker.record(method, nil)
+	snap := tracker.snapshot()
+	if len(snap) != 1 || snap[0].LastSuccess == nil || snap[0].LastError != nil {
+		t.Fatalf("after success: %+v, want only lastSuccess", snap)
+	}
+	firstSuccess := snap[0].LastSuccess
+
+	tracker.record(method, status.Error(codes.AlreadyExists, "email already registered"))
+	snap = tracker.snapshot()
+	if got := snap[0].LastError; got == nil || got.Code != "AlreadyExists" || got.Message != "email already registered" {
+		t.Fatalf("after error: lastError = %+v", got)
+	}
+	if snap[0].LastSuccess != firstSuccess {
+		t.Fatal("error call overwrote lastSuccess")
+	}
+
+	tracker.record(method, nil)
+	if snap = tracker.snapshot(); snap[0].LastSuccess == firstSuccess || snap[0].LastError == nil {
+		t.Fatalf("after second success: %+v, want new lastSuccess and unchanged lastError", snap[0])
+	}
+
+	// Cancel dari gateway sendiri (hedge yang kalah) bukan kegagalan service
+	tracker.record(pb.UserService_GetUser_FullMethodName, status.FromContextError(context.Canceled).Err())
+	for _, entry := range tracker.snapshot() {
+		if entry.Method == pb.UserService_GetUser_FullMethodName && entry.LastError != nil {
+			t.Fatalf("cancelled call recorded as error: %+v", entry.LastError)
+		}
+	}
+
+	// Error non-gRPC tetap tercatat (sebagai Unknown)
+	tracker.record(method, errors.New("boom"))
+	if got := tracker.snapshot()[0].LastError; got.Code != "Unknown" {
+		t.Fatalf("plain error code = %q, want Unknown", got.Code)
+	}
+}
+
+func TestRPCStatusEndpoint(t *testing.T) {
+	upstream := startUserService(t, &slowFirstBackend{notFound: true})
+	cfg := testConfig(t, map[string]string{"ADMIN_ENABLED": "true", "ADMIN_TOKEN": testAdminToken})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET /users/u1 = %d, want 404", rec.Code)
+	}
+
+	rec := doRequest(router, http.MethodGet, "/debug/rpc-status", "", http.Header{"Authorization": {"Bearer " + testAdminToken}})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/debug/rpc-status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	var body struct {
+		Methods []rpcStatusEntry `json:"methods"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, entry := range body.Methods {
+		if entry.Method == pb.UserService_GetUser_FullMethodName {
+			if entry.LastError == nil || entry.LastError.Code != "NotFound" || entry.LastSuccess != nil {
+				t.Fatalf("GetUser status = %+v, want only lastError NotFound", entry)
+			}
+			return
+		}
+	}
+	t.Fatalf("GetUser missing from %s", rec.Body)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// maxBodyBytes adalah batas ukuran body request JSON (1MB)
+const maxBodyBytes = 1 << 20
+
+// embeddedSchemas adalah JSON schema bawaan yang ikut di-compile ke binary
+//
+//go:embed schemas/*.json
+var embeddedSchemas embed.FS
+
+// schemaValidator menyimpan JSON schema yang sudah di-compile, per nama route
+type schemaValidator struct {
+	schemas map[string]*jsonschema.Schema
+}
+
+// fieldError adalah 1 error validasi untuk 1 field
+type fieldError struct {
+	Field   string `json:"field"`   // JSON pointer, contoh: "/email"
+	Message string `json:"message"` // Penjelasan yang bisa dibaca manusia
+}
+
+// newSchemaValidator compile semua schema bawaan
+// Kalau dir diisi, file <nama>.json di dir tersebut menggantikan schema bawaan dengan nama sama
+func newSchemaValidator(dir string) (*schemaValidator, error) {
+	sources := make(map[string][]byte)
+
+	entries, err := embeddedSchemas.ReadDir("schemas")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		data, err := embeddedSchemas.ReadFile("schemas/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		sources[strings.TrimSuffix(entry.Name(), ".json")] = data
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			sources[strings.TrimSuffix(filepath.Base(file), ".json")] = data
+		}
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.AssertFormat() // "format": "email" benar-benar divalidasi, bukan cuma anotasi
+
+	v := &schemaValidator{schemas: make(map[string]*jsonschema.Schema)}
+	for name, data := range sources {
+		doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		url := name + ".json"
+		if err := compiler.AddResource(url, doc); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		schema, err := compiler.Compile(url)
+		if err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+		v.schemas[name] = schema
+	}
+
+	return v, nil
+}
+
+// validate cek body terhadap schema; return daftar field error (kosong = valid)
+func (v *schemaValidator) validate(name string, body []byte) ([]fieldError, error) {
+	schema, ok := v.schemas[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q", name)
+	}
+
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(body))
+	if err != nil {
+		return []fieldError{{Field: "", Message: "invalid JSON: " + err.Error()}}, nil
+	}
+
+	var verr *jsonschema.ValidationError
+	if err := schema.Validate(inst); err != nil {
+		if !errors.As(err, &verr) {
+			return nil, err
+		}
+		return collectFieldErrors(verr), nil
+	}
+	return nil, nil
+}
+
+// collectFieldErrors mengambil error "daun" (paling spesifik) dari tree ValidationError
+func collectFieldErrors(verr *jsonschema.ValidationError) []fieldError {
+	printer := message.NewPrinter(language.English)
+
+	var out []fieldError
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			field := ""
+			if len(e.InstanceLocation) > 0 {
+				field = "/" + strings.Join(e.InstanceLocation, "/")
+			}
+			out = append(out, fieldError{Field: field, Message: e.ErrorKind.LocalizedString(printer)})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(verr)
+	return out
+}
+
+// validateBody adalah middleware yang memvalidasi body JSON terhadap schema sebelum
+// request diteruskan ke handler (dan ke gRPC). Gagal → 400 dengan daftar field error,
+// tanpa perlu round-trip ke User Service
+func (gw *APIGateway) validateBody(schemaName string, next http.HandlerFunc) http.HandlerFunc {
+	if gw.schemas == nil {
+		return next // Schema validation tidak diaktifkan
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Method tanpa body (misal GET yang salah route) biar handler yang menolak
+		if r.Body == nil || r.Method == http.MethodGet {
+			next(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		if err != nil {
+			http.Error(w, "request body too large or unreadable", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		fieldErrs, err := gw.schemas.validate(schemaName, body)
+		if err != nil {
+			log.Printf("❌ Schema validation error: %v", err)
+			http.Error(w, "schema validation unavailable", http.StatusInternalServerError)
+			return
+		}
+		if len(fieldErrs) > 0 {
+			log.Printf("🚫 Request body rejected by schema %s: %d field error(s)", schemaName, len(fieldErrs))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":  "request body failed validation",
+				"fields": fieldErrs,
+			})
+			return
+		}
+
+		// Body sudah dibaca habis → ganti dengan reader baru supaya handler bisa decode lagi
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(w, r)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSchemaValidateCreateUser(t *testing.T) {
+	v, err := newSchemaValidator("")
+	if err != nil {
+		t.Fatalf("newSchemaValidator: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string // Field yang error (kosong = valid)
+	}{
+		{"valid", `{"name":"Alice","email":"alice@example.com","age":30}`, nil},
+		{"valid with status", `{"name":"Alice","email":"alice@example.com","status":"active"}`, nil},
+		{"missing email", `{"name":"Alice"}`, []string{""}},
+		{"invalid email", `{"name":"Alice","email":"not-an-email"}`, []string{"/email"}},
+		{"age out of range", `{"name":"Alice","email":"alice@example.com","age":200}`, []string{"/age"}},
+		{"age wrong type", `{"name":"Alice","email":"alice@example.com","age":"thirty"}`, []string{"/age"}},
+		{"empty name", `{"name":"","email":"alice@example.com"}`, []string{"/name"}},
+		{"unknown field", `{"name":"Alice","email":"alice@example.com","admin":true}`, []string{""}},
+		{"invalid JSON", `{"name":`, []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := v.validate("create_user", []byte(tt.body))
+			if err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+				if e.Message == "" {
+					t.Errorf("field %q: empty message", e.Field)
+				}
+			}
+			if !reflect.DeepEqual(fields, tt.wantFields) {
+				t.Fatalf("field errors = %+v, want fields %v", errs, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestSchemaDirOverridesEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	sample := `{"type": "object", "required": ["nickname"], "properties": {"nickname": {"type": "string"}}}`
+	if err := os.WriteFile(filepath.Join(dir, "create_user.json"), []byte(sample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := newSchemaValidator(dir)
+	if err != nil {
+		t.Fatalf("newSchemaValidator: %v", err)
+	}
+	if errs, _ := v.validate("create_user", []byte(`{"nickname":"al"}`)); len(errs) != 0 {
+		t.Fatalf("sample schema rejected valid body: %+v", errs)
+	}
+	if errs, _ := v.validate("create_user", []byte(`{"name":"Alice","email":"alice@example.com"}`)); len(errs) == 0 {
+		t.Fatal("sample schema accepted body without nickname")
+	}
+	// Schema bawaan yang tidak di-override tetap ada
+	if _, err := v.validate("update_user", []byte(`{}`)); err != nil {
+		t.Fatalf("update_user schema missing: %v", err)
+	}
+}
+
+func TestValidateBodyRejectsBeforeUpstream(t *testing.T) {
+	backend := &writeBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"SCHEMA_VALIDATION": "true"})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+
+	rec := doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"nope","age":-1}`, jsonHeader)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid body: status = %d, want 400", rec.Code)
+	}
+	var body struct {
+		Error  string       `json:"error"`
+		Fields []fieldError `json:"fields"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Fields) != 2 {
+		t.Fatalf("fields = %+v, want errors for /email and /age", body.Fields)
+	}
+	if got := len(backend.createRequests()); got != 0 {
+		t.Fatalf("CreateUser calls = %d, want 0 (rejected at the gateway)", got)
+	}
+
+	rec = doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com","age":30}`, jsonHeader)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("valid body: status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	if got := len(backend.createRequests()); got != 1 {
+		t.Fatalf("CreateUser calls = %d, want 1", got)
+	}
+}
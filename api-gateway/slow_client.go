@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// aggregateChunkSize adalah ukuran potongan payload yang ditulis per write deadline
+const aggregateChunkSize = 32 * 1024
+
+// slowClientPolicy membatasi berapa lama response aggregated (1 JSON blob) boleh
+// "nyangkut" di memory gateway karena client lambat membaca
+type slowClientPolicy struct {
+	writeTimeout time.Duration // Batas waktu 1 chunk harus terkirim (client macet), 0 = off
+	maxHold      time.Duration // Batas total payload boleh ditahan sampai selesai terkirim, 0 = off
+}
+
+// slowClientPolicy return policy slow-client dari konfigurasi gateway
+func (gw *APIGateway) slowClientPolicy() slowClientPolicy {
+	return slowClientPolicy{writeTimeout: gw.cfg.AggregateWriteTimeout, maxHold: gw.cfg.AggregateMaxHold}
+}
+
+// writeCollectionGuarded sama seperti writeCollection, tapi payload ditulis per chunk
+// dengan write deadline. Kalau client tidak sanggup menghabiskan response tepat waktu,
+// koneksi diputus dan payload dilepas, jadi 1 client lambat tidak menahan memory gateway
+func writeCollectionGuarded(w http.ResponseWriter, r *http.Request, policy slowClientPolicy, key string, items interface{}, meta PageMeta) {
+	if policy.writeTimeout == 0 && policy.maxHold == 0 {
+		writeCollection(w, key, items, meta)
+		return
+	}
+
+	// 1. ENCODE DULU, supaya ukuran payload diketahui sebelum mulai menulis
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(map[string]interface{}{
+		key:    items,
+		"meta": meta,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	start := time.Now()
+	payload := buf.Bytes()
+	var holdDeadline time.Time
+	if policy.maxHold > 0 {
+		holdDeadline = start.Add(policy.maxHold)
+	}
+
+	// 2. TULIS PER CHUNK, deadline = yang lebih dulu antara write timeout & max hold
+	abort := func(err error) {
+		log.Printf("🐢 Slow client %s aborted after %v (%d of %d bytes sent): %v",
+			r.RemoteAddr, time.Since(start).Round(time.Millisecond), buf.Len()-len(payload), buf.Len(), err)
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "application/json")
+	for len(payload) > 0 {
+		deadline := holdDeadline
+		if policy.writeTimeout > 0 {
+			if next := time.Now().Add(policy.writeTimeout); deadline.IsZero() || next.Before(deadline) {
+				deadline = next
+			}
+		}
+		if err := rc.SetWriteDeadline(deadline); err != nil {
+			// Writer tidak mendukung deadline (http.ErrNotSupported): tulis sisanya apa adanya
+			log.Printf("⚠️  Write deadline unavailable, writing without slow-client guard: %v", err)
+			w.Write(payload)
+			return
+		}
+
+		n := min(len(payload), aggregateChunkSize)
+		if _, err := w.Write(payload[:n]); err != nil {
+			abort(err)
+			return
+		}
+
+		// Flush supaya chunk benar-benar ditulis ke socket di bawah deadline ini
+		// (bukan menumpuk di buffer bufio milik net/http).
+		// Middleware di atas (otelhttp) menelan error Flush, jadi deadline yang sudah lewat
+		// dianggap gagal: socket menolak write setelah deadline
+		rc.Flush()
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			abort(os.ErrDeadlineExceeded)
+			return
+		}
+		payload = payload[n:]
+	}
+
+	// 3. RESET DEADLINE supaya request berikutnya di koneksi keep-alive tidak kena
+	rc.SetWriteDeadline(time.Time{})
+}
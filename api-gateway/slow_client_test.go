@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// bigCollection cukup besar untuk memenuhi buffer socket kedua sisi
+func bigCollection() []string {
+	items := make([]string, 16*1024)
+	for i := range items {
+		items[i] = strings.Repeat("x", 1024)
+	}
+	return items
+}
+
+func serveGuarded(t *testing.T, policy slowClientPolicy, done chan<- time.Duration) *httptest.Server {
+	t.Helper()
+	items := bigCollection()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		writeCollectionGuarded(w, r, policy, "users", items, PageMeta{Count: len(items)})
+		done <- time.Since(start)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSlowClientAborted(t *testing.T) {
+	done := make(chan time.Duration, 1)
+	srv := serveGuarded(t, slowClientPolicy{writeTimeout: 100 * time.Millisecond, maxHold: time.Second}, done)
+
+	// Client membuka request tapi tidak membaca response sama sekali
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.(*net.TCPConn).SetReadBuffer(4096)
+	io.WriteString(conn, "GET /users HTTP/1.1\r\nHost: test\r\n\r\n")
+
+	select {
+	case elapsed := <-done:
+		if elapsed > 2*time.Second {
+			t.Fatalf("handler held the payload for %s", elapsed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler still blocked on a client that does not read")
+	}
+}
+
+func TestFastClientGetsFullResponse(t *testing.T) {
+	done := make(chan time.Duration, 1)
+	srv := serveGuarded(t, slowClientPolicy{writeTimeout: time.Second, maxHold: 10 * time.Second}, done)
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Users []string `json:"users"`
+		Meta  PageMeta `json:"meta"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Users) != 16*1024 || body.Meta.Count != 16*1024 {
+		t.Fatalf("got %d users (meta count %d), want %d", len(body.Users), body.Meta.Count, 16*1024)
+	}
+	<-done
+}
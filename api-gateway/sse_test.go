@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// gatedListBackend mengirim 1 user setiap kali next menerima sinyal,
+// jadi test bisa membuktikan event sampai ke client sebelum user berikutnya dikirim
+type gatedListBackend struct {
+	pb.UnimplementedUserServiceServer
+	next      chan struct{}
+	cancelled chan struct{}
+}
+
+func (b *gatedListBackend) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+	for i := 1; ; i++ {
+		select {
+		case _, ok := <-b.next:
+			if !ok {
+				return nil
+			}
+		case <-stream.Context().Done():
+			close(b.cancelled)
+			return stream.Context().Err()
+		}
+		user := &pb.User{Id: fmt.Sprintf("u%d", i), Name: "User"}
+		if err := stream.Send(&pb.UserResponse{User: user}); err != nil {
+			return err
+		}
+	}
+}
+
+// nextSSEEvent membaca 1 event SSE (sampai baris kosong)
+func nextSSEEvent(t *testing.T, reader *bufio.Reader) (name, data string) {
+	t.Helper()
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "":
+			return name, data
+		}
+	}
+}
+
+func TestStreamUsersSSEIncremental(t *testing.T) {
+	backend := &gatedListBackend{next: make(chan struct{}), cancelled: make(chan struct{})}
+	upstream := startUserService(t, backend)
+	srv := serveGateway(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	// Header response baru terkirim bersama event pertama
+	go func() { backend.next <- struct{}{} }()
+	resp := getStream(t, srv.URL+"/users/stream", "")
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+	reader := bufio.NewReader(resp.Body)
+
+	// Setiap event terbaca sebelum backend mengirim user berikutnya
+	for i, id := range []string{"u1", "u2", "u3"} {
+		if i > 0 {
+			backend.next <- struct{}{}
+		}
+		name, data := nextSSEEvent(t, reader)
+		if name != "user" || !strings.Contains(data, `"id":"`+id+`"`) {
+			t.Fatalf("event = %s %s, want user %s", name, data, id)
+		}
+	}
+	close(backend.next)
+	if name, data := nextSSEEvent(t, reader); name != "done" || data != `{"count":3}` {
+		t.Fatalf("last event = %s %s, want done with count 3", name, data)
+	}
+}
+
+func TestStreamUsersSSEClientDisconnectCancelsStream(t *testing.T) {
+	backend := &gatedListBackend{next: make(chan struct{}), cancelled: make(chan struct{})}
+	upstream := startUserService(t, backend)
+	srv := serveGateway(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/users/stream", nil)
+	go func() { backend.next <- struct{}{} }()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /users/stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if name, _ := nextSSEEvent(t, bufio.NewReader(resp.Body)); name != "user" {
+		t.Fatalf("first event = %q, want user", name)
+	}
+
+	cancel()
+	select {
+	case <-backend.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("backend stream was not cancelled after the client disconnected")
+	}
+}
+
+func TestStreamUsersSSEErrorAfterFirstEvent(t *testing.T) {
+	backend := newListBackend(2, "")
+	backend.err = status.Error(codes.Internal, "disk on fire")
+	upstream := startUserService(t, backend)
+	srv := serveGateway(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	events := readSSE(t, getStream(t, srv.URL+"/users/stream", ""))
+	if len(events) != 3 || events[0].name != "user" || events[1].name != "user" {
+		t.Fatalf("events = %+v, want 2 users then error", events)
+	}
+	if last := events[2]; last.name != "error" || last.data["code"] != "Internal" {
+		t.Fatalf("last event = %+v, want error with code Internal", last)
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// staleCache menyimpan versi terakhir setiap user yang pernah sukses dibaca
+// Dipakai HANYA sebagai fallback saat User Service tidak bisa dihubungi
+// (bukan cache biasa — request normal tetap selalu ke upstream)
+//
+// Entry dipisah per credential (principalKey), sama seperti responseCache: saat upstream down
+// gateway tidak bisa menjalankan auth User Service, jadi caller hanya boleh mendapat user
+// yang sebelumnya berhasil dibaca dengan credential-nya sendiri
+type staleCache struct {
+	mu      sync.Mutex
+	maxSize int
+	items   map[string]*list.Element
+	order   *list.List // Front = paling baru dipakai (LRU)
+}
+
+// staleItem adalah isi 1 element di linked list
+type staleItem struct {
+	key  string // principal + "|" + id
+	id   string
+	user *pb.User
+}
+
+// newStaleCache membuat cache LRU dengan kapasitas maxSize
+func newStaleCache(maxSize int) *staleCache {
+	return &staleCache{
+		maxSize: maxSize,
+		items:   make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// staleKey menggabungkan principal (lihat principalKey) dan id user
+func staleKey(principal, id string) string {
+	return principal + "|" + id
+}
+
+// Put menyimpan/meng-update last-known user untuk principal
+func (c *staleCache) Put(principal string, user *pb.User) {
+	if user == nil || user.Id == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := staleKey(principal, user.Id)
+	if elem, ok := c.items[key]; ok {
+		elem.Value.(*staleItem).user = user
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&staleItem{key: key, id: user.Id, user: user})
+
+	// Buang user yang paling lama tidak dipakai kalau sudah penuh
+	for c.maxSize > 0 && c.order.Len() > c.maxSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*staleItem).key)
+	}
+}
+
+// Get mengambil last-known user milik principal (nil kalau tidak ada)
+func (c *staleCache) Get(principal, id string) *pb.User {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[staleKey(principal, id)]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*staleItem).user
+}
+
+// Delete membuang user dari cache untuk semua principal
+// (user sudah dihapus/berubah → versi lama tidak boleh tersaji sebagai stale)
+func (c *staleCache) Delete(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, elem := range c.items {
+		if elem.Value.(*staleItem).id == id {
+			c.order.Remove(elem)
+			delete(c.items, key)
+		}
+	}
+}
+
+// Purge membuang semua entry (dipakai write massal yang id-nya tidak diketahui gateway)
+func (c *staleCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// isUpstreamDown cek apakah error berarti upstream tidak bisa dihubungi
+// (bukan error bisnis seperti NotFound / InvalidArgument)
+func isUpstreamDown(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyBackend adalah User Service palsu yang bisa "dimatikan": selama down semua RPC → Unavailable
+type flakyBackend struct {
+	pb.UnimplementedUserServiceServer
+	down atomic.Bool
+}
+
+func (b *flakyBackend) unavailable() error {
+	if b.down.Load() {
+		return status.Error(codes.Unavailable, "connection refused")
+	}
+	return nil
+}
+
+func (b *flakyBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	if err := b.unavailable(); err != nil {
+		return nil, err
+	}
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id, Name: "Alice", Email: "alice@example.com"}}, nil
+}
+
+func (b *flakyBackend) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	if err := b.unavailable(); err != nil {
+		return nil, err
+	}
+	return &pb.CreateUserResponse{User: &pb.User{Id: "new", Name: req.Name, Email: req.Email}, Success: true}, nil
+}
+
+func (b *flakyBackend) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if err := b.unavailable(); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteUserResponse{Deleted: true}, nil
+}
+
+// BulkDeleteUsers menghapus sejumlah user yang id-nya tidak diketahui gateway
+func (b *flakyBackend) BulkDeleteUsers(ctx context.Context, req *pb.BulkDeleteRequest) (*pb.BulkDeleteResponse, error) {
+	if err := b.unavailable(); err != nil {
+		return nil, err
+	}
+	return &pb.BulkDeleteResponse{DeletedCount: 2}, nil
+}
+
+func newStaleTestRouter(t *testing.T, enabled string) (http.Handler, *flakyBackend) {
+	t.Helper()
+	backend := &flakyBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"STALE_WHILE_DOWN": enabled, "GRPC_MAX_RETRIES": "0"})
+	return testRouter(t, newTestGateway(t, cfg, upstream.addr)), backend
+}
+
+func TestStaleWhileDownServesLastKnownUser(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "true")
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("warm-up status = %d", rec.Code)
+	}
+	backend.down.Store(true)
+
+	rec := doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status while down = %d, want 200 (body: %s)", rec.Code, rec.Body)
+	}
+	var body struct {
+		User  map[string]interface{} `json:"user"`
+		Stale bool                   `json:"stale"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !body.Stale || body.User["id"] != "u1" || body.User["name"] != "Alice" {
+		t.Fatalf("body = %s, want last-known u1 with stale=true", rec.Body)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+		t.Fatalf("Cache-Control = %q, want no-store", got)
+	}
+}
+
+func TestStaleWhileDownCacheMiss(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "true")
+	backend.down.Store(true)
+
+	if rec := doRequest(router, http.MethodGet, "/users/never-read", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 (body: %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestStaleWhileDownNotServedAfterDelete(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "true")
+
+	doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	if rec := doRequest(router, http.MethodDelete, "/users/u1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("delete status = %d", rec.Code)
+	}
+	backend.down.Store(true)
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 for deleted user", rec.Code)
+	}
+}
+
+func TestStaleWhileDownNotServedAfterBulkDelete(t *testing.T) {
+	backend := &flakyBackend{}
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{
+		"STALE_WHILE_DOWN": "true",
+		"GRPC_MAX_RETRIES": "0",
+		"ADMIN_ENABLED":    "true",
+		"ADMIN_TOKEN":      testAdminToken,
+	})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+
+	doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	admin := http.Header{"Authorization": {"Bearer " + testAdminToken}}
+	if rec := doRequest(router, http.MethodDelete, "/users?domain=example.com&confirm=true", "", admin); rec.Code != http.StatusOK {
+		t.Fatalf("bulk delete status = %d (body: %s)", rec.Code, rec.Body)
+	}
+	backend.down.Store(true)
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 after bulk delete (body: %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestStaleWhileDownWritesFailFast(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "true")
+	doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	backend.down.Store(true)
+
+	body := `{"name":"Bob","email":"bob@example.com","age":30}`
+	if rec := doRequest(router, http.MethodPost, "/users", body, http.Header{"Content-Type": {"application/json"}}); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("create status = %d, want 503 (body: %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestStaleWhileDownDisabled(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "false")
+	doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	backend.down.Store(true)
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 when STALE_WHILE_DOWN=false", rec.Code)
+	}
+}
+
+func TestStaleWhileDownIsolatedPerCredential(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "true")
+	alice := http.Header{"Authorization": {"Bearer alice-token"}}
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", alice); rec.Code != http.StatusOK {
+		t.Fatalf("warm-up status = %d", rec.Code)
+	}
+	backend.down.Store(true)
+
+	// Auth User Service tidak bisa jalan selama down → credential lain tidak boleh mendapat data alice
+	for name, header := range map[string]http.Header{
+		"other token":   {"Authorization": {"Bearer mallory-token"}},
+		"API key":       {"X-Api-Key": {"mallory-api-key-0123456789"}},
+		"no credential": nil,
+	} {
+		if rec := doRequest(router, http.MethodGet, "/users/u1", "", header); rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("%s: status = %d, want 503 (body: %s)", name, rec.Code, rec.Body)
+		}
+	}
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", alice); rec.Code != http.StatusOK {
+		t.Fatalf("same token: status = %d, want 200 (body: %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestStaleWhileDownSkipsFieldMask(t *testing.T) {
+	router, backend := newStaleTestRouter(t, "true")
+	doRequest(router, http.MethodGet, "/users/u1", "", nil)
+	backend.down.Store(true)
+
+	// Cache berisi user lengkap: menyajikannya untuk ?fields= akan membocorkan field yang tidak diminta
+	if rec := doRequest(router, http.MethodGet, "/users/u1?fields=name", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 for masked request (body: %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestStaleCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newStaleCache(2)
+	c.Put("", &pb.User{Id: "a"})
+	c.Put("", &pb.User{Id: "b"})
+	c.Get("", "a") // "b" sekarang paling lama tidak dipakai
+	c.Put("", &pb.User{Id: "c"})
+
+	if c.Get("", "b") != nil {
+		t.Fatal("b should have been evicted")
+	}
+	if c.Get("", "a") == nil || c.Get("", "c") == nil {
+		t.Fatal("a and c should still be cached")
+	}
+
+	c.Delete("a")
+	if c.Get("", "a") != nil {
+		t.Fatal("a should be gone after Delete")
+	}
+}
+
+func TestStaleCacheDeleteAllPrincipals(t *testing.T) {
+	c := newStaleCache(10)
+	c.Put("alice", &pb.User{Id: "u1"})
+	c.Put("bob", &pb.User{Id: "u1"})
+	c.Put("bob", &pb.User{Id: "u2"})
+
+	if c.Get("alice", "u2") != nil {
+		t.Fatal("alice got an entry stored for bob")
+	}
+	c.Delete("u1")
+	if c.Get("alice", "u1") != nil || c.Get("bob", "u1") != nil {
+		t.Fatal("u1 should be gone for every principal after Delete")
+	}
+	if c.Get("bob", "u2") == nil {
+		t.Fatal("u2 should still be cached")
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userStream adalah tipe stream server-streaming yang return UserResponse
+// (ListUsers, ListUsersByDateRange, dll memakai tipe yang sama)
+type userStream = grpc.ServerStreamingClient[pb.UserResponse]
+
+// openUserStream membuka stream dan mengulang (dengan exponential backoff)
+// kalau stream gagal SEBELUM ada data yang diterima (contoh: backend sedang restart)
+//
+// Di gRPC, error saat open stream baru kelihatan di Recv() pertama, jadi fungsi ini
+// "mengintip" message pertama. Stream yang belum menghasilkan data aman dibuka ulang;
+// setelah data mulai mengalir TIDAK pernah di-retry (client sudah menerima sebagian)
+func (gw *APIGateway) openUserStream(ctx context.Context, open func(context.Context) (userStream, error)) (userStream, error) {
+	attempts, backoff := gw.cfg.StreamOpenAttempts, gw.cfg.StreamOpenBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			log.Printf("🔁 Retrying stream open (attempt %d/%d) after %s: %v", attempt, attempts, backoff, lastErr)
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return nil, lastErr
+			}
+			backoff *= 2
+		}
+
+		stream, err := open(ctx)
+		if err == nil {
+			var first *pb.UserResponse
+			first, err = stream.Recv()
+			if err == nil || err == io.EOF {
+				// Stream sehat (atau kosong tapi sukses) → kembalikan dengan message pertama di-buffer
+				return &peekedStream{ServerStreamingClient: stream, first: first, firstErr: err}, nil
+			}
+		}
+
+		lastErr = err
+		if status.Code(err) != codes.Unavailable {
+			return nil, err // Error bisnis (InvalidArgument, dll) tidak akan sembuh dengan retry
+		}
+	}
+	return nil, lastErr
+}
+
+// peekedStream mengembalikan message pertama yang sudah dibaca openUserStream,
+// lalu melanjutkan Recv() ke stream asli
+type peekedStream struct {
+	grpc.ServerStreamingClient[pb.UserResponse]
+	first    *pb.UserResponse
+	firstErr error // io.EOF kalau stream kosong
+	consumed bool
+}
+
+func (s *peekedStream) Recv() (*pb.UserResponse, error) {
+	if !s.consumed {
+		s.consumed = true
+		if s.firstErr != nil {
+			return nil, s.firstErr
+		}
+		return s.first, nil
+	}
+	return s.ServerStreamingClient.Recv()
+}
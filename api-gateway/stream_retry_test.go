@@ -0,0 +1,88 @@
+package main
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// flakyOpenBackend: `failOpens` pembukaan stream pertama gagal dengan openErr sebelum data apapun
+type flakyOpenBackend struct {
+	*listBackend
+	failOpens int32
+	openErr   error
+	opens     atomic.Int32
+}
+
+func (b *flakyOpenBackend) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+	if b.opens.Add(1) <= b.failOpens {
+		return b.openErr
+	}
+	return b.listBackend.ListUsers(req, stream)
+}
+
+func newStreamRetryRouter(t *testing.T, backend *flakyOpenBackend, attempts string) http.Handler {
+	t.Helper()
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{
+		"GRPC_MAX_RETRIES":     "0", // Retry transport gRPC dimatikan, yang diuji retry open stream gateway
+		"STREAM_OPEN_ATTEMPTS": attempts,
+		"STREAM_OPEN_BACKOFF":  "5ms",
+	})
+	return testRouter(t, newTestGateway(t, cfg, upstream.addr))
+}
+
+func TestStreamOpenRetriedAfterTransientFailure(t *testing.T) {
+	backend := &flakyOpenBackend{listBackend: newListBackend(3, ""), failOpens: 1, openErr: status.Error(codes.Unavailable, "restarting")}
+	router := newStreamRetryRouter(t, backend, "3")
+
+	if rec := doRequest(router, http.MethodGet, "/users/stream?limit=3", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body: %s)", rec.Code, rec.Body)
+	}
+	if got := backend.opens.Load(); got != 2 {
+		t.Fatalf("stream opens = %d, want 2 (1 failure + 1 retry)", got)
+	}
+}
+
+func TestStreamOpenGivesUpAfterAttempts(t *testing.T) {
+	backend := &flakyOpenBackend{listBackend: newListBackend(3, ""), failOpens: 10, openErr: status.Error(codes.Unavailable, "down")}
+	router := newStreamRetryRouter(t, backend, "2")
+
+	if rec := doRequest(router, http.MethodGet, "/users/stream?limit=3", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", rec.Code)
+	}
+	if got := backend.opens.Load(); got != 2 {
+		t.Fatalf("stream opens = %d, want 2 (STREAM_OPEN_ATTEMPTS)", got)
+	}
+}
+
+func TestStreamOpenNotRetried(t *testing.T) {
+	t.Run("business error", func(t *testing.T) {
+		backend := &flakyOpenBackend{listBackend: newListBackend(3, ""), failOpens: 1, openErr: status.Error(codes.InvalidArgument, "bad filter")}
+		router := newStreamRetryRouter(t, backend, "3")
+
+		if rec := doRequest(router, http.MethodGet, "/users/stream?limit=3", "", nil); rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", rec.Code)
+		}
+		if got := backend.opens.Load(); got != 1 {
+			t.Fatalf("stream opens = %d, want 1", got)
+		}
+	})
+
+	t.Run("after data started", func(t *testing.T) {
+		list := newListBackend(2, "")
+		list.err = status.Error(codes.Unavailable, "connection reset")
+		backend := &flakyOpenBackend{listBackend: list}
+		router := newStreamRetryRouter(t, backend, "3")
+
+		doRequest(router, http.MethodGet, "/users/stream?limit=3", "", nil)
+		if got := backend.opens.Load(); got != 1 {
+			t.Fatalf("stream opens = %d, want 1 (stream that already yielded data is never reopened)", got)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	pb "api-gateway/proto/user"
+	"api-gateway/redact"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// protobufStreamContentType adalah media type untuk mode length-delimited protobuf
+// Client minta mode ini lewat header "Accept: application/x-protobuf-stream"
+const protobufStreamContentType = "application/x-protobuf-stream"
+
+// wantsProtobufStream cek apakah client minta response protobuf frames
+func wantsProtobufStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), protobufStreamContentType)
+}
+
+// recvUsers adalah loop Recv() standar untuk Server Streaming RPC
+// onUser dipanggil untuk setiap user yang datang; return error dari onUser menghentikan loop
+func recvUsers(stream pb.UserService_ListUsersClient, onUser func(*pb.User) error) error {
+	return recvUserResponses(stream, func(resp *pb.UserResponse) error {
+		return onUser(resp.User)
+	})
+}
+
+// recvUserResponses sama seperti recvUsers, tapi onResp menerima message lengkap
+// (dipakai ListUsers untuk membaca next_page_token di message terakhir)
+func recvUserResponses(stream pb.UserService_ListUsersClient, onResp func(*pb.UserResponse) error) error {
+	for {
+		// stream.Recv() adalah blocking call
+		// Akan wait sampai message baru datang atau stream selesai
+		resp, err := stream.Recv()
+
+		// EOF = End of File = stream selesai (sukses)
+		if err == io.EOF {
+			log.Println("✅ Stream finished")
+			return nil
+		}
+
+		// Error lain = ada masalah
+		if err != nil {
+			return err
+		}
+
+		log.Printf("📦 Received user: %s", redact.Field("name", resp.User.Name))
+		if err := onResp(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// writeDelimited menulis 1 protobuf message sebagai frame:
+// [varint panjang message][bytes message hasil proto.Marshal]
+func writeDelimited(w io.Writer, msg proto.Message) error {
+	body, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	size := uint64(len(body))
+	frame := protowire.AppendVarint(make([]byte, 0, protowire.SizeVarint(size)+len(body)), size)
+	frame = append(frame, body...)
+	_, err = w.Write(frame)
+	return err
+}
+
+// streamProtobufFrames meneruskan setiap User dari gRPC stream sebagai frame,
+// flush per batch (lihat flushPolicy) supaya client bisa proses secara incremental
+func streamProtobufFrames(w http.ResponseWriter, stream pb.UserService_ListUsersClient, policy flushPolicy) {
+	w.Header().Set("Content-Type", protobufStreamContentType)
+	batch := newBatchFlusher(w, policy, nil)
+
+	count := 0
+	nextPageToken := ""
+	err := recvUserResponses(stream, func(resp *pb.UserResponse) error {
+		if resp.NextPageToken != "" {
+			nextPageToken = resp.NextPageToken
+		}
+		return batch.Do(func() error {
+			if err := writeDelimited(w, resp.User); err != nil {
+				return err
+			}
+			count++
+			return nil
+		})
+	})
+
+	// Batch terakhir yang belum penuh tetap harus terkirim
+	batch.Close()
+
+	if err != nil {
+		log.Printf("❌ Stream error: %v", err)
+		// Header sudah terkirim kalau minimal 1 frame sudah ditulis,
+		// jadi status code tidak bisa diubah lagi — client akan melihat stream terputus
+		if count == 0 {
+			writeGRPCError(w, err)
+		}
+		return
+	}
+
+	// Frame hanya berisi User, jadi token halaman berikutnya dikirim sebagai HTTP trailer
+	if nextPageToken != "" {
+		w.Header().Set(http.TrailerPrefix+"X-Next-Page-Token", nextPageToken)
+	}
+
+	log.Printf("✅ Total frames sent: %d", count)
+}
+
+// streamSSE meneruskan setiap User dari gRPC stream sebagai Server-Sent Event
+// (mode default /users/stream, bisa langsung dipakai EventSource di browser):
+//
+//	event: user   → data: {...user...}                         (1 per user, flush per event)
+//	event: done   → data: {"count": N, "nextPageToken": "..."} (stream selesai)
+//	event: error  → data: {"code": "...", "error": "..."}      (stream gagal setelah event pertama)
+//
+// Client disconnect membatalkan r.Context(), yang juga membatalkan stream gRPC ke backend
+func streamSSE(w http.ResponseWriter, stream pb.UserService_ListUsersClient, cancel context.CancelFunc) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Flush per event: tujuan SSE adalah client melihat setiap user begitu datang
+	batch := newBatchFlusher(w, flushPolicy{records: 1}, nil)
+	defer batch.Close()
+
+	count := 0
+	nextPageToken := ""
+	clientGone := false
+	err := recvUserResponses(stream, func(resp *pb.UserResponse) error {
+		if resp.NextPageToken != "" {
+			nextPageToken = resp.NextPageToken
+		}
+		if err := batch.Do(func() error { return writeSSE(w, "user", protoValue{msg: resp.User}) }); err != nil {
+			clientGone = true
+			return err
+		}
+		count++
+		return nil
+	})
+
+	if clientGone {
+		// Write gagal = client sudah pergi; hentikan stream ke backend sekarang juga
+		log.Printf("❌ Client gone while streaming users: %v", err)
+		cancel()
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Stream error: %v", err)
+		// Belum ada event terkirim → header belum terkirim, masih bisa balas status code biasa
+		if count == 0 {
+			writeGRPCError(w, err)
+			return
+		}
+		st := status.Convert(err)
+		batch.Do(func() error {
+			return writeSSE(w, "error", map[string]string{"code": st.Code().String(), "error": st.Message()})
+		})
+		return
+	}
+
+	batch.Do(func() error {
+		return writeSSE(w, "done", struct {
+			Count         int    `json:"count"`
+			NextPageToken string `json:"nextPageToken,omitempty"`
+		}{count, nextPageToken})
+	})
+
+	log.Printf("✅ Total events sent: %d", count)
+}
+
+/*
+📚 FORMAT LENGTH-DELIMITED PROTOBUF (application/x-protobuf-stream)
+
+Body response adalah deretan frame tanpa pemisah lain:
+
+	┌──────────────┬──────────────────────────┬──────────────┬─────
+	│ varint len N │ N bytes: user.User (pb)  │ varint len M │ ...
+	└──────────────┴──────────────────────────┴──────────────┴─────
+
+- Panjang di-encode sebagai unsigned varint (encoding yang sama dengan protobuf)
+- Setiap message adalah user.User (lihat proto/user/user.proto)
+- Stream selesai = body EOF (tidak ada frame penutup)
+
+Contoh parsing di Go (client):
+
+	reader := bufio.NewReader(resp.Body)
+	for {
+		user := &pb.User{}
+		err := protodelim.UnmarshalFrom(reader, user)
+		if err == io.EOF {
+			break
+		}
+		...
+	}
+
+Format ini sama dengan writeDelimitedTo() di Java dan
+google.protobuf.internal.encoder._VarintBytes di Python.
+*/
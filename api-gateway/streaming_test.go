@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+// listBackend adalah User Service palsu untuk ListUsers (server streaming):
+// mengirim users satu per satu, next_page_token ikut di message terakhir
+type listBackend struct {
+	pb.UnimplementedUserServiceServer
+	users         []*pb.User
+	nextPageToken string
+	err           error // Dikirim setelah semua users (nil = stream sukses)
+}
+
+func newListBackend(n int, nextPageToken string) *listBackend {
+	b := &listBackend{nextPageToken: nextPageToken}
+	for i := 1; i <= n; i++ {
+		b.users = append(b.users, &pb.User{
+			Id:    fmt.Sprintf("u%d", i),
+			Name:  fmt.Sprintf("User %d", i),
+			Email: fmt.Sprintf("user%d@example.com", i),
+			Age:   int32(20 + i),
+		})
+	}
+	return b
+}
+
+func (b *listBackend) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
+	for i, user := range b.users {
+		resp := &pb.UserResponse{User: user}
+		if i == len(b.users)-1 {
+			resp.NextPageToken = b.nextPageToken
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return b.err
+}
+
+// getStream melakukan GET sungguhan (httptest.Server) supaya flush & trailer ikut teruji
+func getStream(t *testing.T, url, accept string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// readFrames membaca body application/x-protobuf-stream seperti contoh client di streaming.go
+func readFrames(t *testing.T, body io.Reader) []*pb.User {
+	t.Helper()
+	reader := bufio.NewReader(body)
+	var users []*pb.User
+	for {
+		user := &pb.User{}
+		err := protodelim.UnmarshalFrom(reader, user)
+		if err == io.EOF {
+			return users
+		}
+		if err != nil {
+			t.Fatalf("read frame %d: %v", len(users), err)
+		}
+		users = append(users, user)
+	}
+}
+
+func TestProtobufStreamFramesRoundTrip(t *testing.T) {
+	backend := newListBackend(25, "next-token")
+	upstream := startUserService(t, backend)
+	cfg := testConfig(t, map[string]string{"STREAM_FLUSH_RECORDS": "10"})
+	srv := serveGateway(t, newTestGateway(t, cfg, upstream.addr))
+
+	for _, path := range []string{"/users/stream?limit=25", "/users?limit=25"} {
+		t.Run(path, func(t *testing.T) {
+			resp := getStream(t, srv.URL+path, protobufStreamContentType)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want 200", resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != protobufStreamContentType {
+				t.Fatalf("Content-Type = %q", got)
+			}
+
+			users := readFrames(t, resp.Body)
+			if len(users) != len(backend.users) {
+				t.Fatalf("frames = %d, want %d", len(users), len(backend.users))
+			}
+			for i, user := range users {
+				if !proto.Equal(user, backend.users[i]) {
+					t.Fatalf("frame %d = %v, want %v", i, user, backend.users[i])
+				}
+			}
+			// Trailer baru terbaca setelah body habis
+			if got := resp.Trailer.Get("X-Next-Page-Token"); got != "next-token" {
+				t.Fatalf("X-Next-Page-Token trailer = %q, want next-token", got)
+			}
+		})
+	}
+}
+
+func TestWriteDelimitedFraming(t *testing.T) {
+	user := &pb.User{Id: "u1", Name: "Alice"}
+	var buf bytes.Buffer
+	if err := writeDelimited(&buf, user); err != nil {
+		t.Fatalf("writeDelimited: %v", err)
+	}
+	body, _ := proto.Marshal(user)
+	// Message kecil (< 128 bytes) → prefix varint 1 byte
+	frame := buf.Bytes()
+	if len(frame) != 1+len(body) || int(frame[0]) != len(body) || !bytes.Equal(frame[1:], body) {
+		t.Fatalf("frame = %x, want 1-byte length %d + message", frame, len(body))
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"api-gateway/config"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// transportCredentials memilih credentials koneksi gateway → User Service
+//   - TLS_CA_FILE kosong → plaintext (hanya kalau INSECURE=true, divalidasi di config.Load)
+//   - TLS_CA_FILE di-set → TLS, server cert diverifikasi terhadap CA tersebut
+//   - TLS_CLIENT_CERT_FILE/KEY_FILE di-set → gateway juga mengirim client cert (mTLS)
+func transportCredentials(cfg *config.Config) (credentials.TransportCredentials, error) {
+	if cfg.TLSCAFile == "" {
+		return insecure.NewCredentials(), nil
+	}
+
+	pemData, err := os.ReadFile(cfg.TLSCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("read TLS_CA_FILE: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("no valid certificates in %s", cfg.TLSCAFile)
+	}
+
+	tlsCfg := &tls.Config{
+		RootCAs:    pool,
+		ServerName: cfg.TLSServerName, // Kosong = pakai host dari address
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if cfg.TLSClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSClientCertFile, cfg.TLSClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(tlsCfg), nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// testCA adalah CA sementara untuk menerbitkan server & client cert di test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue menerbitkan leaf cert untuk 127.0.0.1, return cert & key dalam format PEM
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startMutualTLSUserService menjalankan User Service palsu yang mewajibkan client cert dari ca
+func startMutualTLSUserService(t *testing.T, ca *testCA) *testBackend {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, "user-service", x509.ExtKeyUsageServerAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	})
+	return startUserService(t, &pb.UnimplementedUserServiceServer{}, grpc.Creds(creds))
+}
+
+func TestGatewayPresentsClientCert(t *testing.T) {
+	ca := newTestCA(t)
+	upstream := startMutualTLSUserService(t, ca)
+	dir := t.TempDir()
+	caFile := writeTestFile(t, dir, "ca.pem", ca.pem)
+
+	// Gateway dengan client cert dari CA yang dipercaya → handshake mTLS berhasil
+	certPEM, keyPEM := ca.issue(t, "api-gateway", x509.ExtKeyUsageClientAuth)
+	cfg := testConfig(t, map[string]string{
+		"TLS_CA_FILE":          caFile,
+		"TLS_CLIENT_CERT_FILE": writeTestFile(t, dir, "client.pem", certPEM),
+		"TLS_CLIENT_KEY_FILE":  writeTestFile(t, dir, "client-key.pem", keyPEM),
+	})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+	if rec := doRequest(router, http.MethodGet, "/health", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("/health with client cert = %d, want 200 (body: %s)", rec.Code, rec.Body)
+	}
+}
+
+func TestGatewayWithoutClientCertRejected(t *testing.T) {
+	ca := newTestCA(t)
+	upstream := startMutualTLSUserService(t, ca)
+
+	// TLS saja tanpa client cert → User Service menolak koneksi
+	cfg := testConfig(t, map[string]string{"TLS_CA_FILE": writeTestFile(t, t.TempDir(), "ca.pem", ca.pem)})
+	router := testRouter(t, newTestGateway(t, cfg, upstream.addr))
+	if rec := doRequest(router, http.MethodGet, "/health", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("/health without client cert = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Setup meng-configure global OpenTelemetry TracerProvider dan propagator (W3C trace-context)
+// - sampleRate: fraksi trace baru yang di-sample (0.0 - 1.0)
+// - otlpEndpoint: collector tujuan export span, kosong = span tidak di-export
+// Return function shutdown untuk flush span yang tersisa saat service berhenti
+func Setup(ctx context.Context, serviceName string, sampleRate float64, otlpEndpoint string) (func(context.Context) error, error) {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(NewSampler(sampleRate)),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+		)),
+	}
+
+	if otlpEndpoint != "" {
+		exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpointURL(otlpEndpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return tp.Shutdown, nil
+}
+
+// NewSampler membuat parent-based ratio sampler
+// Request dengan parent (traceparent dari upstream) selalu mengikuti keputusan upstream,
+// sedangkan trace baru (root span) hanya di-sample sebanyak sampleRate
+func NewSampler(sampleRate float64) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRate))
+}
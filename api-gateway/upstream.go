@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// serviceConfig membuat service config default untuk koneksi ke User Service
+// (dipasang lewat grpc.WithDefaultServiceConfig):
+//   - round_robin: RPC disebar ke SEMUA backend yang sehat (default gRPC pick_first
+//     mengirim semua RPC ke 1 backend saja); backend yang mati dilewati sampai pulih
+//   - retryPolicy untuk read yang aman diulang (GetUser, ListUsers): UNAVAILABLE /
+//     DEADLINE_EXCEEDED dicoba lagi maxRetries kali dengan exponential backoff
+//
+// Write seperti CreateUser sengaja TIDAK di-retry: request yang sebenarnya sudah sampai
+// tapi response-nya hilang bisa membuat user dobel. Stream (ListUsers) hanya di-retry
+// oleh gRPC selama belum ada message yang diterima client
+func serviceConfig(maxRetries int) string {
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodName struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type methodConfig struct {
+		Name        []methodName `json:"name"`
+		RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	cfg := map[string]interface{}{
+		"loadBalancingConfig": []map[string]interface{}{{"round_robin": struct{}{}}},
+	}
+	if maxRetries > 0 {
+		cfg["methodConfig"] = []methodConfig{{
+			Name: []methodName{
+				{Service: "user.UserService", Method: "GetUser"},
+				{Service: "user.UserService", Method: "ListUsers"},
+			},
+			RetryPolicy: &retryPolicy{
+				MaxAttempts:          maxRetries + 1, // Termasuk attempt pertama
+				InitialBackoff:       "0.1s",
+				MaxBackoff:           "1s",
+				BackoffMultiplier:    2,
+				RetryableStatusCodes: []string{"UNAVAILABLE", "DEADLINE_EXCEEDED"},
+			},
+		}}
+	}
+
+	out, _ := json.Marshal(cfg) // Semua tipe di atas pasti bisa di-marshal
+	return string(out)
+}
+
+// compressionCallOptions return default CallOption kompresi untuk GRPC_COMPRESSION
+// "identity" → tidak ada option (message dikirim apa adanya, server membalas identity juga)
+// "gzip" → request dikompres dan server membalas dengan gzip (lihat registrasi di user-service)
+func compressionCallOptions(name string) []grpc.CallOption {
+	if name == gzip.Name {
+		return []grpc.CallOption{grpc.UseCompressor(gzip.Name)}
+	}
+	return nil
+}
+
+// upstreamTarget menentukan target grpc.NewClient untuk daftar address User Service
+//   - 1 address → dipakai langsung (resolver dns: kalau hostname-nya resolve ke
+//     banyak IP, round_robin tetap menyebar ke semua IP)
+//   - >1 address → resolver manual berisi semua address (scheme "static"),
+//     didaftarkan ke ClientConn lewat DialOption yang dikembalikan
+//
+// Catatan: request yang sama bisa mendarat di instance berbeda, jadi semua instance
+// harus berbagi storage yang sama (in-memory store milik masing-masing proses)
+func upstreamTarget(addrs []string) (string, grpc.DialOption) {
+	if len(addrs) == 1 {
+		return addrs[0], grpc.EmptyDialOption{}
+	}
+
+	state := resolver.State{}
+	for _, addr := range addrs {
+		// ServerName per address: verifikasi TLS tetap memakai hostname backend masing-masing,
+		// bukan authority target "static:///user-service"
+		host, _, _ := net.SplitHostPort(addr)
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: addr, ServerName: host})
+	}
+	r := manual.NewBuilderWithScheme("static")
+	r.InitialState(state)
+
+	return "static:///user-service", grpc.WithResolvers(r)
+}
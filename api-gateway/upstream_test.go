@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRoundRobinAcrossUserServices(t *testing.T) {
+	first, second := &slowFirstBackend{}, &slowFirstBackend{}
+	upstreamA := startUserService(t, first)
+	upstreamB := startUserService(t, second)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstreamA.addr, upstreamB.addr))
+
+	const requests = 40
+	for i := 0; i < requests; i++ {
+		if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d (body: %s)", i, rec.Code, rec.Body)
+		}
+	}
+	a, b := first.calls.Load(), second.calls.Load()
+	if a+b != requests || a == 0 || b == 0 {
+		t.Fatalf("calls = %d / %d, want %d requests spread over both instances", a, b, requests)
+	}
+
+	// 1 instance mati → request berikutnya tetap dilayani instance yang masih hidup
+	upstreamA.server.Stop()
+	for i := 0; i < 10; i++ {
+		if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+			t.Fatalf("after one instance stopped, request %d: status = %d (body: %s)", i, rec.Code, rec.Body)
+		}
+	}
+	if got := second.calls.Load(); got <= b {
+		t.Fatalf("surviving instance calls = %d, want more than %d", got, b)
+	}
+}
+
+func TestUpstreamTargetSingleAddress(t *testing.T) {
+	if target, _ := upstreamTarget([]string{"user-service:50051"}); target != "user-service:50051" {
+		t.Fatalf("target = %q, want the address as is", target)
+	}
+}
+
+// failNTimesBackend: failures call pertama GetUser & CreateUser gagal Unavailable, setelahnya sukses
+type failNTimesBackend struct {
+	pb.UnimplementedUserServiceServer
+	failures    int32
+	getCalls    atomic.Int32
+	createCalls atomic.Int32
+}
+
+func (b *failNTimesBackend) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	if b.getCalls.Add(1) <= b.failures {
+		return nil, status.Error(codes.Unavailable, "transient failure")
+	}
+	return &pb.GetUserResponse{User: &pb.User{Id: req.Id}}, nil
+}
+
+func (b *failNTimesBackend) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	if b.createCalls.Add(1) <= b.failures {
+		return nil, status.Error(codes.Unavailable, "transient failure")
+	}
+	return &pb.CreateUserResponse{User: &pb.User{Id: "new"}, Success: true}, nil
+}
+
+func TestRetryGetUserUntilSuccess(t *testing.T) {
+	backend := &failNTimesBackend{failures: 2}
+	upstream := startUserService(t, backend)
+	// GRPC_MAX_RETRIES=2 → maksimal 3 attempt
+	router := testRouter(t, newTestGateway(t, testConfig(t, map[string]string{"GRPC_MAX_RETRIES": "2"}), upstream.addr))
+
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 after retries (body: %s)", rec.Code, rec.Body)
+	}
+	if got := backend.getCalls.Load(); got != 3 {
+		t.Fatalf("GetUser calls = %d, want 3 (2 failures + success)", got)
+	}
+}
+
+func TestRetryDisabledAndCreateNotRetried(t *testing.T) {
+	backend := &failNTimesBackend{failures: 1}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, map[string]string{"GRPC_MAX_RETRIES": "2"}), upstream.addr))
+
+	// CreateUser tidak idempotent: Unavailable langsung diteruskan, tidak diulang
+	rec := doRequest(router, http.MethodPost, "/users", `{"name":"Alice","email":"alice@example.com"}`, http.Header{"Content-Type": {"application/json"}})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("CreateUser status = %d, want 503 (body: %s)", rec.Code, rec.Body)
+	}
+	if got := backend.createCalls.Load(); got != 1 {
+		t.Fatalf("CreateUser calls = %d, want 1", got)
+	}
+
+	// GRPC_MAX_RETRIES=0 → GetUser juga tidak diulang
+	noRetry := &failNTimesBackend{failures: 1}
+	upstream = startUserService(t, noRetry)
+	router = testRouter(t, newTestGateway(t, testConfig(t, map[string]string{"GRPC_MAX_RETRIES": "0"}), upstream.addr))
+	if rec := doRequest(router, http.MethodGet, "/users/u1", "", nil); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("GetUser without retries: status = %d, want 503", rec.Code)
+	}
+	if got := noRetry.getCalls.Load(); got != 1 {
+		t.Fatalf("GetUser calls = %d, want 1", got)
+	}
+}
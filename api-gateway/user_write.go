@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+
+	pb "api-gateway/proto/user"
+	"api-gateway/redact"
+)
+
+// UpdateUserHandler menghandle PUT /users/{id} dengan body {"name", "email", "age", "expectedVersion"}
+// (route lama PUT /users/update: id di body)
+// Semua field ditimpa (bukan partial update), status & created_at tidak berubah
+// expectedVersion = "version" dari GET terakhir; user sudah diubah orang lain → 409 (stale update)
+func (gw *APIGateway) UpdateUserHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI HTTP METHOD
+	if r.Method != http.MethodPut {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE HTTP REQUEST BODY (JSON)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &pb.UpdateUserRequest{}
+	if err := decodeProtoJSON(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// id dari path; "id" di body opsional, tapi kalau diisi harus sama
+	if pathID := r.PathValue("id"); pathID != "" {
+		if req.Id != "" && req.Id != pathID {
+			http.Error(w, "id in body does not match id in path", http.StatusBadRequest)
+			return
+		}
+		req.Id = pathID
+	}
+	if req.Id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received UpdateUser request: %s (%s)", req.Id, redact.Field("email", req.Email))
+
+	// 3. CREATE CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD
+	resp, err := gw.userClient.UpdateUser(ctx, req)
+
+	// 5. ERROR HANDLING: gRPC status code → HTTP status code
+	if err != nil {
+		logGRPCError(r, err, "user_id", req.Id)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ User updated: %s", resp.User.Id)
+
+	// Versi lama tidak boleh tersaji lagi dari cache manapun
+	if gw.responses != nil {
+		gw.responses.Invalidate(resp.User.Id)
+	}
+	if gw.staleUsers != nil {
+		gw.staleUsers.Delete(resp.User.Id) // Versi lama milik principal lain
+		gw.staleUsers.Put(principalKey(r), resp.User)
+	}
+
+	// 6. RETURN HTTP RESPONSE (JSON)
+	writeProtoJSON(w, http.StatusOK, resp)
+}
+
+// SetUserRolesHandler menghandle PUT /users/{id}/roles {"roles": ["admin"]}
+// Role user diganti seluruhnya; yang memeriksa caller punya role admin adalah User Service
+// (interceptor.Authorize), gateway hanya meneruskan bearer token lewat withAuth
+func (gw *APIGateway) SetUserRolesHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. PARSE HTTP REQUEST BODY (JSON)
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &pb.SetUserRolesRequest{}
+	if err := decodeProtoJSON(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Id = r.PathValue("id")
+
+	log.Printf("📥 Received SetUserRoles request: %s %v", req.Id, req.Roles)
+
+	// 2. CREATE CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 3. CALL gRPC METHOD
+	resp, err := gw.userClient.SetUserRoles(ctx, req)
+	if err != nil {
+		logGRPCError(r, err, "user_id", req.Id)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ Roles updated: %s %v", resp.User.Id, resp.User.Roles)
+
+	if gw.responses != nil {
+		gw.responses.Invalidate(resp.User.Id)
+	}
+	if gw.staleUsers != nil {
+		gw.staleUsers.Delete(resp.User.Id) // Versi lama milik principal lain
+		gw.staleUsers.Put(principalKey(r), resp.User)
+	}
+
+	// 4. RETURN HTTP RESPONSE (JSON)
+	writeProtoJSON(w, http.StatusOK, resp)
+}
+
+// DeleteUserHandler menghandle DELETE /users/{id}
+// Idempotent: id yang sudah tidak ada tetap 200 dengan "deleted": false
+func (gw *APIGateway) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI HTTP METHOD
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. GET PATH PARAMETER
+	userId := r.PathValue("id")
+	if userId == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received DeleteUser request: %s", userId)
+
+	// 3. CREATE CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD
+	resp, err := gw.userClient.DeleteUser(ctx, &pb.DeleteUserRequest{Id: userId})
+
+	// 5. ERROR HANDLING
+	if err != nil {
+		logGRPCError(r, err, "user_id", userId)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ DeleteUser %s: %s", userId, resp.Message)
+
+	// User yang sudah dihapus tidak boleh tersaji lagi (dari response cache maupun fallback stale)
+	if gw.responses != nil {
+		gw.responses.Invalidate(userId)
+	}
+	if gw.staleUsers != nil {
+		gw.staleUsers.Delete(userId)
+	}
+
+	// 6. RETURN HTTP RESPONSE (JSON)
+	// Selalu 200 + body (bukan 204) supaya client bisa bedakan "deleted" vs "already deleted"
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":      userId,
+		"deleted": resp.Deleted,
+		"message": resp.Message,
+	})
+}
+
+// RestoreUserHandler menghandle POST /users/restore?id=xxx
+// Membatalkan soft delete; 404 kalau user tidak ada, 400 kalau user tidak sedang dihapus
+func (gw *APIGateway) RestoreUserHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI HTTP METHOD
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. GET QUERY PARAMETER
+	userId := r.URL.Query().Get("id")
+	if userId == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received RestoreUser request: %s", userId)
+
+	// 3. CREATE CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD
+	resp, err := gw.userClient.RestoreUser(ctx, &pb.RestoreUserRequest{Id: userId})
+	if err != nil {
+		logGRPCError(r, err, "user_id", userId)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ User restored: %s", userId)
+
+	// Entry lama di response cache (dari sebelum delete) dibuang supaya read berikutnya fresh
+	if gw.responses != nil {
+		gw.responses.Invalidate(userId)
+	}
+
+	// 5. RETURN HTTP RESPONSE
+	writeProtoJSON(w, http.StatusOK, resp)
+}
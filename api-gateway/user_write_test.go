@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+// deleteBackend meniru DeleteUser idempotent User Service: id yang sudah tidak ada → deleted=false
+type deleteBackend struct {
+	pb.UnimplementedUserServiceServer
+	mu    sync.Mutex
+	users map[string]bool
+}
+
+func (b *deleteBackend) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.users[req.Id] {
+		return &pb.DeleteUserResponse{Deleted: false, Message: "already deleted"}, nil
+	}
+	delete(b.users, req.Id)
+	return &pb.DeleteUserResponse{Deleted: true, Message: "User deleted successfully"}, nil
+}
+
+func TestDeleteUserTwiceReturns200(t *testing.T) {
+	upstream := startUserService(t, &deleteBackend{users: map[string]bool{"u1": true}})
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	for i, wantDeleted := range []bool{true, false} {
+		rec := doRequest(router, http.MethodDelete, "/users/u1", "", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("delete #%d: status = %d, want 200 (body: %s)", i+1, rec.Code, rec.Body)
+		}
+		var body struct {
+			ID      string `json:"id"`
+			Deleted bool   `json:"deleted"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body.ID != "u1" || body.Deleted != wantDeleted {
+			t.Fatalf("delete #%d: body = %+v, want deleted=%v", i+1, body, wantDeleted)
+		}
+	}
+}
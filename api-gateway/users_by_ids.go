@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+
+	pb "api-gateway/proto/user"
+)
+
+// GetUsersByIdsHandler menghandle GET /users/by-ids?ids=a,b,c
+// 1 RPC untuk semua id (bukan N x GetUser); id yang tidak ada dikembalikan di
+// "missingIds" dengan status 200, jadi client tetap bisa render yang ditemukan
+func (gw *APIGateway) GetUsersByIdsHandler(w http.ResponseWriter, r *http.Request) {
+	// 1. VALIDASI METHOD
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 2. PARSE QUERY PARAMETER
+	// Id duplikat dihitung sekali (sama dengan User Service), jadi batasnya konsisten di kedua sisi
+	ids := parseFieldList(r.URL.Query().Get("ids"))
+	if len(ids) == 0 {
+		http.Error(w, "ids parameter required", http.StatusBadRequest)
+		return
+	}
+	ids = uniqueIDs(ids)
+	if len(ids) > gw.cfg.GetUsersByIDsMax {
+		http.Error(w, fmt.Sprintf("too many ids: %d (max %d)", len(ids), gw.cfg.GetUsersByIDsMax), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("📥 Received GetUsersByIds request (%d ids)", len(ids))
+
+	// 3. CONTEXT dengan TIMEOUT
+	ctx, cancel := context.WithTimeout(withAuth(r.Context(), r), gw.cfg.RPCTimeout)
+	defer cancel()
+
+	// 4. CALL gRPC METHOD (Unary RPC)
+	resp, err := gw.userClient.GetUsersByIds(ctx, &pb.GetUsersByIdsRequest{Ids: ids})
+	if err != nil {
+		logGRPCError(r, err)
+		writeGRPCError(w, err)
+		return
+	}
+
+	log.Printf("✅ Found %d users, %d missing", len(resp.Users), len(resp.MissingIds))
+
+	// 5. RETURN COLLECTION (missingIds selalu array, bukan null)
+	missingIds := resp.MissingIds
+	if missingIds == nil {
+		missingIds = []string{}
+	}
+	writeCollectionExtra(w, "users", protoValues(resp.Users), buildPageMeta(len(resp.Users), len(ids), "", false),
+		map[string]interface{}{"missingIds": missingIds})
+}
+
+// uniqueIDs membuang id duplikat, urutan kemunculan pertama dipertahankan
+func uniqueIDs(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	pb "api-gateway/proto/user"
+)
+
+// byIDsBackend: hanya user di map yang ditemukan, sisanya masuk missing_ids
+type byIDsBackend struct {
+	pb.UnimplementedUserServiceServer
+	users map[string]*pb.User
+	calls atomic.Int32
+}
+
+func (b *byIDsBackend) GetUsersByIds(ctx context.Context, req *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error) {
+	b.calls.Add(1)
+	resp := &pb.GetUsersByIdsResponse{}
+	for _, id := range req.Ids {
+		if user, ok := b.users[id]; ok {
+			resp.Users = append(resp.Users, user)
+		} else {
+			resp.MissingIds = append(resp.MissingIds, id)
+		}
+	}
+	return resp, nil
+}
+
+func TestGetUsersByIdsEndpointMixedIds(t *testing.T) {
+	backend := &byIDsBackend{users: map[string]*pb.User{
+		"u1": {Id: "u1", Name: "Alice", Email: "alice@example.com"},
+		"u3": {Id: "u3", Name: "Carol", Email: "carol@example.com"},
+	}}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/by-ids?ids=u3,%20u2,u1", "", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 even with missing ids (body: %s)", rec.Code, rec.Body)
+	}
+	var body struct {
+		Users []struct {
+			ID string `json:"id"`
+		} `json:"users"`
+		MissingIds []string `json:"missingIds"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v (body: %s)", err, rec.Body)
+	}
+	var found []string
+	for _, user := range body.Users {
+		found = append(found, user.ID)
+	}
+	if want := []string{"u3", "u1"}; !reflect.DeepEqual(found, want) {
+		t.Fatalf("users = %v, want %v", found, want)
+	}
+	if want := []string{"u2"}; !reflect.DeepEqual(body.MissingIds, want) {
+		t.Fatalf("missingIds = %v, want %v", body.MissingIds, want)
+	}
+
+	if rec := doRequest(router, http.MethodGet, "/users/by-ids", "", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("without ids: HTTP %d, want 400", rec.Code)
+	}
+}
+
+// TestGetUsersByIdsRejectsOverLimit: id melebihi GET_USERS_BY_IDS_MAX ditolak tanpa RPC ke User Service
+func TestGetUsersByIdsRejectsOverLimit(t *testing.T) {
+	backend := &byIDsBackend{users: map[string]*pb.User{"a": {Id: "a"}}}
+	upstream := startUserService(t, backend)
+	router := testRouter(t, newTestGateway(t, testConfig(t, map[string]string{"GET_USERS_BY_IDS_MAX": "2"}), upstream.addr))
+
+	rec := doRequest(router, http.MethodGet, "/users/by-ids?ids=a,b,c", "", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400 (body: %s)", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "max 2") {
+		t.Fatalf("body = %q, want the limit stated", rec.Body)
+	}
+	if calls := backend.calls.Load(); calls != 0 {
+		t.Fatalf("upstream calls = %d, want 0", calls)
+	}
+
+	// Id duplikat dihitung sekali, sama dengan User Service
+	if rec := doRequest(router, http.MethodGet, "/users/by-ids?ids=a,b,a", "", nil); rec.Code != http.StatusOK {
+		t.Fatalf("duplicate ids within limit: status = %d (body: %s)", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	pb "api-gateway/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// versionBackend: UpdateUser dengan compare-and-swap version seperti User Service
+type versionBackend struct {
+	pb.UnimplementedUserServiceServer
+	mu      sync.Mutex
+	version int64
+}
+
+func (b *versionBackend) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if req.ExpectedVersion != b.version {
+		return nil, status.Errorf(codes.Aborted, "stale update: expected version %d, current version is %d", req.ExpectedVersion, b.version)
+	}
+	b.version++
+	return &pb.UpdateUserResponse{User: &pb.User{Id: req.Id, Name: req.Name, Email: req.Email, Version: b.version}}, nil
+}
+
+func TestUpdateUserStaleVersionIsConflict(t *testing.T) {
+	upstream := startUserService(t, &versionBackend{version: 1})
+	router := testRouter(t, newTestGateway(t, testConfig(t, nil), upstream.addr))
+	jsonHeader := http.Header{"Content-Type": {"application/json"}}
+	body := `{"name":"Alice","email":"alice@example.com","expectedVersion":1}`
+
+	if rec := doRequest(router, http.MethodPut, "/users/u1", body, jsonHeader); rec.Code != http.StatusOK {
+		t.Fatalf("first update: HTTP %d (body: %s)", rec.Code, rec.Body)
+	}
+	// Client kedua masih memegang version 1
+	if rec := doRequest(router, http.MethodPut, "/users/u1", body, jsonHeader); rec.Code != http.StatusConflict {
+		t.Fatalf("stale update: HTTP %d, want 409 (body: %s)", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadGRPCPort(t *testing.T) {
+	t.Setenv("INSECURE", "true")
+
+	t.Setenv("GRPC_PORT", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GRPCPort != 50051 {
+		t.Fatalf("default GRPCPort = %d, want 50051", cfg.GRPCPort)
+	}
+
+	t.Setenv("GRPC_PORT", "6000")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GRPCPort != 6000 {
+		t.Fatalf("GRPCPort = %d, want 6000", cfg.GRPCPort)
+	}
+
+	for _, raw := range []string{"grpc", "-1", "0", "65536"} {
+		t.Setenv("GRPC_PORT", raw)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "GRPC_PORT") {
+			t.Fatalf("GRPC_PORT=%q: err = %v, want error naming GRPC_PORT", raw, err)
+		}
+	}
+}
+
+func TestLoadChaosDisabledByDefault(t *testing.T) {
+	t.Setenv("INSECURE", "true")
+	for _, key := range []string{"CHAOS_LATENCY_MS", "CHAOS_LATENCY_RATE", "CHAOS_ERROR_RATE", "CHAOS_ERROR_CODE"} {
+		t.Setenv(key, "")
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	// main hanya memasang interceptor Chaos kalau salah satu dari keduanya > 0
+	if cfg.ChaosLatency != 0 || cfg.ChaosErrorRate != 0 {
+		t.Fatalf("default chaos = latency %s, error rate %v; want disabled", cfg.ChaosLatency, cfg.ChaosErrorRate)
+	}
+
+	t.Setenv("CHAOS_LATENCY_MS", "250")
+	t.Setenv("CHAOS_ERROR_RATE", "0.5")
+	t.Setenv("CHAOS_ERROR_CODE", "deadline_exceeded")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.ChaosLatency != 250*time.Millisecond || cfg.ChaosErrorRate != 0.5 || cfg.ChaosErrorCode != "deadline_exceeded" {
+		t.Fatalf("chaos config = %s / %v / %q", cfg.ChaosLatency, cfg.ChaosErrorRate, cfg.ChaosErrorCode)
+	}
+
+	for _, raw := range []string{"-0.1", "1.5", "half"} {
+		t.Setenv("CHAOS_ERROR_RATE", raw)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "CHAOS_ERROR_RATE") {
+			t.Fatalf("CHAOS_ERROR_RATE=%q: err = %v, want error naming CHAOS_ERROR_RATE", raw, err)
+		}
+	}
+}
+
+func TestLoadGetUsersByIDsMax(t *testing.T) {
+	t.Setenv("INSECURE", "true")
+
+	t.Setenv("GET_USERS_BY_IDS_MAX", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GetUsersByIDsMax != 100 {
+		t.Fatalf("default GetUsersByIDsMax = %d, want 100", cfg.GetUsersByIDsMax)
+	}
+
+	t.Setenv("GET_USERS_BY_IDS_MAX", "250")
+	if cfg, err = Load(); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.GetUsersByIDsMax != 250 {
+		t.Fatalf("GetUsersByIDsMax = %d, want 250", cfg.GetUsersByIDsMax)
+	}
+
+	for _, raw := range []string{"0", "-5", "many"} {
+		t.Setenv("GET_USERS_BY_IDS_MAX", raw)
+		if _, err := Load(); err == nil || !strings.Contains(err.Error(), "GET_USERS_BY_IDS_MAX") {
+			t.Fatalf("GET_USERS_BY_IDS_MAX=%q: err = %v, want error naming GET_USERS_BY_IDS_MAX", raw, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Publisher (event sink) mengirim domain event user ke sistem lain (event-driven architecture)
+// Dipanggil UserServer SETELAH mutasi berhasil, di bawah lock server, jadi implementasi
+// wajib best-effort & non-blocking: gagal publish tidak boleh menggagalkan RPC
+type Publisher interface {
+	Publish(eventType pb.UserEventType, user *pb.User)
+	Close() error // Flush event yang masih antre, dipanggil saat shutdown
+}
+
+// Noop tidak mengirim apa-apa (default kalau NATS_URL & KAFKA_BROKERS tidak di-set)
+type Noop struct{}
+
+func (Noop) Publish(pb.UserEventType, *pb.User) {}
+func (Noop) Close() error                       { return nil }
+
+// Multi meneruskan setiap event ke semua publisher (contoh: NATS dan Kafka sekaligus)
+type Multi []Publisher
+
+func (m Multi) Publish(eventType pb.UserEventType, user *pb.User) {
+	for _, p := range m {
+		p.Publish(eventType, user)
+	}
+}
+
+func (m Multi) Close() error {
+	var errs []error
+	for _, p := range m {
+		errs = append(errs, p.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Event adalah payload JSON yang dikirim ke broker
+//
+//	{"type": "created", "user": {...}, "timestamp": "2024-01-01T00:00:00Z"}
+//
+// user ditulis dengan proto JSON mapping (sama dengan response gateway)
+type Event struct {
+	Type      string          `json:"type"`
+	User      json.RawMessage `json:"user"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// TypeName mengubah USER_EVENT_TYPE_CREATED menjadi "created" (dipakai di payload & subject)
+func TypeName(eventType pb.UserEventType) string {
+	return strings.ToLower(strings.TrimPrefix(eventType.String(), "USER_EVENT_TYPE_"))
+}
+
+// Marshal membuat payload JSON event
+func Marshal(eventType pb.UserEventType, user *pb.User, now time.Time) ([]byte, error) {
+	userJSON, err := protojson.Marshal(user)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Event{
+		Type:      TypeName(eventType),
+		User:      userJSON,
+		Timestamp: now.UTC(),
+	})
+}
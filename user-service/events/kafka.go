@@ -0,0 +1,64 @@
+package events
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher menulis event ke 1 topic Kafka, dengan key = user id
+// Semua event 1 user masuk partition yang sama, jadi urutannya terjaga untuk consumer
+// yang membangun read model (created → updated → deleted)
+type KafkaPublisher struct {
+	writer kafkaWriter
+}
+
+// kafkaWriter adalah bagian *kafka.Writer yang dipakai KafkaPublisher (diganti fake di test)
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// NewKafkaPublisher membuat writer async ke brokers/topic
+// Async: WriteMessages tidak pernah blocking, error pengiriman hanya dicatat di log
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{writer: &kafka.Writer{
+		Addr:  kafka.TCP(brokers...),
+		Topic: topic,
+		// Murmur2 = partitioner default client Java, jadi key yang sama berakhir di
+		// partition yang sama dengan producer lain (default kafka-go adalah round robin)
+		Balancer:     &kafka.Murmur2Balancer{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond, // Default 1s terlalu lama untuk event feed
+		Async:        true,
+		Completion: func(messages []kafka.Message, err error) {
+			if err != nil {
+				log.Printf("⚠️  Failed to write %d events to Kafka topic %s: %v", len(messages), topic, err)
+			}
+		},
+	}}
+}
+
+// Publish menambahkan event ke batch writer tanpa menunggu broker
+func (p *KafkaPublisher) Publish(eventType pb.UserEventType, user *pb.User) {
+	data, err := Marshal(eventType, user, time.Now())
+	if err != nil {
+		log.Printf("⚠️  Failed to encode %s event: %v", TypeName(eventType), err)
+		return
+	}
+	// Context tidak dipakai di mode async (tidak ada yang ditunggu)
+	p.writer.WriteMessages(context.Background(), kafka.Message{
+		Key:     []byte(user.Id),
+		Value:   data,
+		Headers: []kafka.Header{{Key: "type", Value: []byte(TypeName(eventType))}},
+	})
+}
+
+// Close mengirim batch yang tersisa lalu menutup koneksi ke broker
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
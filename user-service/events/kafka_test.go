@@ -0,0 +1,84 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	pb "user-service/proto/user"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// fakeKafkaWriter mencatat message yang ditulis, tanpa broker
+type fakeKafkaWriter struct {
+	mu       sync.Mutex
+	messages []kafka.Message
+	closed   bool
+}
+
+func (w *fakeKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeKafkaWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func TestKafkaPublisherKeyAndValue(t *testing.T) {
+	writer := &fakeKafkaWriter{}
+	p := &KafkaPublisher{writer: writer}
+
+	alice := &pb.User{Id: "u1", Name: "Alice", Email: "alice@example.com"}
+	bob := &pb.User{Id: "u2", Name: "Bob", Email: "bob@example.com"}
+	p.Publish(pb.UserEventType_USER_EVENT_TYPE_CREATED, alice)
+	p.Publish(pb.UserEventType_USER_EVENT_TYPE_CREATED, bob)
+	p.Publish(pb.UserEventType_USER_EVENT_TYPE_UPDATED, alice)
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !writer.closed {
+		t.Fatal("Close did not close the writer")
+	}
+
+	want := []struct{ key, eventType string }{
+		{"u1", "created"},
+		{"u2", "created"},
+		{"u1", "updated"},
+	}
+	if len(writer.messages) != len(want) {
+		t.Fatalf("wrote %d messages, want %d", len(writer.messages), len(want))
+	}
+	for i, msg := range writer.messages {
+		// Key = user id → semua event 1 user masuk partition yang sama
+		if string(msg.Key) != want[i].key {
+			t.Fatalf("message %d key = %q, want %q", i, msg.Key, want[i].key)
+		}
+		if len(msg.Headers) != 1 || msg.Headers[0].Key != "type" || string(msg.Headers[0].Value) != want[i].eventType {
+			t.Fatalf("message %d headers = %v, want type=%s", i, msg.Headers, want[i].eventType)
+		}
+
+		var event Event
+		if err := json.Unmarshal(msg.Value, &event); err != nil {
+			t.Fatalf("message %d value: %v (%s)", i, err, msg.Value)
+		}
+		if event.Type != want[i].eventType || event.Timestamp.IsZero() {
+			t.Fatalf("message %d event = %+v", i, event)
+		}
+		var user pb.User
+		if err := protojson.Unmarshal(event.User, &user); err != nil {
+			t.Fatalf("message %d user: %v", i, err)
+		}
+		if user.Id != want[i].key {
+			t.Fatalf("message %d user id = %q, want %q", i, user.Id, want[i].key)
+		}
+	}
+}
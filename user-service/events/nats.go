@@ -0,0 +1,108 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsQueueSize adalah jumlah event yang boleh antre menunggu dikirim ke NATS
+// Kalau penuh (NATS lambat / putus lama), event baru dibuang dan dicatat di log
+const natsQueueSize = 1024
+
+type natsMessage struct {
+	subject string
+	data    []byte
+}
+
+// NATSPublisher mengirim event ke subject "<prefix>.<type>" (contoh: users.created)
+// Publish hanya memasukkan event ke antrean; pengiriman ke NATS dilakukan 1 goroutine,
+// jadi RPC tidak pernah menunggu broker
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+
+	mu     sync.RWMutex // Menjaga queue dari Publish setelah Close
+	closed bool
+	queue  chan natsMessage
+	done   chan struct{}
+}
+
+// NewNATSPublisher connect ke NATS di url
+// Koneksi awal yang gagal tidak membuat service gagal start: client NATS
+// terus mencoba reconnect di background, event selama putus ikut antre di buffer client
+func NewNATSPublisher(url, prefix string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url,
+		nats.Name("user-service"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			log.Printf("⚠️  NATS disconnected: %v", err)
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			log.Printf("📡 NATS reconnected to %s", c.ConnectedUrl())
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &NATSPublisher{
+		conn:   conn,
+		prefix: prefix,
+		queue:  make(chan natsMessage, natsQueueSize),
+		done:   make(chan struct{}),
+	}
+	go p.run()
+	return p, nil
+}
+
+// run mengirim event dari antrean sampai queue ditutup Close
+func (p *NATSPublisher) run() {
+	defer close(p.done)
+	for msg := range p.queue {
+		if err := p.conn.Publish(msg.subject, msg.data); err != nil {
+			log.Printf("⚠️  Failed to publish event to %s: %v", msg.subject, err)
+		}
+	}
+}
+
+// Publish memasukkan event ke antrean tanpa blocking
+func (p *NATSPublisher) Publish(eventType pb.UserEventType, user *pb.User) {
+	data, err := Marshal(eventType, user, time.Now())
+	if err != nil {
+		log.Printf("⚠️  Failed to encode %s event: %v", TypeName(eventType), err)
+		return
+	}
+	msg := natsMessage{subject: p.prefix + "." + TypeName(eventType), data: data}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return
+	}
+	select {
+	case p.queue <- msg:
+	default:
+		log.Printf("⚠️  Event queue full, dropping event for %s", msg.subject)
+	}
+}
+
+// Close mengirim sisa antrean lalu menutup koneksi (Drain = flush buffer client NATS)
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	<-p.done
+	return p.conn.Drain()
+}
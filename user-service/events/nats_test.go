@@ -0,0 +1,102 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// startNATS menjalankan NATS server embedded di port acak, return URL client
+func startNATS(t *testing.T) string {
+	t.Helper()
+	ns, err := natsserver.NewServer(&natsserver.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("nats server: %v", err)
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+	t.Cleanup(ns.Shutdown)
+	return ns.ClientURL()
+}
+
+func TestNATSPublisherEventPayload(t *testing.T) {
+	url := startNATS(t)
+
+	sub, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("connect subscriber: %v", err)
+	}
+	defer sub.Close()
+	msgs := make(chan *nats.Msg, 8)
+	if _, err := sub.ChanSubscribe("users.>", msgs); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if err := sub.Flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+
+	p, err := NewNATSPublisher(url, "users")
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+	user := &pb.User{Id: "u1", Name: "Alice", Email: "alice@example.com", Age: 30, Version: 2}
+	before := time.Now().UTC()
+	p.Publish(pb.UserEventType_USER_EVENT_TYPE_CREATED, user)
+	p.Publish(pb.UserEventType_USER_EVENT_TYPE_DELETED, user)
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for _, wantType := range []string{"created", "deleted"} {
+		var msg *nats.Msg
+		select {
+		case msg = <-msgs:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %s event received", wantType)
+		}
+		if want := "users." + wantType; msg.Subject != want {
+			t.Fatalf("subject = %q, want %q", msg.Subject, want)
+		}
+
+		var event Event
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			t.Fatalf("decode payload: %v (%s)", err, msg.Data)
+		}
+		if event.Type != wantType {
+			t.Fatalf("type = %q, want %q", event.Type, wantType)
+		}
+		if event.Timestamp.Before(before.Truncate(time.Second)) || event.Timestamp.Location() != time.UTC {
+			t.Fatalf("timestamp = %v, want UTC time after %v", event.Timestamp, before)
+		}
+		var got pb.User
+		if err := protojson.Unmarshal(event.User, &got); err != nil {
+			t.Fatalf("decode user: %v (%s)", err, event.User)
+		}
+		if got.Id != "u1" || got.Email != "alice@example.com" || got.Version != 2 {
+			t.Fatalf("user = %v", &got)
+		}
+	}
+}
+
+func TestNATSPublisherPublishAfterCloseIsNoop(t *testing.T) {
+	p, err := NewNATSPublisher(startNATS(t), "users")
+	if err != nil {
+		t.Fatalf("NewNATSPublisher: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// Tidak panic (queue sudah ditutup) dan Close kedua tetap aman
+	p.Publish(pb.UserEventType_USER_EVENT_TYPE_CREATED, &pb.User{Id: "u1"})
+	if err := p.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+}
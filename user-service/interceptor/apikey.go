@@ -0,0 +1,45 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKey membuat interceptor (unary + stream) yang mewajibkan metadata "x-api-key"
+// berisi salah satu dari keys (AUTH_MODE=apikey, alternatif yang lebih ringan dari JWT
+// untuk client service-to-service). Key tidak ada / tidak dikenal → Unauthenticated.
+// Method di skipMethods (contoh: health check) tidak dicek
+//
+// API key tidak membawa identitas user maupun role: setiap key yang valid dipercaya penuh,
+// jadi interceptor Authorize (berbasis claims JWT) tidak dipakai di mode ini
+func APIKey(keys []string, skipMethods []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	// Dibandingkan dalam bentuk hash: panjang sama, jadi ConstantTimeCompare tidak bocor lewat panjang key
+	hashes := make([][32]byte, len(keys))
+	for i, key := range keys {
+		hashes[i] = sha256.Sum256([]byte(key))
+	}
+
+	return authInterceptors(skipMethods, func(ctx context.Context) (context.Context, error) {
+		values := metadata.ValueFromIncomingContext(ctx, "x-api-key")
+		if len(values) == 0 || values[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing x-api-key metadata")
+		}
+
+		// Semua key selalu dicek (tanpa break), supaya waktu tidak bergantung posisi key yang cocok
+		presented := sha256.Sum256([]byte(values[0]))
+		match := 0
+		for _, h := range hashes {
+			match |= subtle.ConstantTimeCompare(presented[:], h[:])
+		}
+		if match != 1 {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		return ctx, nil
+	})
+}
@@ -0,0 +1,88 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testAPIKeys = []string{"first-api-key-0123456789", "second-api-key-0123456789"}
+
+// apiKeyCtx membuat incoming context dengan metadata x-api-key (kosong = tanpa metadata)
+func apiKeyCtx(key string) context.Context {
+	if key == "" {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", key))
+}
+
+func TestAPIKeyValid(t *testing.T) {
+	unary, stream := APIKey(testAPIKeys, nil)
+
+	// Semua key di set diterima, bukan hanya yang pertama
+	for _, key := range testAPIKeys {
+		resp, err := unary(apiKeyCtx(key), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler)
+		if err != nil || resp != "ok" {
+			t.Fatalf("unary with key %q: resp = %v, err = %v", key, resp, err)
+		}
+
+		called := false
+		err = stream(nil, &contextStream{ctx: apiKeyCtx(key)}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				return nil
+			})
+		if err != nil || !called {
+			t.Fatalf("stream with key %q: err = %v, handler called = %v", key, err, called)
+		}
+	}
+}
+
+func TestAPIKeyRejectsMissingAndInvalid(t *testing.T) {
+	unary, stream := APIKey(testAPIKeys, nil)
+
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{"missing", ""},
+		{"unknown key", "unknown-api-key-0123456789"},
+		{"prefix of valid key", testAPIKeys[0][:8]},
+		{"bearer token instead of key", "Bearer " + testAPIKeys[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := unary(apiKeyCtx(tt.key), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					called = true
+					return "ok", nil
+				})
+			if status.Code(err) != codes.Unauthenticated || called {
+				t.Fatalf("unary: err = %v, handler called = %v; want Unauthenticated without calling the handler", err, called)
+			}
+
+			err = stream(nil, &contextStream{ctx: apiKeyCtx(tt.key)}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					called = true
+					return nil
+				})
+			if status.Code(err) != codes.Unauthenticated || called {
+				t.Fatalf("stream: err = %v, handler called = %v; want Unauthenticated", err, called)
+			}
+		})
+	}
+}
+
+func TestAPIKeySkipMethods(t *testing.T) {
+	unary, _ := APIKey(testAPIKeys, []string{healthCheckMethod})
+
+	resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: healthCheckMethod}, okHandler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("skipped method without key: resp = %v, err = %v", resp, err)
+	}
+}
@@ -0,0 +1,158 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Claims adalah isi JWT yang sudah diverifikasi, dibaca handler lewat ClaimsFromContext
+type Claims struct {
+	UserID    string   `json:"sub"`   // Subject = id user yang memanggil
+	Roles     []string `json:"roles"` // Contoh: ["admin"]
+	ExpiresAt int64    `json:"exp"`   // Unix seconds, 0 = tidak pernah expired
+}
+
+// HasRole cek apakah claims punya role tertentu
+func (c Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type claimsKey struct{}
+
+// ClaimsFromContext mengambil claims JWT (ok = false kalau method di-skip dari auth)
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(Claims)
+	return c, ok
+}
+
+// errInvalidToken sengaja generik: client tidak perlu tahu bagian mana dari token yang salah
+var errInvalidToken = errors.New("invalid token")
+
+// verifyJWT memverifikasi token HS256 (header.payload.signature) dengan secret
+// dan return claims-nya kalau signature cocok dan token belum expired
+func verifyJWT(token string, secret []byte, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, errInvalidToken
+	}
+
+	// Algoritma dicek eksplisit supaya token "alg: none" tidak lolos
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return Claims{}, errInvalidToken
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac.Sum(nil)) {
+		return Claims{}, errInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return Claims{}, errInvalidToken
+	}
+	if claims.ExpiresAt != 0 && now.Unix() >= claims.ExpiresAt {
+		return Claims{}, errors.New("token expired")
+	}
+	return claims, nil
+}
+
+// SignJWT membuat token HS256 untuk claims (kebalikan verifyJWT), dipakai RPC Authenticate
+func SignJWT(claims Claims, secret []byte) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// decodeSegment decode 1 bagian JWT (base64url tanpa padding) lalu unmarshal JSON-nya
+func decodeSegment(seg string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Auth membuat interceptor (unary + stream) yang mewajibkan bearer token di metadata "authorization"
+// Token divalidasi sebagai JWT HS256 dengan secret; token tidak ada / tidak valid / expired
+// ditolak dengan Unauthenticated. Method di skipMethods (contoh: health check) tidak dicek
+func Auth(secret []byte, skipMethods []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	return authInterceptors(skipMethods, func(ctx context.Context) (context.Context, error) {
+		values := metadata.ValueFromIncomingContext(ctx, "authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing authorization metadata")
+		}
+		token, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok || token == "" {
+			return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+		}
+		claims, err := verifyJWT(token, secret, time.Now())
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return context.WithValue(ctx, claimsKey{}, claims), nil
+	})
+}
+
+// authInterceptors membungkus fungsi authenticate (Auth, APIKey) menjadi interceptor unary + stream
+// authenticate return context baru (boleh berisi identitas caller) atau error gRPC status
+func authInterceptors(skipMethods []string, authenticate func(ctx context.Context) (context.Context, error)) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	skip := make(map[string]bool, len(skipMethods))
+	for _, m := range skipMethods {
+		skip[m] = true
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if skip[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if skip[info.FullMethod] {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+
+	return unary, stream
+}
@@ -0,0 +1,110 @@
+package interceptor
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testJWTSecret = []byte("test-jwt-secret")
+
+const healthCheckMethod = "/grpc.health.v1.Health/Check"
+
+func signTestJWT(t *testing.T, claims Claims, secret []byte) string {
+	t.Helper()
+	token, err := SignJWT(claims, secret)
+	if err != nil {
+		t.Fatalf("SignJWT: %v", err)
+	}
+	return token
+}
+
+// bearerCtx membuat incoming context dengan metadata authorization (kosong = tanpa header)
+func bearerCtx(authorization string) context.Context {
+	if authorization == "" {
+		return context.Background()
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", authorization))
+}
+
+func TestAuthValidToken(t *testing.T) {
+	unary, _ := Auth(testJWTSecret, nil)
+	token := signTestJWT(t, Claims{UserID: "u1", Roles: []string{"admin"}, ExpiresAt: time.Now().Add(time.Hour).Unix()}, testJWTSecret)
+
+	var claims Claims
+	var ok bool
+	_, err := unary(bearerCtx("Bearer "+token), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			claims, ok = ClaimsFromContext(ctx)
+			return "ok", nil
+		})
+	if err != nil {
+		t.Fatalf("valid token rejected: %v", err)
+	}
+	if !ok || claims.UserID != "u1" || !claims.HasRole("admin") {
+		t.Fatalf("claims in context = %+v (ok %v), want u1 with admin role", claims, ok)
+	}
+}
+
+func TestAuthRejectsInvalidTokens(t *testing.T) {
+	unary, stream := Auth(testJWTSecret, nil)
+	expired := signTestJWT(t, Claims{UserID: "u1", ExpiresAt: time.Now().Add(-time.Minute).Unix()}, testJWTSecret)
+	wrongSecret := signTestJWT(t, Claims{UserID: "u1"}, []byte("other-secret"))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	noneToken := noneHeader + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"u1"}`)) + "."
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"missing header", ""},
+		{"expired", "Bearer " + expired},
+		{"wrong secret", "Bearer " + wrongSecret},
+		{"alg none", "Bearer " + noneToken},
+		{"not bearer", "Basic dXNlcjpwYXNz"},
+		{"malformed", "Bearer not-a-jwt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := unary(bearerCtx(tt.authorization), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+				func(ctx context.Context, req interface{}) (interface{}, error) {
+					called = true
+					return "ok", nil
+				})
+			if status.Code(err) != codes.Unauthenticated || called {
+				t.Fatalf("unary: err = %v, handler called = %v; want Unauthenticated without calling the handler", err, called)
+			}
+
+			err = stream(nil, &contextStream{ctx: bearerCtx(tt.authorization)}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+				func(srv interface{}, ss grpc.ServerStream) error {
+					called = true
+					return nil
+				})
+			if status.Code(err) != codes.Unauthenticated || called {
+				t.Fatalf("stream: err = %v, handler called = %v; want Unauthenticated", err, called)
+			}
+		})
+	}
+
+	// Pesan expired dibedakan supaya client tahu harus login ulang
+	_, err := unary(bearerCtx("Bearer "+expired), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler)
+	if msg := status.Convert(err).Message(); msg != "token expired" {
+		t.Fatalf("expired token message = %q, want \"token expired\"", msg)
+	}
+}
+
+func TestAuthSkipMethods(t *testing.T) {
+	unary, _ := Auth(testJWTSecret, []string{healthCheckMethod})
+
+	resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: healthCheckMethod}, okHandler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("skipped method without token: resp = %v, err = %v", resp, err)
+	}
+}
@@ -0,0 +1,50 @@
+package interceptor
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Authorize membuat interceptor (unary + stream) yang mengecek role caller (Claims.Roles dari JWT)
+// terhadap policy per method: full method name → role yang diizinkan (cukup punya salah satu)
+// Method yang tidak ada di policy boleh dipanggil semua user yang lolos Auth
+//
+// Wajib dipasang SETELAH Auth. Method di policy yang tidak membawa claims (misal ikut
+// AUTH_SKIP_METHODS) selalu ditolak: policy tidak boleh bisa dilewati lewat konfigurasi skip
+func Authorize(policy map[string][]string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authorize := func(ctx context.Context, method string) error {
+		roles, restricted := policy[method]
+		if !restricted {
+			return nil
+		}
+		claims, ok := ClaimsFromContext(ctx)
+		if ok {
+			for _, role := range roles {
+				if claims.HasRole(role) {
+					return nil
+				}
+			}
+		}
+		return status.Errorf(codes.PermissionDenied, "%s requires role %s", method, strings.Join(roles, " or "))
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
@@ -0,0 +1,71 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const deleteUserMethod = "/user.UserService/DeleteUser"
+
+var testPolicy = map[string][]string{
+	deleteUserMethod: {"admin"},
+}
+
+// callWithRoles menjalankan Auth lalu Authorize (urutan sama dengan main) untuk caller dengan roles
+func callWithRoles(t *testing.T, method string, roles []string) error {
+	t.Helper()
+	authUnary, _ := Auth(testJWTSecret, nil)
+	authzUnary, _ := Authorize(testPolicy)
+	token := signTestJWT(t, Claims{UserID: "u1", Roles: roles, ExpiresAt: time.Now().Add(time.Hour).Unix()}, testJWTSecret)
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	_, err := authUnary(bearerCtx("Bearer "+token), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return authzUnary(ctx, req, info, okHandler)
+	})
+	return err
+}
+
+func TestAuthorizeAdminAllowedDeleteUser(t *testing.T) {
+	if err := callWithRoles(t, deleteUserMethod, []string{"admin"}); err != nil {
+		t.Fatalf("admin DeleteUser: %v", err)
+	}
+	if err := callWithRoles(t, deleteUserMethod, []string{"viewer", "admin"}); err != nil {
+		t.Fatalf("admin among other roles: %v", err)
+	}
+}
+
+func TestAuthorizeNonAdminDeniedDeleteUser(t *testing.T) {
+	for _, roles := range [][]string{nil, {"viewer"}, {"Admin"}} {
+		err := callWithRoles(t, deleteUserMethod, roles)
+		if status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("roles %v: err = %v, want PermissionDenied", roles, err)
+		}
+	}
+}
+
+func TestAuthorizeUnrestrictedMethod(t *testing.T) {
+	// Method yang tidak ada di policy: cukup lolos Auth
+	if err := callWithRoles(t, getUserMethod, nil); err != nil {
+		t.Fatalf("GetUser without roles: %v", err)
+	}
+}
+
+func TestAuthorizeWithoutClaimsDenied(t *testing.T) {
+	// Method di policy yang melewati Auth (misal ada di AUTH_SKIP_METHODS) tetap ditolak
+	unary, stream := Authorize(testPolicy)
+
+	_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: deleteUserMethod}, okHandler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("unary without claims: err = %v, want PermissionDenied", err)
+	}
+	err = stream(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: deleteUserMethod},
+		func(srv interface{}, ss grpc.ServerStream) error { return nil })
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("stream without claims: err = %v, want PermissionDenied", err)
+	}
+}
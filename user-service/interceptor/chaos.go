@@ -0,0 +1,94 @@
+package interceptor
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ChaosConfig adalah konfigurasi interceptor Chaos
+type ChaosConfig struct {
+	Latency     time.Duration // Delay buatan sebelum handler jalan (0 = tanpa delay)
+	LatencyRate float64       // Fraksi request yang diberi delay (0.0 - 1.0)
+	ErrorRate   float64       // Fraksi request yang langsung gagal (0.0 - 1.0)
+	ErrorCode   string        // Code error buatan, nama ("UNAVAILABLE", "unavailable") atau angka ("14")
+	SkipMethods []string      // Method yang tidak pernah diganggu (contoh: health check)
+}
+
+// Chaos membuat interceptor (unary + stream) untuk menguji retry/timeout client:
+// sebagian request diperlambat (Latency) dan/atau langsung gagal dengan ErrorCode,
+// tanpa handler sempat jalan (jadi write yang "gagal" memang tidak pernah terjadi)
+//
+// Delay ikut menghormati deadline client: request yang deadline-nya habis selama delay
+// selesai dengan DeadlineExceeded, sama seperti server yang benar-benar lambat
+// Hanya untuk testing; main hanya memasangnya kalau CHAOS_* diisi
+func Chaos(cfg ChaosConfig) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor, error) {
+	code, err := parseCode(cfg.ErrorCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if code == codes.OK {
+		return nil, nil, fmt.Errorf("error code must not be OK")
+	}
+	skip := make(map[string]bool, len(cfg.SkipMethods))
+	for _, m := range cfg.SkipMethods {
+		skip[m] = true
+	}
+
+	// rand.Float64() selalu < 1.0, jadi rate 1.0 = selalu dan rate 0 = tidak pernah
+	inject := func(ctx context.Context, method string) error {
+		if skip[method] {
+			return nil
+		}
+		if cfg.Latency > 0 && rand.Float64() < cfg.LatencyRate {
+			timer := time.NewTimer(cfg.Latency)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return status.FromContextError(ctx.Err()).Err()
+			}
+		}
+		if rand.Float64() < cfg.ErrorRate {
+			return status.Errorf(code, "chaos: injected %s on %s", code, method)
+		}
+		return nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := inject(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := inject(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream, nil
+}
+
+// parseCode membaca nama code gRPC ("UNAVAILABLE", "resource_exhausted") atau angkanya ("14")
+func parseCode(raw string) (codes.Code, error) {
+	input := raw
+	if _, err := strconv.Atoi(raw); err != nil {
+		input = strconv.Quote(strings.ToUpper(raw)) // UnmarshalJSON mengenali nama dalam bentuk JSON string
+	}
+
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(input)); err != nil {
+		return 0, fmt.Errorf("unknown gRPC code %q", raw)
+	}
+	return code, nil
+}
@@ -0,0 +1,97 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChaosFullErrorRateAlwaysFails(t *testing.T) {
+	unary, stream, err := Chaos(ChaosConfig{ErrorRate: 1, ErrorCode: "resource_exhausted"})
+	if err != nil {
+		t.Fatalf("Chaos: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		called := false
+		_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				called = true
+				return "ok", nil
+			})
+		if status.Code(err) != codes.ResourceExhausted || called {
+			t.Fatalf("unary call %d: err = %v, handler called = %v; want ResourceExhausted without calling the handler", i, err, called)
+		}
+
+		err = stream(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+			func(srv interface{}, ss grpc.ServerStream) error {
+				called = true
+				return nil
+			})
+		if status.Code(err) != codes.ResourceExhausted || called {
+			t.Fatalf("stream call %d: err = %v, handler called = %v; want ResourceExhausted", i, err, called)
+		}
+	}
+}
+
+func TestChaosZeroErrorRateNeverFails(t *testing.T) {
+	unary, stream, err := Chaos(ChaosConfig{ErrorRate: 0, ErrorCode: "UNAVAILABLE"})
+	if err != nil {
+		t.Fatalf("Chaos: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler)
+		if err != nil || resp != "ok" {
+			t.Fatalf("unary call %d: resp = %v, err = %v", i, resp, err)
+		}
+		err = stream(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+			func(srv interface{}, ss grpc.ServerStream) error { return nil })
+		if err != nil {
+			t.Fatalf("stream call %d: err = %v", i, err)
+		}
+	}
+}
+
+func TestChaosErrorCodeByNumberAndSkipMethods(t *testing.T) {
+	unary, _, err := Chaos(ChaosConfig{ErrorRate: 1, ErrorCode: "14", SkipMethods: []string{healthCheckMethod}})
+	if err != nil {
+		t.Fatalf("Chaos: %v", err)
+	}
+	if _, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler); status.Code(err) != codes.Unavailable {
+		t.Fatalf("err = %v, want Unavailable (code 14)", err)
+	}
+	if resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: healthCheckMethod}, okHandler); err != nil || resp != "ok" {
+		t.Fatalf("skipped method: resp = %v, err = %v", resp, err)
+	}
+}
+
+func TestChaosLatencyRespectsDeadline(t *testing.T) {
+	unary, _, err := Chaos(ChaosConfig{Latency: time.Minute, LatencyRate: 1, ErrorCode: "UNAVAILABLE"})
+	if err != nil {
+		t.Fatalf("Chaos: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("delay ignored deadline: returned after %s", elapsed)
+	}
+}
+
+func TestChaosRejectsInvalidCode(t *testing.T) {
+	for _, code := range []string{"not_a_code", "OK", "0"} {
+		if _, _, err := Chaos(ChaosConfig{ErrorRate: 1, ErrorCode: code}); err == nil {
+			t.Fatalf("Chaos with error code %q: want error", code)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package interceptor
+
+import (
+	"context"
+
+	"user-service/store"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ConsistencyMetadataKey adalah metadata yang dipakai client untuk memilih mode konsistensi read
+//   - "strong"   → read melewati read cache, selalu ke source
+//   - "eventual" → (default) read boleh dari cache
+const ConsistencyMetadataKey = "consistency"
+
+// Consistency membuat interceptor (unary + stream) yang membaca metadata "consistency"
+// dan menandai context dengan store.WithStrongConsistency kalau diminta
+func Consistency() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	apply := func(ctx context.Context) (context.Context, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(ConsistencyMetadataKey)
+		if len(values) == 0 {
+			return ctx, nil
+		}
+		switch values[0] {
+		case "strong":
+			return store.WithStrongConsistency(ctx), nil
+		case "eventual", "":
+			return ctx, nil
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be strong or eventual, got %q", ConsistencyMetadataKey, values[0])
+		}
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := apply(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := apply(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+
+	return unary, stream
+}
@@ -0,0 +1,46 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"user-service/store"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestConsistencyMetadata(t *testing.T) {
+	unary, _ := Consistency()
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	tests := []struct {
+		value      string // Kosong = metadata tidak dikirim
+		wantStrong bool
+		wantCode   codes.Code
+	}{
+		{"", false, codes.OK},
+		{"eventual", false, codes.OK},
+		{"strong", true, codes.OK},
+		{"linearizable", false, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.value != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ConsistencyMetadataKey, tt.value))
+		}
+		var strong bool
+		_, err := unary(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			strong = store.StrongConsistency(ctx)
+			return "ok", nil
+		})
+		if code := status.Code(err); code != tt.wantCode {
+			t.Fatalf("consistency=%q: code = %v, want %v", tt.value, code, tt.wantCode)
+		}
+		if strong != tt.wantStrong {
+			t.Fatalf("consistency=%q: strong = %v, want %v", tt.value, strong, tt.wantStrong)
+		}
+	}
+}
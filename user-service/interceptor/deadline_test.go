@@ -0,0 +1,57 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDeadlineFloorRejectsNearExpiredContext(t *testing.T) {
+	unary, stream := DeadlineFloor(100 * time.Millisecond)
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	called := false
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return "ok", nil
+	}
+	_, err := unary(ctx, nil, info, handler)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("err = %v, want DeadlineExceeded", err)
+	}
+	if called {
+		t.Fatal("handler ran although the deadline was below the floor")
+	}
+
+	streamCalled := false
+	err = stream(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: listUsersMethod}, func(srv interface{}, ss grpc.ServerStream) error {
+		streamCalled = true
+		return nil
+	})
+	if status.Code(err) != codes.DeadlineExceeded || streamCalled {
+		t.Fatalf("stream: err = %v, handler ran = %v; want DeadlineExceeded without running", err, streamCalled)
+	}
+}
+
+func TestDeadlineFloorAllowsEnoughBudget(t *testing.T) {
+	unary, _ := DeadlineFloor(100 * time.Millisecond)
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if resp, err := unary(ctx, nil, info, okHandler); err != nil || resp != "ok" {
+		t.Fatalf("deadline above floor: resp = %v, err = %v", resp, err)
+	}
+
+	// Tanpa deadline selalu diteruskan
+	if resp, err := unary(context.Background(), nil, info, okHandler); err != nil || resp != "ok" {
+		t.Fatalf("no deadline: resp = %v, err = %v", resp, err)
+	}
+}
@@ -0,0 +1,231 @@
+package interceptor
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// DedupConfig mengatur perilaku deduplication interceptor
+type DedupConfig struct {
+	Window     time.Duration // Berapa lama response disimpan untuk mendeteksi duplikat
+	MaxEntries int           // Batas jumlah entry di cache (yang paling lama dibuang duluan)
+	Methods    []string      // Full method name yang di-dedup, contoh: "/user.UserService/CreateUser"
+}
+
+// dedupEntry adalah 1 hasil RPC yang disimpan di cache
+// done di-close saat handler selesai, supaya duplikat yang datang
+// BERSAMAAN dengan request pertama ikut menunggu hasil yang sama
+type dedupEntry struct {
+	key       string
+	done      chan struct{}
+	resp      interface{}
+	err       error
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+// dedupCache adalah cache kecil berbasis map + linked list (urutan insert)
+type dedupCache struct {
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	order   *list.List // Front = entry paling lama
+	cfg     DedupConfig
+}
+
+// errDedupAborted adalah hasil entry yang handler-nya panic (tidak pernah return)
+// Duplikat yang menunggu melihat error → menjalankan handler sendiri, entry dibuang
+var errDedupAborted = status.Error(codes.Internal, "request aborted")
+
+// Dedup membuat UnaryServerInterceptor yang mendeteksi request duplikat
+// dalam window pendek, lalu mengembalikan response sebelumnya (at-most-once sederhana)
+func Dedup(cfg DedupConfig) grpc.UnaryServerInterceptor {
+	methods := make(map[string]bool, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		methods[m] = true
+	}
+
+	cache := &dedupCache{
+		entries: make(map[string]*dedupEntry),
+		order:   list.New(),
+		cfg:     cfg,
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// Method yang tidak terdaftar (misal read/idempotent) langsung diteruskan
+		if !methods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+
+		key, ok := dedupKey(ctx, info.FullMethod, req)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		entry, isOwner := cache.acquire(key)
+		if !isOwner {
+			// Duplikat! Tunggu request pertama selesai lalu pakai hasilnya
+			select {
+			case <-entry.done:
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			}
+			if entry.err == nil {
+				log.Printf("♻️  Duplicate %s detected, returning cached response", info.FullMethod)
+				return entry.resp, nil
+			}
+			// Request pertama gagal → error tidak di-cache, proses ulang
+			return handler(ctx, req)
+		}
+
+		// complete lewat defer: kalau handler panic (diubah jadi Internal oleh interceptor Recovery),
+		// entry tetap selesai dengan errDedupAborted, jadi duplikat tidak menunggu sampai deadline
+		var resp interface{}
+		err := errDedupAborted
+		defer func() { cache.complete(entry, resp, err) }()
+		resp, err = handler(ctx, req)
+		return resp, err
+	}
+}
+
+// dedupKey = sha256(method + caller identity + serialized request)
+// Serialization deterministic supaya request yang sama menghasilkan bytes yang sama
+func dedupKey(ctx context.Context, method string, req interface{}) (string, bool) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", false
+	}
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", false
+	}
+
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(callerIdentity(ctx)))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// callerIdentity menentukan "siapa" pemanggilnya:
+// 1. authorization metadata (token yang sama = caller yang sama)
+// 2. x-api-key metadata (AUTH_MODE=apikey; client berbeda di belakang 1 gateway punya key berbeda)
+// 3. identitas client certificate (mTLS), kalau interceptor Identity dipasang lebih dulu
+// 4. fallback ke IP peer (tanpa port, karena port bisa berubah per koneksi)
+func callerIdentity(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if auth := md.Get("authorization"); len(auth) > 0 {
+			return "auth:" + auth[0]
+		}
+		if key := md.Get("x-api-key"); len(key) > 0 {
+			return "apikey:" + key[0]
+		}
+	}
+	if id, ok := ClientIdentityFromContext(ctx); ok {
+		return "cert:" + id.String()
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return "peer:" + host
+		}
+		return "peer:" + p.Addr.String()
+	}
+	return ""
+}
+
+// acquire return entry untuk key tersebut
+// isOwner = true berarti caller ini yang harus menjalankan handler
+func (c *dedupCache) acquire(key string) (entry *dedupEntry, isOwner bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.evictExpired(now)
+
+	if existing, ok := c.entries[key]; ok {
+		return existing, false
+	}
+
+	entry = &dedupEntry{key: key, done: make(chan struct{})}
+	entry.elem = c.order.PushBack(entry)
+	c.entries[key] = entry
+
+	// Batasi ukuran cache: buang entry paling lama
+	for c.cfg.MaxEntries > 0 && c.order.Len() > c.cfg.MaxEntries {
+		c.remove(c.order.Front().Value.(*dedupEntry))
+	}
+	return entry, true
+}
+
+// complete menyimpan hasil handler dan membangunkan duplikat yang menunggu
+func (c *dedupCache) complete(entry *dedupEntry, resp interface{}, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.resp = resp
+	entry.err = err
+	entry.expiresAt = time.Now().Add(c.cfg.Window)
+	close(entry.done)
+
+	// Error tidak di-cache, supaya retry setelah gagal tetap diproses
+	if err != nil {
+		c.remove(entry)
+	}
+}
+
+// evictExpired membuang entry yang sudah lewat window (dari yang paling lama)
+// Entry yang masih in-flight (expiresAt zero) tidak dibuang
+func (c *dedupCache) evictExpired(now time.Time) {
+	for e := c.order.Front(); e != nil; {
+		next := e.Next()
+		entry := e.Value.(*dedupEntry)
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			c.remove(entry)
+		}
+		e = next
+	}
+}
+
+// remove hapus entry dari map dan list (caller harus pegang lock)
+func (c *dedupCache) remove(entry *dedupEntry) {
+	if c.entries[entry.key] == entry {
+		delete(c.entries, entry.key)
+	}
+	c.order.Remove(entry.elem)
+}
+
+/*
+📚 CATATAN: Dedup Window vs Idempotency Key
+
+Interceptor ini hanya "pengaman" untuk double-submit tidak sengaja
+(misal user double-click tombol submit lewat gateway).
+
+Keterbatasan dibanding idempotency key yang sebenarnya:
+1. Hanya request yang IDENTIK byte-per-byte yang dianggap duplikat.
+   Kalau client kirim ulang dengan field berbeda sedikit → dianggap request baru.
+2. Window pendek & in-memory: setelah window lewat, restart service,
+   atau entry terbuang karena cache penuh → duplikat akan diproses lagi.
+3. Tidak bisa membedakan "retry" dari "memang sengaja create 2x dengan data sama".
+   Idempotency key membuat niat client eksplisit.
+4. Per-instance: kalau ada beberapa replica, duplikat yang masuk ke replica
+   berbeda tidak terdeteksi.
+5. Caller identity berbasis authorization / x-api-key metadata atau IP peer.
+   Di belakang gateway, semua request dari gateway punya IP yang sama.
+
+Untuk at-most-once yang benar-benar terjamin, gunakan idempotency key
+yang disimpan di storage bersama (database/Redis).
+*/
@@ -0,0 +1,269 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const createUserMethod = "/user.UserService/CreateUser"
+
+// countingHandler return handler yang menghitung berapa kali dijalankan;
+// setiap call menghasilkan response dengan id berbeda
+func countingHandler(calls *atomic.Int32) grpc.UnaryHandler {
+	return func(ctx context.Context, req interface{}) (interface{}, error) {
+		n := calls.Add(1)
+		return &pb.CreateUserResponse{User: &pb.User{Id: string(rune('a' + n - 1))}}, nil
+	}
+}
+
+func callerCtx(token string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", token))
+}
+
+func newDedup(window time.Duration, maxEntries int) grpc.UnaryServerInterceptor {
+	return Dedup(DedupConfig{Window: window, MaxEntries: maxEntries, Methods: []string{createUserMethod}})
+}
+
+func TestDedupRapidDuplicateSubmit(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+	req := &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com", Age: 30}
+
+	first, err := dedup(callerCtx("Bearer alice"), req, info, handler)
+	if err != nil {
+		t.Fatalf("first submit: %v", err)
+	}
+	// Double-click: request identik (message baru, isi sama) langsung menyusul
+	second, err := dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com", Age: 30}, info, handler)
+	if err != nil {
+		t.Fatalf("duplicate submit: %v", err)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("handler calls = %d, want 1", got)
+	}
+	if first != second {
+		t.Fatal("duplicate did not receive the cached response of the first submit")
+	}
+}
+
+func TestDedupConcurrentDuplicatesShareResult(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	release := make(chan struct{})
+	var calls atomic.Int32
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls.Add(1)
+		<-release // Tahan request pertama supaya duplikat datang saat masih in-flight
+		return &pb.CreateUserResponse{User: &pb.User{Id: "u1"}}, nil
+	}
+
+	const n = 5
+	var wg sync.WaitGroup
+	resps := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resps[i], _ = dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("handler calls = %d, want 1", got)
+	}
+	for i, resp := range resps {
+		if resp != resps[0] {
+			t.Fatalf("response %d differs from the first response", i)
+		}
+	}
+}
+
+func TestDedupDistinguishesRequests(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+
+	dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	dedup(callerCtx("Bearer bob"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)   // Caller lain
+	dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice2"}, info, handler) // Body lain
+	dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"},
+		&grpc.UnaryServerInfo{FullMethod: "/user.UserService/UpdateUser"}, handler) // Method tidak di-dedup
+
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("handler calls = %d, want 4", got)
+	}
+}
+
+func TestDedupDoesNotCacheErrors(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	var calls atomic.Int32
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if calls.Add(1) == 1 {
+			return nil, errors.New("transient")
+		}
+		return &pb.CreateUserResponse{}, nil
+	}
+
+	if _, err := dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler); err == nil {
+		t.Fatal("first call: want error")
+	}
+	if _, err := dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler); err != nil {
+		t.Fatalf("retry after error: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("handler calls = %d, want 2 (retry after error must run the handler)", got)
+	}
+}
+
+func TestDedupWindowExpires(t *testing.T) {
+	dedup := newDedup(20*time.Millisecond, 100)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+
+	dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	time.Sleep(40 * time.Millisecond)
+	dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("handler calls = %d, want 2 (window expired)", got)
+	}
+}
+
+func TestDedupMaxEntriesEvictsOldest(t *testing.T) {
+	dedup := newDedup(time.Minute, 2)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+
+	for _, name := range []string{"a", "b", "c", "a"} {
+		dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: name}, info, handler)
+	}
+	// "a" sudah dibuang saat "c" masuk (cache maksimal 2), jadi "a" kedua diproses lagi
+	if got := calls.Load(); got != 4 {
+		t.Fatalf("handler calls = %d, want 4", got)
+	}
+}
+
+func TestDedupHandlerPanicReleasesEntry(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	recoveryUnary, _ := Recovery()
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	release := make(chan struct{})
+	var calls atomic.Int32
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if calls.Add(1) == 1 {
+			<-release // Duplikat datang saat request pertama masih in-flight
+			panic("boom")
+		}
+		return &pb.CreateUserResponse{User: &pb.User{Id: "u1"}}, nil
+	}
+	// Urutan sama dengan main: Recovery di luar Dedup
+	call := func(ctx context.Context) (interface{}, error) {
+		return recoveryUnary(ctx, &pb.CreateUserRequest{Name: "Alice"}, info,
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return dedup(ctx, req, info, handler)
+			})
+	}
+
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := call(callerCtx("Bearer alice"))
+		firstErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	waiterErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(callerCtx("Bearer alice"), 5*time.Second)
+		defer cancel()
+		_, err := call(ctx)
+		waiterErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if err := <-firstErr; status.Code(err) != codes.Internal {
+		t.Fatalf("panicking request: err = %v, want Internal", err)
+	}
+	select {
+	case err := <-waiterErr:
+		if err != nil {
+			t.Fatalf("waiting duplicate: %v (want it to run the handler itself)", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting duplicate still blocked after the first request panicked")
+	}
+
+	// Entry tidak tertinggal: request berikutnya langsung diproses, bukan menunggu selamanya
+	ctx, cancel := context.WithTimeout(callerCtx("Bearer alice"), time.Second)
+	defer cancel()
+	if _, err := call(ctx); err != nil {
+		t.Fatalf("request after panic: %v", err)
+	}
+}
+
+func TestDedupWaiterCanceledReturnsStatus(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	release := make(chan struct{})
+	defer close(release)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		<-release
+		return &pb.CreateUserResponse{}, nil
+	}
+
+	go dedup(callerCtx("Bearer alice"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(callerCtx("Bearer alice"), 20*time.Millisecond)
+	defer cancel()
+	_, err := dedup(ctx, &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("waiter err = %v, want DeadlineExceeded status", err)
+	}
+}
+
+func TestDedupAPIKeyIdentity(t *testing.T) {
+	dedup := newDedup(time.Minute, 100)
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+	var calls atomic.Int32
+	handler := countingHandler(&calls)
+	// Semua request datang dari IP gateway yang sama, dibedakan oleh API key
+	gateway := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 40000}}
+	keyCtx := func(key string) context.Context {
+		ctx := peer.NewContext(context.Background(), gateway)
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("x-api-key", key))
+	}
+
+	dedup(keyCtx("client-a-key-0123456789"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	dedup(keyCtx("client-b-key-0123456789"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("handler calls = %d, want 2 (different API keys are different callers)", got)
+	}
+	dedup(keyCtx("client-a-key-0123456789"), &pb.CreateUserRequest{Name: "Alice"}, info, handler)
+	if got := calls.Load(); got != 2 {
+		t.Fatalf("handler calls = %d, want 2 (same API key is a duplicate)", got)
+	}
+}
@@ -0,0 +1,95 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIdentity adalah identitas client dari client certificate yang sudah diverifikasi (mTLS)
+type ClientIdentity struct {
+	CommonName string   // Subject CN
+	DNSNames   []string // SAN DNS
+	URIs       []string // SAN URI (contoh: spiffe://cluster/ns/default/sa/api-gateway)
+}
+
+// String return identitas yang paling spesifik: URI SAN, lalu CN, lalu DNS SAN
+func (id ClientIdentity) String() string {
+	switch {
+	case len(id.URIs) > 0:
+		return id.URIs[0]
+	case id.CommonName != "":
+		return id.CommonName
+	case len(id.DNSNames) > 0:
+		return id.DNSNames[0]
+	default:
+		return ""
+	}
+}
+
+type clientIdentityKey struct{}
+
+// ClientIdentityFromContext mengambil identitas client (ok = false kalau tidak ada client cert)
+// Dipakai handler/interceptor lain untuk logging dan authorization
+func ClientIdentityFromContext(ctx context.Context) (ClientIdentity, bool) {
+	id, ok := ctx.Value(clientIdentityKey{}).(ClientIdentity)
+	return id, ok
+}
+
+// peerIdentity membaca client cert yang SUDAH diverifikasi oleh TLS handshake
+// VerifiedChains kosong = client tidak mengirim cert (atau TLS tidak aktif)
+func peerIdentity(ctx context.Context) (ClientIdentity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.AuthInfo == nil {
+		return ClientIdentity{}, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return ClientIdentity{}, false
+	}
+
+	leaf := tlsInfo.State.VerifiedChains[0][0]
+	id := ClientIdentity{
+		CommonName: leaf.Subject.CommonName,
+		DNSNames:   leaf.DNSNames,
+	}
+	for _, uri := range leaf.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+	return id, true
+}
+
+// Identity membuat interceptor (unary + stream) yang menaruh ClientIdentity di context
+func Identity() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if id, ok := peerIdentity(ctx); ok {
+			log.Printf("🪪 %s called by %s", info.FullMethod, id)
+			ctx = context.WithValue(ctx, clientIdentityKey{}, id)
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if id, ok := peerIdentity(ss.Context()); ok {
+			log.Printf("🪪 %s called by %s", info.FullMethod, id)
+			ss = &contextStream{ServerStream: ss, ctx: context.WithValue(ss.Context(), clientIdentityKey{}, id)}
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
+
+// contextStream mengganti Context() stream dengan context yang sudah diperkaya interceptor
+// (identitas client, mode konsistensi, dll)
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
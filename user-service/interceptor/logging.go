@@ -0,0 +1,72 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"user-service/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requestIDFromContext membaca request id dari metadata "x-request-id" (dikirim API Gateway
+// dari header X-Request-ID), "" kalau client tidak mengirimnya
+func requestIDFromContext(ctx context.Context) string {
+	if ids := metadata.ValueFromIncomingContext(ctx, "x-request-id"); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// Logging membuat interceptor (unary + stream) yang mencatat 1 log terstruktur per RPC:
+// method, duration_ms, status (gRPC code), dan user_id kalau request membawa id
+// Logger request-scoped (dengan request_id dari metadata "x-request-id" kalau ada)
+// disimpan di context, jadi handler bisa memakainya lewat logging.FromContext
+func Logging() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	requestLogger := func(ctx context.Context, method string) *slog.Logger {
+		logger := slog.Default().With("method", method)
+		if id := requestIDFromContext(ctx); id != "" {
+			logger = logger.With("request_id", id)
+		}
+		return logger
+	}
+
+	done := func(logger *slog.Logger, start time.Time, err error) {
+		code := status.Code(err)
+		level := slog.LevelInfo
+		if err != nil {
+			level = slog.LevelWarn
+		}
+		logger.Log(context.Background(), level, "rpc finished",
+			"status", code.String(),
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+		)
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		logger := requestLogger(ctx, info.FullMethod)
+		// GetUserRequest, UpdateUserRequest, DeleteUserRequest, dll punya GetId()
+		if r, ok := req.(interface{ GetId() string }); ok && r.GetId() != "" {
+			logger = logger.With("user_id", r.GetId())
+		}
+
+		resp, err := handler(logging.WithLogger(ctx, logger), req)
+		done(logger, start, err)
+		return resp, err
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		logger := requestLogger(ss.Context(), info.FullMethod)
+
+		err := handler(srv, &contextStream{ServerStream: ss, ctx: logging.WithLogger(ss.Context(), logger)})
+		done(logger, start, err)
+		return err
+	}
+
+	return unary, stream
+}
@@ -0,0 +1,90 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"user-service/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// captureLogs mengarahkan slog.Default ke buffer JSON selama 1 test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// logLines mem-parse setiap baris log JSON
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestLoggingRequestIDFromMetadata(t *testing.T) {
+	buf := captureLogs(t)
+	unary, stream := Logging()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-123"))
+
+	// Handler melihat request id di context & memakainya lewat logger request-scoped
+	_, err := unary(ctx, nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			if got := requestIDFromContext(ctx); got != "req-123" {
+				t.Errorf("requestIDFromContext = %q, want req-123", got)
+			}
+			logging.FromContext(ctx).Info("handler log")
+			return "ok", nil
+		})
+	if err != nil {
+		t.Fatalf("unary: %v", err)
+	}
+	err = stream(nil, &contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+		func(srv interface{}, ss grpc.ServerStream) error {
+			logging.FromContext(ss.Context()).Info("stream handler log")
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("stream: %v", err)
+	}
+
+	lines := logLines(t, buf)
+	if len(lines) != 4 {
+		t.Fatalf("got %d log lines, want 4 (handler + rpc finished, twice):\n%s", len(lines), buf)
+	}
+	for _, line := range lines {
+		if line["request_id"] != "req-123" {
+			t.Fatalf("log line without request_id: %v", line)
+		}
+	}
+}
+
+func TestLoggingWithoutRequestID(t *testing.T) {
+	buf := captureLogs(t)
+	unary, _ := Logging()
+
+	if _, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler); err != nil {
+		t.Fatalf("unary: %v", err)
+	}
+	for _, line := range logLines(t, buf) {
+		if _, ok := line["request_id"]; ok {
+			t.Fatalf("request_id logged without metadata: %v", line)
+		}
+	}
+}
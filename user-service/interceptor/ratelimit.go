@@ -0,0 +1,93 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimitIdleTTL adalah berapa lama bucket client yang tidak aktif disimpan
+// Setelah itu bucket dibuang; client yang kembali mulai lagi dengan bucket penuh
+const rateLimitIdleTTL = 5 * time.Minute
+
+// clientBucket adalah token bucket milik 1 client
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// RateLimiter membatasi jumlah RPC per client dengan token bucket (golang.org/x/time/rate)
+// Client dikenali dari (urutan prioritas): user id di JWT, metadata "x-client-id",
+// lalu identitas yang sama dengan dedup (authorization, client cert, IP peer)
+type RateLimiter struct {
+	mu        sync.Mutex
+	rps       rate.Limit
+	burst     int
+	buckets   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+// NewRateLimiter membuat limiter rps request per detik per client, dengan burst tertentu
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	return &RateLimiter{
+		rps:       rate.Limit(rps),
+		burst:     burst,
+		buckets:   make(map[string]*clientBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow mengambil 1 token dari bucket client key
+// Bucket idle dibersihkan secara lazy (paling sering 1x per rateLimitIdleTTL),
+// jadi memory tidak tumbuh tanpa batas walaupun client datang dan pergi
+func (l *RateLimiter) allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimitIdleTTL {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= rateLimitIdleTTL {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &clientBucket{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, 1)
+}
+
+// rateLimitKey menentukan bucket mana yang dipakai request ini
+func rateLimitKey(ctx context.Context) string {
+	if claims, ok := ClaimsFromContext(ctx); ok && claims.UserID != "" {
+		return "user:" + claims.UserID
+	}
+	if ids := metadata.ValueFromIncomingContext(ctx, "x-client-id"); len(ids) > 0 && ids[0] != "" {
+		return "client:" + ids[0]
+	}
+	return callerIdentity(ctx)
+}
+
+// UnaryInterceptor menolak RPC dengan ResourceExhausted kalau bucket client sudah habis
+func (l *RateLimiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// Key tidak di-log: bisa berisi token dari metadata authorization
+		if !l.allow(rateLimitKey(ctx), time.Now()) {
+			log.Printf("🚦 Rate limit exceeded on %s", info.FullMethod)
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded, retry later")
+		}
+		return handler(ctx, req)
+	}
+}
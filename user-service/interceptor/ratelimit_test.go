@@ -0,0 +1,57 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func clientIDCtx(id string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-client-id", id))
+}
+
+func TestRateLimitRejectsRequestOverBurst(t *testing.T) {
+	const burst = 5
+	// Refill sangat lambat: selama test hanya burst yang tersedia
+	limiter := NewRateLimiter(0.001, burst)
+	unary := limiter.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: getUserMethod}
+
+	for i := 0; i < burst; i++ {
+		if _, err := unary(clientIDCtx("client-a"), nil, info, okHandler); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	_, err := unary(clientIDCtx("client-a"), nil, info, okHandler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("request %d: err = %v, want ResourceExhausted", burst+1, err)
+	}
+
+	// Bucket per client: client lain tidak ikut terkena limit
+	if _, err := unary(clientIDCtx("client-b"), nil, info, okHandler); err != nil {
+		t.Fatalf("other client: %v", err)
+	}
+}
+
+func TestRateLimitDropsIdleBuckets(t *testing.T) {
+	limiter := NewRateLimiter(1, 1)
+	start := time.Now()
+
+	limiter.allow("idle", start)
+	limiter.allow("active", start.Add(rateLimitIdleTTL-time.Second))
+	limiter.allow("active", start.Add(rateLimitIdleTTL))
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if _, ok := limiter.buckets["idle"]; ok {
+		t.Fatal("idle bucket was not garbage-collected")
+	}
+	if _, ok := limiter.buckets["active"]; !ok {
+		t.Fatal("active bucket was dropped")
+	}
+}
@@ -0,0 +1,44 @@
+package interceptor
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReadOnlyMessage adalah pesan error saat write ditolak karena read-only mode
+// Gateway memakai pesan ini untuk mengenali error dan mengubahnya jadi HTTP 503
+const ReadOnlyMessage = "service in read-only mode"
+
+// ReadOnly membuat interceptor (unary + stream) yang menolak RPC mutasi
+// selama flag bernilai true. RPC baca (Get, List) tetap dilayani.
+// Flag dibaca setiap request, jadi bisa di-toggle saat runtime tanpa restart
+func ReadOnly(flag *atomic.Bool, mutatingMethods []string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	mutating := make(map[string]bool, len(mutatingMethods))
+	for _, m := range mutatingMethods {
+		mutating[m] = true
+	}
+
+	blocked := func(method string) bool {
+		return mutating[method] && flag.Load()
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if blocked(info.FullMethod) {
+			return nil, status.Error(codes.FailedPrecondition, ReadOnlyMessage)
+		}
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if blocked(info.FullMethod) {
+			return status.Error(codes.FailedPrecondition, ReadOnlyMessage)
+		}
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
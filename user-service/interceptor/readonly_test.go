@@ -0,0 +1,66 @@
+package interceptor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	getUserMethod   = "/user.UserService/GetUser"
+	listUsersMethod = "/user.UserService/ListUsers"
+)
+
+func okHandler(ctx context.Context, req interface{}) (interface{}, error) {
+	return "ok", nil
+}
+
+func TestReadOnlyBlocksWritesAllowsReads(t *testing.T) {
+	var flag atomic.Bool
+	flag.Store(true)
+	unary, stream := ReadOnly(&flag, []string{createUserMethod, "/user.UserService/BatchCreateUsers"})
+
+	_, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: createUserMethod}, okHandler)
+	if st := status.Convert(err); st.Code() != codes.FailedPrecondition || st.Message() != ReadOnlyMessage {
+		t.Fatalf("CreateUser in read-only mode: err = %v, want FailedPrecondition %q", err, ReadOnlyMessage)
+	}
+	if resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler); err != nil || resp != "ok" {
+		t.Fatalf("GetUser in read-only mode: resp = %v, err = %v", resp, err)
+	}
+
+	// Stream mutasi (batch) ditolak, stream baca (list) tetap jalan
+	var streamed bool
+	streamHandler := func(srv interface{}, ss grpc.ServerStream) error {
+		streamed = true
+		return nil
+	}
+	err = stream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/user.UserService/BatchCreateUsers"}, streamHandler)
+	if status.Code(err) != codes.FailedPrecondition || streamed {
+		t.Fatalf("BatchCreateUsers in read-only mode: err = %v, handler ran = %v", err, streamed)
+	}
+	if err := stream(nil, nil, &grpc.StreamServerInfo{FullMethod: listUsersMethod}, streamHandler); err != nil || !streamed {
+		t.Fatalf("ListUsers in read-only mode: err = %v, handler ran = %v", err, streamed)
+	}
+}
+
+func TestReadOnlyToggleAtRuntime(t *testing.T) {
+	var flag atomic.Bool
+	unary, _ := ReadOnly(&flag, []string{createUserMethod})
+	info := &grpc.UnaryServerInfo{FullMethod: createUserMethod}
+
+	if _, err := unary(context.Background(), nil, info, okHandler); err != nil {
+		t.Fatalf("read-only off: %v", err)
+	}
+	flag.Store(true)
+	if _, err := unary(context.Background(), nil, info, okHandler); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("read-only on: err = %v, want FailedPrecondition", err)
+	}
+	flag.Store(false)
+	if _, err := unary(context.Background(), nil, info, okHandler); err != nil {
+		t.Fatalf("read-only off again: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package interceptor
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+
+	"user-service/logging"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Recovery membuat interceptor (unary + stream) yang menangkap panic di handler
+// dan mengubahnya menjadi error Internal, jadi 1 request yang bermasalah
+// tidak mematikan seluruh proses (gRPC tidak me-recover panic sendiri)
+// Stack trace dicatat di log server, TIDAK dikirim ke client
+func Recovery() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	recovered := func(ctx context.Context, method string, p interface{}) error {
+		logging.FromContext(ctx).Error("panic recovered",
+			slog.String("method", method),
+			slog.Any("panic", p),
+			slog.String("stack", string(debug.Stack())),
+		)
+		return status.Error(codes.Internal, "internal server error")
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				resp, err = nil, recovered(ctx, info.FullMethod, p)
+			}
+		}()
+		return handler(ctx, req)
+	}
+
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ss.Context(), info.FullMethod, p)
+			}
+		}()
+		return handler(srv, ss)
+	}
+
+	return unary, stream
+}
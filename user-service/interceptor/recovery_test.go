@@ -0,0 +1,50 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryConvertsPanicToInternal(t *testing.T) {
+	unary, stream := Recovery()
+
+	resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			panic("nil map write")
+		})
+	if resp != nil || status.Code(err) != codes.Internal {
+		t.Fatalf("unary panic: resp = %v, err = %v; want nil, Internal", resp, err)
+	}
+	// Detail panic hanya di log server, tidak bocor ke client
+	if msg := status.Convert(err).Message(); msg != "internal server error" {
+		t.Fatalf("message = %q, want generic message", msg)
+	}
+
+	err = stream(nil, &contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: listUsersMethod},
+		func(srv interface{}, ss grpc.ServerStream) error {
+			panic("index out of range")
+		})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("stream panic: err = %v, want Internal", err)
+	}
+}
+
+func TestRecoveryPassesThroughNormalResults(t *testing.T) {
+	unary, _ := Recovery()
+
+	resp, err := unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod}, okHandler)
+	if resp != "ok" || err != nil {
+		t.Fatalf("resp = %v, err = %v; want ok", resp, err)
+	}
+	_, err = unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: getUserMethod},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("err = %v, want NotFound unchanged", err)
+	}
+}
@@ -0,0 +1,124 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamBudget membatasi total bytes message stream yang sedang "menggantung"
+// (sudah diproduksi handler tapi belum diterima transport) di SEMUA stream aktif.
+//
+// stream.Send() memblok selama flow-control window client penuh, jadi message dari
+// client lambat menumpuk di sini. Kalau budget habis, Send() berikutnya (dari stream
+// manapun) menunggu sampai ada message yang terkirim → produksi otomatis berhenti
+// sementara dan lanjut lagi begitu client mulai menghabiskan data.
+type StreamBudget struct {
+	mu       sync.Mutex
+	max      int64
+	inFlight int64
+	released chan struct{} // Ditutup (lalu diganti) setiap ada bytes yang dilepas
+}
+
+// NewStreamBudget membuat budget global maxBytes dan mendaftarkan gauge
+// rpc.server.stream.inflight_bytes di global MeterProvider
+func NewStreamBudget(maxBytes int64) (*StreamBudget, error) {
+	b := &StreamBudget{max: maxBytes, released: make(chan struct{})}
+
+	meter := otel.Meter("user-service/interceptor")
+	_, err := meter.Int64ObservableGauge("rpc.server.stream.inflight_bytes",
+		metric.WithDescription("Bytes of streamed messages queued but not yet accepted by the transport"),
+		metric.WithUnit("By"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			o.Observe(b.InFlight())
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// InFlight return total bytes yang sedang menunggu terkirim
+func (b *StreamBudget) InFlight() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.inFlight
+}
+
+// acquire menunggu sampai n bytes muat di budget (atau ctx selesai).
+// Message yang lebih besar dari budget tetap boleh lewat kalau sedang tidak ada
+// yang in-flight, supaya 1 message besar tidak macet selamanya.
+// Return true kalau sempat harus menunggu
+func (b *StreamBudget) acquire(ctx context.Context, n int64) (bool, error) {
+	waited := false
+	for {
+		b.mu.Lock()
+		if b.inFlight == 0 || b.inFlight+n <= b.max {
+			b.inFlight += n
+			b.mu.Unlock()
+			return waited, nil
+		}
+		wait := b.released
+		b.mu.Unlock()
+
+		waited = true
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return waited, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// release melepas n bytes dan membangunkan semua stream yang sedang menunggu
+func (b *StreamBudget) release(n int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.inFlight -= n
+	close(b.released)
+	b.released = make(chan struct{})
+}
+
+// StreamInterceptor membungkus setiap server stream supaya SendMsg lewat budget
+func (b *StreamBudget) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &budgetStream{ServerStream: ss, budget: b, method: info.FullMethod})
+	}
+}
+
+// budgetStream menghitung ukuran setiap message yang dikirim ke client
+type budgetStream struct {
+	grpc.ServerStream
+	budget *StreamBudget
+	method string
+	paused bool // Sudah pernah di-log pause (cukup 1x per stream)
+}
+
+func (s *budgetStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return s.ServerStream.SendMsg(m)
+	}
+
+	n := int64(proto.Size(msg))
+	waited, err := s.budget.acquire(s.Context(), n)
+	if err != nil {
+		return err
+	}
+	if waited && !s.paused {
+		s.paused = true
+		log.Printf("⏸️  %s paused: stream in-flight budget (%d bytes) exhausted", s.method, s.budget.max)
+	}
+	defer s.budget.release(n)
+
+	return s.ServerStream.SendMsg(m)
+}
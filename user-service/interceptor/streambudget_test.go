@@ -0,0 +1,131 @@
+package interceptor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// slowClientStream meniru client lambat: SendMsg memblok sampai gate dibuka
+type slowClientStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	gate chan struct{}
+}
+
+func (s *slowClientStream) Context() context.Context { return s.ctx }
+
+func (s *slowClientStream) SendMsg(m interface{}) error {
+	<-s.gate
+	return nil
+}
+
+// sendVia menjalankan 1 SendMsg lewat interceptor budget di goroutine terpisah
+func sendVia(b *StreamBudget, ss grpc.ServerStream, msg proto.Message) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		done <- b.StreamInterceptor()(nil, ss, &grpc.StreamServerInfo{FullMethod: listUsersMethod}, func(srv interface{}, ss grpc.ServerStream) error {
+			return ss.SendMsg(msg)
+		})
+	}()
+	return done
+}
+
+func waitInFlight(t *testing.T, b *StreamBudget, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for b.InFlight() != want && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := b.InFlight(); got != want {
+		t.Fatalf("in-flight bytes = %d, want %d", got, want)
+	}
+}
+
+func TestStreamBudgetPausesProductionForSlowConsumer(t *testing.T) {
+	msg := &pb.UserResponse{User: &pb.User{Id: "u1", Name: "Alice", Email: "alice@example.com"}}
+	size := int64(proto.Size(msg))
+	b, err := NewStreamBudget(size + size/2) // Muat 1 message, tidak muat 2
+	if err != nil {
+		t.Fatalf("NewStreamBudget: %v", err)
+	}
+
+	slow := &slowClientStream{ctx: context.Background(), gate: make(chan struct{})}
+	first := sendVia(b, slow, msg)
+	waitInFlight(t, b, size)
+
+	// Stream lain harus menunggu selama message client lambat belum terkirim
+	other := &slowClientStream{ctx: context.Background(), gate: make(chan struct{})}
+	close(other.gate)
+	second := sendVia(b, other, msg)
+	select {
+	case err := <-second:
+		t.Fatalf("second send completed (err: %v) while the budget was exhausted", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Client lambat mulai membaca → budget lepas, produksi lanjut
+	close(slow.gate)
+	for _, done := range []<-chan error{first, second} {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("send: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("send still paused after the slow client drained")
+		}
+	}
+	waitInFlight(t, b, 0)
+}
+
+func TestStreamBudgetWaitEndsWithContext(t *testing.T) {
+	msg := &pb.UserResponse{User: &pb.User{Id: "u1"}}
+	b, err := NewStreamBudget(int64(proto.Size(msg)))
+	if err != nil {
+		t.Fatalf("NewStreamBudget: %v", err)
+	}
+
+	slow := &slowClientStream{ctx: context.Background(), gate: make(chan struct{})}
+	defer close(slow.gate)
+	sendVia(b, slow, msg)
+	waitInFlight(t, b, int64(proto.Size(msg)))
+
+	// Client yang sedang menunggu budget lalu disconnect tidak menggantung selamanya
+	ctx, cancel := context.WithCancel(context.Background())
+	waiting := sendVia(b, &slowClientStream{ctx: ctx, gate: make(chan struct{})}, msg)
+	cancel()
+	select {
+	case err := <-waiting:
+		if status.Code(err) != codes.Canceled {
+			t.Fatalf("err = %v, want Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting send did not return after the context was cancelled")
+	}
+}
+
+func TestStreamBudgetAllowsOversizedMessageWhenIdle(t *testing.T) {
+	b, err := NewStreamBudget(1)
+	if err != nil {
+		t.Fatalf("NewStreamBudget: %v", err)
+	}
+	fast := &slowClientStream{ctx: context.Background(), gate: make(chan struct{})}
+	close(fast.gate)
+
+	select {
+	case err := <-sendVia(b, fast, &pb.UserResponse{User: &pb.User{Id: "u1", Name: "bigger than the budget"}}):
+		if err != nil {
+			t.Fatalf("send: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("oversized message blocked forever on an idle budget")
+	}
+}
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// Setup membuat logger slog sesuai format ("json" atau "text") dan memasangnya sebagai default
+// slog.SetDefault juga mengarahkan log.Printf lama ke handler yang sama,
+// jadi semua log (lama maupun baru) keluar dengan format yang konsisten
+func Setup(format string, out io.Writer) *slog.Logger {
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(out, nil)
+	} else {
+		handler = slog.NewTextHandler(out, nil)
+	}
+
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+	return logger
+}
+
+type loggerKey struct{}
+
+// WithLogger menyimpan logger request-scoped (sudah berisi request_id, method, dll) di context
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext mengambil logger request-scoped, atau slog.Default() kalau tidak ada
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
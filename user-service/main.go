@@ -1,30 +1,98 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	// Import konfigurasi dari environment
+	"user-service/config"
+	// Import publisher domain event (NATS)
+	"user-service/events"
+	// Import gRPC interceptors (middleware)
+	"user-service/interceptor"
+	// Import structured logging (log/slog)
+	"user-service/logging"
+	"user-service/metrics"
 	// Import proto package
 	pb "user-service/proto/user"
+	// Import PII redaction untuk log
+	"user-service/redact"
 	// Import business logic server
 	"user-service/server"
+	"user-service/store"
+	// Import setup OpenTelemetry tracing
+	"user-service/tracing"
+	// Import konfigurasi TLS / mTLS
+	"user-service/tlsconfig"
+
+	// Instrumentasi gRPC untuk OpenTelemetry (bikin span per RPC)
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
 	// gRPC core package
 	"google.golang.org/grpc"
+	// Transport credentials (TLS)
+	"google.golang.org/grpc/credentials"
+	// Registrasi compressor gzip: response dikompres HANYA kalau client memintanya
+	// (grpc-encoding: gzip), client lain tetap dapat identity (tanpa kompresi)
+	_ "google.golang.org/grpc/encoding/gzip"
+	// Standard gRPC health check service (grpc.health.v1.Health)
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	// Keepalive HTTP/2 (ping) untuk koneksi idle
+	"google.golang.org/grpc/keepalive"
 	// Reflection untuk debugging/testing (seperti Postman untuk gRPC)
 	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	// 0. LOAD CONFIG
+	// Semua konfigurasi dibaca dari environment variable
+	// Fail fast kalau ada nilai yang tidak valid
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+
+	// Structured logging (log/slog): LOG_FORMAT=json untuk production, text untuk development
+	// PII redaction: semua output log melewati redact.Writer (email → salted hash)
+	logging.Setup(cfg.LogFormat, redact.Writer(os.Stderr))
+	if cfg.PIILogRedaction {
+		redact.Enable(cfg.PIILogSalt, cfg.PIILogFields)
+		log.Printf("🙈 PII redaction enabled for log fields: %v", cfg.PIILogFields)
+	}
+
+	// Setup tracing dengan sampling rate dari config
+	// Request yang datang dengan traceparent dari gateway mengikuti keputusan sampling upstream
+	shutdownTracing, err := tracing.Setup(context.Background(), "user-service", cfg.TraceSampleRate, cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("❌ Failed to setup tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+
+	log.Printf("🔭 Tracing enabled (sample rate: %.2f)", cfg.TraceSampleRate)
+
+	// Setup metrics (di-push ke collector yang sama dengan tracing)
+	shutdownMetrics, err := metrics.Setup(context.Background(), "user-service", cfg.OTLPEndpoint)
+	if err != nil {
+		log.Fatalf("❌ Failed to setup metrics: %v", err)
+	}
+	defer shutdownMetrics(context.Background())
+
 	// 1. CREATE TCP LISTENER
-	// Listen di port 50051 untuk menerima koneksi gRPC
+	// Listen di GRPC_PORT (default 50051) untuk menerima koneksi gRPC
 	// Format: ":port" berarti listen di semua network interfaces
-	lis, err := net.Listen("tcp", ":50051")
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.GRPCPort))
 	if err != nil {
 		log.Fatalf("❌ Failed to listen: %v", err)
 	}
 	
-	log.Println("🎧 Listening on :50051")
+	log.Printf("🎧 Listening on :%d", cfg.GRPCPort)
 
 	// 2. CREATE gRPC SERVER
 	// grpc.NewServer() membuat server dengan default configuration
@@ -32,14 +100,278 @@ func main() {
 	// - grpc.MaxRecvMsgSize() untuk limit ukuran message
 	// - grpc.UnaryInterceptor() untuk middleware/logging
 	// - grpc.Creds() untuk TLS/SSL
-	grpcServer := grpc.NewServer()
+	// Business logic server dibuat lebih dulu karena interceptor read-only
+	// membaca flag yang dimiliki server (bisa di-toggle lewat RPC SetReadOnly)
+	var userServerOpts []server.Option
+	if cfg.EmailCanonicalize {
+		userServerOpts = append(userServerOpts, server.WithEmailCanonicalizer(server.NewEmailCanonicalizer(
+			cfg.EmailIgnoreDotsDomains, cfg.EmailStripPlusDomains, cfg.EmailDomainAliases,
+		)))
+		log.Println("📧 Email canonicalization enabled for uniqueness")
+	}
+	// Default field CreateUser (policy per deployment)
+	if cfg.UserDefaulter == "static" {
+		defaulter, err := server.NewStaticDefaulter(cfg.UserDefaultAge, cfg.UserDefaultStatus)
+		if err != nil {
+			log.Fatalf("❌ Invalid USER_DEFAULT_STATUS: %v", err)
+		}
+		userServerOpts = append(userServerOpts, server.WithDefaulter(defaulter))
+		log.Printf("🧩 Static user defaults enabled (age: %d, status: %q)", cfg.UserDefaultAge, cfg.UserDefaultStatus)
+	}
+
+	// Soft delete untuk audit trail: user yang dihapus disembunyikan, bukan dibuang
+	if cfg.SoftDelete {
+		userServerOpts = append(userServerOpts, server.WithSoftDelete())
+		log.Println("🪦 Soft delete enabled (deleted users are kept with deleted_at)")
+	}
+
+	// Batas id per GetUsersByIds, samakan dengan GET_USERS_BY_IDS_MAX di gateway
+	userServerOpts = append(userServerOpts, server.WithMaxGetUsersByIDs(cfg.GetUsersByIDsMax))
+
+	// Login (RPC Authenticate): token ditandatangani dengan JWT_SECRET yang sama dengan interceptor.Auth
+	// Hanya di AUTH_MODE=jwt: di mode lain token hasil login tidak akan diterima RPC berikutnya
+	if cfg.AuthMode == "jwt" {
+		secret := []byte(cfg.JWTSecret)
+		userServerOpts = append(userServerOpts, server.WithTokenIssuer(func(userID string, roles []string) (string, error) {
+			return interceptor.SignJWT(interceptor.Claims{
+				UserID:    userID,
+				Roles:     roles,
+				ExpiresAt: time.Now().Add(cfg.JWTTTL).Unix(),
+			}, secret)
+		}))
+	}
+
+	// Domain event (create/update/delete) ke NATS dan/atau Kafka
+	// Best-effort: tidak pernah menggagalkan RPC
+	var publishers events.Multi
+	if cfg.NATSURL != "" {
+		publisher, err := events.NewNATSPublisher(cfg.NATSURL, cfg.NATSSubjectPrefix)
+		if err != nil {
+			log.Fatalf("❌ Failed to create NATS publisher: %v", err)
+		}
+		publishers = append(publishers, publisher)
+		log.Printf("📡 Publishing user events to NATS (subjects: %s.*)", cfg.NATSSubjectPrefix)
+	}
+	if len(cfg.KafkaBrokers) > 0 {
+		publishers = append(publishers, events.NewKafkaPublisher(cfg.KafkaBrokers, cfg.KafkaTopic))
+		log.Printf("📡 Publishing user events to Kafka (topic: %s, brokers: %v)", cfg.KafkaTopic, cfg.KafkaBrokers)
+	}
+	if len(publishers) > 0 {
+		defer publishers.Close()
+		userServerOpts = append(userServerOpts, server.WithEventPublisher(publishers))
+	}
+
+	// Storage persistent: SQLite (DATABASE_DSN) atau Postgres (DATABASE_URL), default tetap in-memory map
+	var userStore store.UserStore
+	if cfg.DatabaseURL != "" {
+		postgresStore, err := store.NewPostgresStore(context.Background(), cfg.DatabaseURL)
+		if err != nil {
+			log.Fatalf("❌ Failed to open database: %v", err)
+		}
+		defer postgresStore.Close()
+		userStore = postgresStore
+		log.Println("🐘 Using Postgres store")
+	} else if cfg.DatabaseDSN != "" {
+		sqliteStore, err := store.NewSQLiteStore(context.Background(), cfg.DatabaseDSN)
+		if err != nil {
+			log.Fatalf("❌ Failed to open database: %v", err)
+		}
+		defer sqliteStore.Close()
+		userStore = sqliteStore
+		log.Println("🗄️  Using SQLite store")
+	} else {
+		userStore = store.NewMemoryStore()
+		log.Println("🧠 Using in-memory store (data is lost on restart)")
+	}
+	// Cache Redis dipasang lebih dulu, jadi read cache lokal (kalau aktif) ada di depannya:
+	// lokal (per proses) → Redis (bersama antar replica) → store
+	if cfg.RedisURL != "" {
+		redisStore, err := store.NewRedisCachingStore(context.Background(), userStore, cfg.RedisURL, cfg.RedisCacheTTL)
+		if err != nil {
+			log.Fatalf("❌ Failed to connect to Redis: %v", err)
+		}
+		defer redisStore.Close()
+		userStore = redisStore
+		log.Printf("🧊 Redis cache enabled (ttl: %s)", cfg.RedisCacheTTL)
+	}
+	if cfg.ReadCacheTTL > 0 {
+		cachingStore, err := store.NewCachingStore(userStore, cfg.ReadCacheTTL, cfg.UserCacheSize)
+		if err != nil {
+			log.Fatalf("❌ Failed to create read cache: %v", err)
+		}
+		userStore = cachingStore
+		log.Printf("⚡ Read cache enabled (ttl: %s, size: %d)", cfg.ReadCacheTTL, cfg.UserCacheSize)
+	}
+	userServer := server.NewUserServer(userStore, userServerOpts...)
+	userServer.ReadOnlyFlag().Store(cfg.ReadOnly)
+
+	var unaryInterceptors []grpc.UnaryServerInterceptor
+	var streamInterceptors []grpc.StreamServerInterceptor
+
+	// Log terstruktur per RPC (method, durasi, status code) dipasang paling luar,
+	// supaya RPC yang ditolak interceptor lain (read-only, deadline floor, dll) juga tercatat
+	loggingUnary, loggingStream := interceptor.Logging()
+	unaryInterceptors = append(unaryInterceptors, loggingUnary)
+	streamInterceptors = append(streamInterceptors, loggingStream)
+
+	// Panic di handler (atau interceptor setelahnya) → codes.Internal, proses tetap hidup
+	// Dipasang tepat di dalam logging, jadi RPC yang panic tetap tercatat dengan status Internal
+	recoveryUnary, recoveryStream := interceptor.Recovery()
+	unaryInterceptors = append(unaryInterceptors, recoveryUnary)
+	streamInterceptors = append(streamInterceptors, recoveryStream)
+
+	// Chaos testing: latency/error buatan, dipasang di dalam logging & recovery supaya
+	// error buatan tetap tercatat, tapi sebelum auth dll (mensimulasikan server yang bermasalah)
+	if cfg.ChaosLatency > 0 || cfg.ChaosErrorRate > 0 {
+		chaosUnary, chaosStream, err := interceptor.Chaos(interceptor.ChaosConfig{
+			Latency:     cfg.ChaosLatency,
+			LatencyRate: cfg.ChaosLatencyRate,
+			ErrorRate:   cfg.ChaosErrorRate,
+			ErrorCode:   cfg.ChaosErrorCode,
+			SkipMethods: []string{
+				"/grpc.health.v1.Health/Check",
+				"/grpc.health.v1.Health/Watch",
+			},
+		})
+		if err != nil {
+			log.Fatalf("❌ Invalid CHAOS_ERROR_CODE: %v", err)
+		}
+		unaryInterceptors = append(unaryInterceptors, chaosUnary)
+		streamInterceptors = append(streamInterceptors, chaosStream)
+		log.Printf("🐒 CHAOS MODE enabled: latency %s (%.0f%% of requests), errors %s (%.0f%% of requests) — do not use for real traffic",
+			cfg.ChaosLatency, cfg.ChaosLatencyRate*100, cfg.ChaosErrorCode, cfg.ChaosErrorRate*100)
+	}
+
+	// Identitas client cert (mTLS) dipasang paling awal, supaya interceptor
+	// setelahnya (dedup, dll) dan handler bisa membacanya dari context
+	identityUnary, identityStream := interceptor.Identity()
+	unaryInterceptors = append(unaryInterceptors, identityUnary)
+	streamInterceptors = append(streamInterceptors, identityStream)
+
+	// Autentikasi (AUTH_MODE): RPC tanpa bearer token / API key yang valid ditolak dengan Unauthenticated
+	// Dipasang setelah identity & sebelum interceptor lain, jadi request tanpa token
+	// tidak sempat memakai resource (dedup cache, stream budget, dll)
+	switch cfg.AuthMode {
+	case "jwt":
+		authUnary, authStream := interceptor.Auth([]byte(cfg.JWTSecret), cfg.AuthSkipMethods)
+		unaryInterceptors = append(unaryInterceptors, authUnary)
+		streamInterceptors = append(streamInterceptors, authStream)
+		log.Printf("🔑 JWT auth enabled (skipped methods: %v)", cfg.AuthSkipMethods)
+
+		// Otorisasi per role (dari claims JWT): method → role yang diizinkan
+		// Method baru yang perlu dibatasi cukup ditambahkan di map ini
+		authzUnary, authzStream := interceptor.Authorize(map[string][]string{
+			pb.UserService_DeleteUser_FullMethodName:      {"admin"},
+			pb.UserService_BulkDeleteUsers_FullMethodName: {"admin"},
+			pb.UserService_RestoreUser_FullMethodName:     {"admin"},
+			pb.UserService_SetUserRoles_FullMethodName:    {"admin"},
+			pb.UserService_SetReadOnly_FullMethodName:     {"admin"},
+			pb.UserService_Compact_FullMethodName:         {"admin"},
+			pb.UserService_VerifyIntegrity_FullMethodName: {"admin"},
+		})
+		unaryInterceptors = append(unaryInterceptors, authzUnary)
+		streamInterceptors = append(streamInterceptors, authzStream)
+	case "apikey":
+		apiKeyUnary, apiKeyStream := interceptor.APIKey(cfg.APIKeys, cfg.AuthSkipMethods)
+		unaryInterceptors = append(unaryInterceptors, apiKeyUnary)
+		streamInterceptors = append(streamInterceptors, apiKeyStream)
+		log.Printf("🔑 API key auth enabled (%d keys, skipped methods: %v)", len(cfg.APIKeys), cfg.AuthSkipMethods)
+	default:
+		log.Println("⚠️  AUTH_MODE=none, RPCs are not authenticated")
+	}
+
+	// Rate limit per client: dipasang setelah auth supaya bucket bisa dikunci ke user id di JWT
+	if cfg.RateLimitRPS > 0 {
+		limiter := interceptor.NewRateLimiter(cfg.RateLimitRPS, cfg.RateLimitBurst)
+		unaryInterceptors = append(unaryInterceptors, limiter.UnaryInterceptor())
+		log.Printf("🚦 Rate limit enabled (%.1f rps, burst %d per client)", cfg.RateLimitRPS, cfg.RateLimitBurst)
+	}
+
+	// Mode konsistensi read: metadata "consistency: strong" → bypass read cache
+	consistencyUnary, consistencyStream := interceptor.Consistency()
+	unaryInterceptors = append(unaryInterceptors, consistencyUnary)
+	streamInterceptors = append(streamInterceptors, consistencyStream)
+
+	// Deadline floor: request yang sisa deadline-nya terlalu pendek ditolak sebelum dikerjakan
+	if cfg.DeadlineFloor > 0 {
+		floorUnary, floorStream := interceptor.DeadlineFloor(cfg.DeadlineFloor)
+		unaryInterceptors = append(unaryInterceptors, floorUnary)
+		streamInterceptors = append(streamInterceptors, floorStream)
+		log.Printf("⏱️  Deadline floor enabled (%s)", cfg.DeadlineFloor)
+	}
+
+	// Read-only (safe) mode: tolak semua RPC mutasi dengan FailedPrecondition
+	readOnlyUnary, readOnlyStream := interceptor.ReadOnly(userServer.ReadOnlyFlag(), []string{
+		pb.UserService_CreateUser_FullMethodName,
+		pb.UserService_UpdateUser_FullMethodName,
+		pb.UserService_DeleteUser_FullMethodName,
+		pb.UserService_BulkDeleteUsers_FullMethodName,
+		pb.UserService_TransferEmail_FullMethodName,
+		pb.UserService_BatchCreateUsers_FullMethodName,
+		pb.UserService_RestoreUser_FullMethodName,
+		pb.UserService_SetUserRoles_FullMethodName,
+		pb.UserService_Compact_FullMethodName, // Purge + rebuild index; VerifyIntegrity dicek di handler (hanya kalau repair)
+	})
+	unaryInterceptors = append(unaryInterceptors, readOnlyUnary)
+	streamInterceptors = append(streamInterceptors, readOnlyStream)
+	if cfg.ReadOnly {
+		log.Println("🔒 Starting in read-only mode")
+	}
+
+	// Backpressure streaming: total bytes yang menunggu terkirim di semua stream dibatasi,
+	// supaya beberapa client lambat tidak membuat memory server membengkak saat export besar
+	if cfg.StreamInFlightBytes > 0 {
+		budget, err := interceptor.NewStreamBudget(cfg.StreamInFlightBytes)
+		if err != nil {
+			log.Fatalf("❌ Failed to create stream budget: %v", err)
+		}
+		streamInterceptors = append(streamInterceptors, budget.StreamInterceptor())
+		log.Printf("🚰 Stream in-flight budget enabled (%d bytes)", cfg.StreamInFlightBytes)
+	}
+
+	// Request deduplication untuk RPC yang tidak idempotent (opsional)
+	// Mencegah double-submit tidak sengaja dalam window pendek
+	if cfg.DedupWindow > 0 {
+		unaryInterceptors = append(unaryInterceptors, interceptor.Dedup(interceptor.DedupConfig{
+			Window:     cfg.DedupWindow,
+			MaxEntries: cfg.DedupCacheSize,
+			Methods:    cfg.DedupMethods,
+		}))
+		log.Printf("♻️  Request dedup enabled (window: %s, methods: %v)", cfg.DedupWindow, cfg.DedupMethods)
+	}
+
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+
+		// Batas ukuran message (GRPC_MAX_MSG_SIZE_MB), berlaku untuk unary & tiap message stream
+		grpc.MaxRecvMsgSize(cfg.GRPCMaxMsgSizeMB<<20),
+		grpc.MaxSendMsgSize(cfg.GRPCMaxMsgSizeMB<<20),
+	}
+	serverOpts = append(serverOpts, keepaliveOptions(cfg)...)
+
+	// TLS (dan mutual TLS kalau TLS_CLIENT_AUTH=verify/require)
+	// Koneksi tanpa client cert yang valid ditolak saat handshake, sebelum RPC apapun jalan
+	if cfg.TLSCertFile != "" {
+		tlsCfg, err := tlsconfig.Server(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSClientAuth)
+		if err != nil {
+			log.Fatalf("❌ Invalid TLS configuration: %v", err)
+		}
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+		log.Printf("🔐 TLS enabled (client auth: %s)", cfg.TLSClientAuth)
+	} else {
+		log.Println("⚠️  TLS disabled (INSECURE=true): serving plaintext gRPC (development only)")
+	}
+
+	grpcServer := grpc.NewServer(serverOpts...)
+	log.Printf("📦 Max gRPC message size: %d MB", cfg.GRPCMaxMsgSizeMB)
 	
 	log.Println("🔧 gRPC Server created")
 
-	// 3. CREATE BUSINESS LOGIC SERVER
-	// Ini adalah struct kita yang implements gRPC service methods
-	userServer := server.NewUserServer()
-	
+	// 3. BUSINESS LOGIC SERVER
+	// userServer (struct yang implements gRPC service methods) sudah dibuat
+	// di atas sebelum interceptor, karena read-only flag miliknya dipakai interceptor
 	log.Println("👤 User Server initialized")
 
 	// 4. REGISTER SERVICE
@@ -50,26 +382,154 @@ func main() {
 	
 	log.Println("📝 UserService registered")
 
+	// 4b. REGISTER HEALTH SERVICE
+	// Load balancer / Kubernetes probe / gateway cek status lewat service ini
+	// Kalau warmup aktif, status NOT_SERVING sampai warmup selesai,
+	// supaya traffic tidak dikirim ke instance yang masih "dingin"
+	healthServer, setServing := registerHealth(grpcServer)
+
+	// Cek (dan repair) konsistensi index sebelum mulai SERVING (opsional)
+	// Di store persisten ini idealnya dijalankan setelah data di-load dari disk
+	verifyOnStartup := func() {
+		if cfg.VerifyIntegrityOnStartup {
+			// READ_ONLY=true → repair ditolak, index tetap seperti hasil load
+			if _, err := userServer.VerifyIntegrity(context.Background(), &pb.VerifyIntegrityRequest{Repair: true}); err != nil {
+				log.Printf("⚠️  Startup integrity check failed: %v", err)
+			}
+		}
+	}
+
+	if cfg.WarmupEnabled {
+		setServing(healthpb.HealthCheckResponse_NOT_SERVING)
+		log.Printf("🔥 Warming up (timeout: %s), reporting NOT_SERVING...", cfg.WarmupTimeout)
+
+		// Warmup jalan di background, server tetap start supaya health check bisa dijawab
+		go func() {
+			err := warmUp(cfg.WarmupTimeout, userServer.Warmup, func() {
+				verifyOnStartup()
+				setServing(healthpb.HealthCheckResponse_SERVING)
+				log.Println("💚 Warmup finished, reporting SERVING")
+			})
+			if err != nil {
+				log.Fatalf("❌ Warmup failed: %v", err)
+			}
+		}()
+	} else {
+		verifyOnStartup()
+		setServing(healthpb.HealthCheckResponse_SERVING)
+	}
+
+	log.Println("💚 Health service registered")
+
 	// 5. ENABLE REFLECTION (Optional, untuk development)
 	// Reflection memungkinkan tools seperti grpcurl untuk:
 	// - Discover services yang tersedia
 	// - Melihat method definitions
 	// - Testing tanpa perlu generate client code
-	// CATATAN: Disable di production untuk security
-	reflection.Register(grpcServer)
-	
-	log.Println("🔍 gRPC Reflection enabled")
+	// CATATAN: default off kalau ENV=production (override dengan ENABLE_REFLECTION)
+	registerReflection(grpcServer, cfg)
 
 	// 6. START SERVER
 	// Serve() adalah blocking call - program akan wait di sini
 	// Menerima dan handle incoming gRPC requests
-	log.Println("🚀 User Service running on :50051")
+	log.Printf("🚀 User Service running on :%d", cfg.GRPCPort)
 	log.Println("✅ Ready to receive gRPC requests...")
 	log.Println("⏳ Press Ctrl+C to stop")
-	
-	if err := grpcServer.Serve(lis); err != nil {
+
+	// Serve() blocking, jadi dijalankan di goroutine; main menunggu signal shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(lis)
+	}()
+
+	select {
+	case err := <-serveErr:
 		log.Fatalf("❌ Failed to serve: %v", err)
+	case <-ctx.Done():
+	}
+
+	// 7. GRACEFUL SHUTDOWN (Ctrl+C / SIGTERM)
+	// Laporkan NOT_SERVING dulu supaya load balancer & gateway berhenti mengirim traffic baru,
+	// lalu tunggu RPC yang sedang jalan selesai (maksimal SHUTDOWN_DRAIN_TIMEOUT)
+	log.Println("🛑 Shutdown signal received, reporting NOT_SERVING")
+	healthServer.Shutdown()
+	// Stream list yang sedang jalan berhenti dengan Unavailable di message berikutnya,
+	// jadi client tahu harus retry (bukan menerima stream yang terputus di tengah)
+	userServer.DrainingFlag().Store(true)
+
+	log.Printf("⏳ Draining in-flight RPCs (timeout: %s)...", cfg.ShutdownDrainTimeout)
+	drained := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop() // Tolak koneksi baru, tunggu RPC yang sedang jalan
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		log.Println("✅ All in-flight RPCs finished")
+	case <-time.After(cfg.ShutdownDrainTimeout):
+		// Stream panjang (WatchUsers, export besar) tidak akan selesai sendiri
+		log.Println("⏰ Drain timeout exceeded, closing remaining connections")
+		grpcServer.Stop()
+	}
+
+	log.Println("👋 User Service stopped")
+}
+
+// keepaliveOptions: server ping koneksi idle (KEEPALIVE_TIME/TIMEOUT) supaya koneksi mati
+// cepat ketahuan, dan mengizinkan ping client sampai KEEPALIVE_MIN_TIME sekali
+// (termasuk saat tidak ada RPC aktif) tanpa diputus "too_many_pings"
+func keepaliveOptions(cfg *config.Config) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    cfg.KeepaliveTime,
+			Timeout: cfg.KeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             cfg.KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+}
+
+// registerReflection mendaftarkan reflection service kalau ENABLE_REFLECTION aktif
+func registerReflection(grpcServer *grpc.Server, cfg *config.Config) {
+	if cfg.EnableReflection {
+		reflection.Register(grpcServer)
+		log.Println("🔍 gRPC Reflection enabled")
+	} else {
+		log.Println("🔍 gRPC Reflection disabled")
+	}
+}
+
+// registerHealth mendaftarkan grpc.health.v1.Health di grpcServer
+// setServing mengubah status server ("") dan UserService sekaligus
+func registerHealth(grpcServer *grpc.Server) (*health.Server, func(healthpb.HealthCheckResponse_ServingStatus)) {
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	setServing := func(status healthpb.HealthCheckResponse_ServingStatus) {
+		healthServer.SetServingStatus("", status)
+		healthServer.SetServingStatus(pb.UserService_ServiceDesc.ServiceName, status)
+	}
+	return healthServer, setServing
+}
+
+// warmUp menjalankan warmup dengan batas waktu timeout
+// ready (set SERVING) HANYA dipanggil kalau warmup sukses, jadi instance yang gagal warmup
+// tidak pernah menerima traffic
+func warmUp(timeout time.Duration, warmup func(context.Context) error, ready func()) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := warmup(ctx); err != nil {
+		return err
 	}
+	ready()
+	return nil
 }
 
 /*
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"user-service/config"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+// servingStatus membaca status health UserService seperti yang dilihat gateway (/readyz)
+func servingStatus(t *testing.T, hs *health.Server) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: pb.UserService_ServiceDesc.ServiceName})
+	if err != nil {
+		t.Fatalf("health check: %v", err)
+	}
+	return resp.Status
+}
+
+func TestReadinessFlipsOnlyAfterWarmup(t *testing.T) {
+	hs := health.NewServer()
+	hs.SetServingStatus(pb.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	release := make(chan struct{})
+	warmup := func(ctx context.Context) error {
+		<-release // Warmup "lambat": belum selesai sampai test melepasnya
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- warmUp(time.Minute, warmup, func() {
+			hs.SetServingStatus(pb.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if got := servingStatus(t, hs); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("status during warmup = %v, want NOT_SERVING", got)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("warmUp: %v", err)
+	}
+	if got := servingStatus(t, hs); got != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("status after warmup = %v, want SERVING", got)
+	}
+}
+
+func TestWarmupFailureNeverReady(t *testing.T) {
+	ready := false
+	err := warmUp(time.Minute, func(ctx context.Context) error { return errors.New("db unreachable") }, func() { ready = true })
+	if err == nil || ready {
+		t.Fatalf("err = %v, ready = %v; want error and not ready", err, ready)
+	}
+}
+
+func TestWarmupTimeout(t *testing.T) {
+	ready := false
+	err := warmUp(20*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, func() { ready = true })
+	if !errors.Is(err, context.DeadlineExceeded) || ready {
+		t.Fatalf("err = %v, ready = %v; want DeadlineExceeded and not ready", err, ready)
+	}
+}
+
+func TestHealthCheckServing(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	healthServer, setServing := registerHealth(grpcServer)
+	setServing(healthpb.HealthCheckResponse_SERVING)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		t.Helper()
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatalf("Check(%q): %v", service, err)
+		}
+		return resp.Status
+	}
+	for _, service := range []string{"", pb.UserService_ServiceDesc.ServiceName} {
+		if got := check(service); got != healthpb.HealthCheckResponse_SERVING {
+			t.Fatalf("Check(%q) = %v, want SERVING", service, got)
+		}
+	}
+
+	// Sama dengan urutan graceful shutdown di main
+	healthServer.Shutdown()
+	if got := check(pb.UserService_ServiceDesc.ServiceName); got != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Check after shutdown = %v, want NOT_SERVING", got)
+	}
+}
+
+func TestKeepaliveAcceptsAggressiveClientPings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for several keepalive pings")
+	}
+	t.Setenv("INSECURE", "true")
+	cfg, err := config.Load() // Default KEEPALIVE_MIN_TIME sama dengan interval ping minimum client gRPC (10s)
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer(keepaliveOptions(cfg)...)
+	_, setServing := registerHealth(grpcServer)
+	setServing(healthpb.HealthCheckResponse_SERVING)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	// Client paling agresif yang diizinkan gRPC: ping setiap 10s, juga saat tidak ada RPC aktif
+	conn, err := grpc.NewClient(lis.Addr().String(),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{Time: 10 * time.Second, Timeout: time.Second, PermitWithoutStream: true}),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := healthpb.NewHealthClient(conn)
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	// 3 ping dalam keadaan idle: server yang menolak ping mengirim GOAWAY "too_many_pings"
+	// setelah ping ke-3, dan koneksi keluar dari READY
+	ctx, cancel := context.WithTimeout(context.Background(), 35*time.Second)
+	defer cancel()
+	if conn.WaitForStateChange(ctx, connectivity.Ready) {
+		t.Fatalf("connection left READY (now %v) while idle with client keepalive", conn.GetState())
+	}
+	if _, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("Check after idle pings: %v", err)
+	}
+}
+
+// listServices memanggil reflection ListServices seperti `grpcurl list`
+func listServices(t *testing.T, cfg *config.Config) ([]string, error) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer()
+	pb.RegisterUserServiceServer(grpcServer, &pb.UnimplementedUserServiceServer{})
+	registerReflection(grpcServer, cfg)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		return nil, err
+	}
+	resp, err := stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	var services []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		services = append(services, service.Name)
+	}
+	return services, nil
+}
+
+func TestReflectionToggle(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		enabled bool
+	}{
+		{"dev default", nil, true},
+		{"production default", map[string]string{"ENV": "production"}, false},
+		{"production override", map[string]string{"ENV": "production", "ENABLE_REFLECTION": "true"}, true},
+		{"explicitly disabled", map[string]string{"ENABLE_REFLECTION": "false"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INSECURE", "true")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("config.Load: %v", err)
+			}
+
+			services, err := listServices(t, cfg)
+			if !tt.enabled {
+				if status.Code(err) != codes.Unimplemented {
+					t.Fatalf("reflection disabled: services = %v, err = %v; want Unimplemented", services, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ListServices: %v", err)
+			}
+			if !slices.Contains(services, pb.UserService_ServiceDesc.ServiceName) {
+				t.Fatalf("services = %v, want %s listed", services, pb.UserService_ServiceDesc.ServiceName)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Setup meng-configure global OpenTelemetry MeterProvider
+//   - otlpEndpoint: collector tujuan export metric, kosong = metric tidak di-export
+//     (instrument tetap bisa dibuat, nilainya saja yang dibuang)
+//
+// Instrument dari otelgrpc (rpc.server.duration, dll) dan interceptor ikut ter-export
+// karena semuanya memakai global MeterProvider.
+// Return function shutdown untuk flush metric yang tersisa saat service berhenti
+func Setup(ctx context.Context, serviceName string, otlpEndpoint string) (func(context.Context) error, error) {
+	if otlpEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpointURL(otlpEndpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter)),
+		sdkmetric.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+		)),
+	)
+	otel.SetMeterProvider(mp)
+
+	return mp.Shutdown, nil
+}
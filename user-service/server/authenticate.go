@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	pb "user-service/proto/user"
+	"user-service/redact"
+	"user-service/store"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TokenIssuer membuat access token untuk user yang berhasil Authenticate
+// (main memakai JWT HS256 yang sama dengan interceptor.Auth, lihat interceptor.SignJWT)
+// roles ikut ke token supaya interceptor.Authorize tidak perlu membaca store per RPC
+type TokenIssuer func(userID string, roles []string) (string, error)
+
+// WithTokenIssuer mengaktifkan RPC Authenticate; tanpa issuer Authenticate selalu FailedPrecondition
+func WithTokenIssuer(issue TokenIssuer) Option {
+	return func(s *UserServer) {
+		s.issueToken = issue
+	}
+}
+
+// errInvalidCredentials sengaja sama untuk email tidak terdaftar, user tanpa password,
+// dan password salah: client tidak boleh bisa menebak email mana yang terdaftar
+var errInvalidCredentials = status.Error(codes.Unauthenticated, "invalid email or password")
+
+// dummyPasswordHash dipakai saat email tidak ditemukan, supaya waktu respons tetap
+// sebesar 1x bcrypt compare (tanpa ini email terdaftar bisa ditebak dari latency)
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password for timing"), bcrypt.DefaultCost)
+	return hash
+})
+
+// hashPassword membuat bcrypt hash ("" kalau password kosong = user tanpa password)
+// Panjang password sudah divalidasi validateNewUser (bcrypt menolak > 72 byte)
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Authenticate memverifikasi email + password lalu mengeluarkan token (Unary RPC)
+// User soft-deleted dianggap tidak ada, user SUSPENDED ditolak dengan PermissionDenied
+func (s *UserServer) Authenticate(ctx context.Context, req *pb.AuthenticateRequest) (*pb.AuthenticateResponse, error) {
+	log.Printf("🔐 Authenticating user: %s", redact.Field("email", req.Email))
+
+	if s.issueToken == nil {
+		return nil, status.Error(codes.FailedPrecondition, "authentication is disabled (AUTH_MODE is not jwt)")
+	}
+	if req.Email == "" || req.Password == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	}
+
+	// Ambil user + hash di bawah read lock; bcrypt compare (lambat) dilakukan setelah lock dilepas
+	var (
+		user *pb.User
+		hash string
+		err  error
+	)
+	s.mu.RLock()
+	if id, ok := s.emailIndex[s.emailKey(req.Email)]; ok {
+		if user, err = s.getActiveUser(ctx, id); err == nil {
+			hash, err = s.store.GetPasswordHash(ctx, id)
+		}
+	}
+	s.mu.RUnlock()
+	if err != nil && !errors.Is(err, store.ErrUserNotFound) {
+		return nil, s.storeError(err)
+	}
+
+	if user == nil || hash == "" {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(req.Password))
+		return nil, errInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(req.Password)); err != nil {
+		return nil, errInvalidCredentials
+	}
+	if user.Status == pb.UserStatus_USER_STATUS_SUSPENDED {
+		return nil, status.Error(codes.PermissionDenied, "account is suspended")
+	}
+
+	token, err := s.issueToken(user.Id, user.Roles)
+	if err != nil {
+		log.Printf("❌ Failed to issue token for %s: %v", user.Id, err)
+		return nil, status.Error(codes.Internal, "failed to issue token")
+	}
+
+	log.Printf("✅ User authenticated: %s", user.Id)
+
+	return &pb.AuthenticateResponse{Token: token}, nil
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	pb "user-service/proto/user"
+
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const testPassword = "correct horse battery"
+
+// fakeIssuer membuat token yang bisa dibaca test: "token:<user id>"
+func fakeIssuer(userID string, roles []string) (string, error) {
+	return "token:" + userID, nil
+}
+
+func createUserWithPassword(t *testing.T, s *UserServer, email, password string, userStatus pb.UserStatus) *pb.User {
+	t.Helper()
+	resp, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{
+		Name: "Alice", Email: email, Age: 30, Status: userStatus, Password: password,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", email, err)
+	}
+	return resp.User
+}
+
+func TestAuthenticateCorrectPassword(t *testing.T) {
+	s, memory := newTestServer(t, nil, WithTokenIssuer(fakeIssuer))
+	user := createUserWithPassword(t, s, "alice@example.com", testPassword, pb.UserStatus_USER_STATUS_UNSPECIFIED)
+
+	resp, err := s.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "alice@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if resp.Token != "token:"+user.Id {
+		t.Fatalf("token = %q, want token for %s", resp.Token, user.Id)
+	}
+
+	// Yang disimpan hanya bcrypt hash, bukan password asli
+	hash, err := memory.GetPasswordHash(context.Background(), user.Id)
+	if err != nil {
+		t.Fatalf("GetPasswordHash: %v", err)
+	}
+	if hash == testPassword || bcrypt.CompareHashAndPassword([]byte(hash), []byte(testPassword)) != nil {
+		t.Fatalf("stored hash %q is not a bcrypt hash of the password", hash)
+	}
+}
+
+func TestAuthenticateRejectsInvalidCredentials(t *testing.T) {
+	s, _ := newTestServer(t, nil, WithTokenIssuer(fakeIssuer))
+	createUserWithPassword(t, s, "alice@example.com", testPassword, pb.UserStatus_USER_STATUS_UNSPECIFIED)
+	createUser(t, s, "No Password", "nopass@example.com")
+
+	tests := []struct {
+		name, email, password string
+	}{
+		{"wrong password", "alice@example.com", "wrong password"},
+		{"unknown email", "nobody@example.com", testPassword},
+		{"user without password", "nopass@example.com", testPassword},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: tt.email, Password: tt.password})
+			wantCode(t, err, codes.Unauthenticated)
+			// Pesan sama untuk semua kasus: email terdaftar tidak bisa ditebak
+			if msg := status.Convert(err).Message(); msg != "invalid email or password" {
+				t.Fatalf("message = %q", msg)
+			}
+		})
+	}
+}
+
+func TestAuthenticateSuspendedAndDisabled(t *testing.T) {
+	s, _ := newTestServer(t, nil, WithTokenIssuer(fakeIssuer))
+	createUserWithPassword(t, s, "sus@example.com", testPassword, pb.UserStatus_USER_STATUS_SUSPENDED)
+	_, err := s.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "sus@example.com", Password: testPassword})
+	wantCode(t, err, codes.PermissionDenied)
+
+	disabled, _ := newTestServer(t, nil)
+	_, err = disabled.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "sus@example.com", Password: testPassword})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestCreateUserRejectsShortPassword(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com", Password: "short"})
+	wantCode(t, err, codes.InvalidArgument)
+	if strings.Contains(err.Error(), "short") {
+		t.Fatalf("error message contains the password: %v", err)
+	}
+}
+
+func TestPasswordHashNeverInResponseOrLog(t *testing.T) {
+	var out bytes.Buffer
+	log.SetOutput(&out)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	s, memory := newTestServer(t, nil, WithTokenIssuer(fakeIssuer))
+	user := createUserWithPassword(t, s, "alice@example.com", testPassword, pb.UserStatus_USER_STATUS_UNSPECIFIED)
+	hash, _ := memory.GetPasswordHash(context.Background(), user.Id)
+	got, err := s.GetUser(context.Background(), &pb.GetUserRequest{Id: user.Id})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	s.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "alice@example.com", Password: testPassword})
+	s.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "alice@example.com", Password: "wrong password"})
+
+	for _, u := range []*pb.User{user, got.User} {
+		body, _ := protojson.Marshal(u)
+		if strings.Contains(string(body), hash) || strings.Contains(string(body), testPassword) {
+			t.Fatalf("response user contains password material: %s", body)
+		}
+	}
+	for _, secret := range []string{hash, testPassword, "wrong password"} {
+		if strings.Contains(out.String(), secret) {
+			t.Fatalf("log contains %q:\n%s", secret, out.String())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc"
+)
+
+// batchStream adalah client stream palsu untuk BatchCreateUsers: Recv membaca reqs lalu io.EOF
+type batchStream struct {
+	grpc.ServerStream
+	reqs []*pb.CreateUserRequest
+	resp *pb.BatchCreateUsersResponse
+}
+
+func (b *batchStream) Context() context.Context { return context.Background() }
+
+func (b *batchStream) Recv() (*pb.CreateUserRequest, error) {
+	if len(b.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := b.reqs[0]
+	b.reqs = b.reqs[1:]
+	return req, nil
+}
+
+func (b *batchStream) SendAndClose(resp *pb.BatchCreateUsersResponse) error {
+	b.resp = resp
+	return nil
+}
+
+func TestBatchCreateUsersThousand(t *testing.T) {
+	s, memory := newTestServer(t, nil)
+
+	// 1000 item: setiap item ke-100 email-nya tidak valid, item terakhir duplikat item pertama
+	stream := &batchStream{}
+	for i := 0; i < 1000; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		switch {
+		case i == 999:
+			email = "user0@example.com"
+		case i%100 == 50:
+			email = "not-an-email"
+		}
+		stream.reqs = append(stream.reqs, &pb.CreateUserRequest{Name: fmt.Sprintf("User %d", i), Email: email})
+	}
+
+	if err := s.BatchCreateUsers(stream); err != nil {
+		t.Fatalf("BatchCreateUsers: %v", err)
+	}
+	resp := stream.resp
+	if resp.Created != 989 || resp.Failed != 11 {
+		t.Fatalf("created = %d, failed = %d; want 989, 11", resp.Created, resp.Failed)
+	}
+	if len(resp.Ids) != 989 || len(resp.Errors) != 11 {
+		t.Fatalf("ids = %d, errors = %d; want 989, 11", len(resp.Ids), len(resp.Errors))
+	}
+	if first, last := resp.Errors[0], resp.Errors[10]; first.Index != 50 || last.Index != 999 || first.Message == "" {
+		t.Fatalf("errors = %v ... %v, want index 50 first and 999 (duplicate) last", first, last)
+	}
+	if n, _ := memory.Count(context.Background()); n != 989 {
+		t.Fatalf("store has %d users, want 989", n)
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+)
+
+func countUsers(t *testing.T, s *UserServer, req *pb.CountUsersRequest) int64 {
+	t.Helper()
+	resp, err := s.CountUsers(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CountUsers: %v", err)
+	}
+	return resp.Count
+}
+
+func TestCountUsersSeeded(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := []*pb.User{
+		seedUser("u1", "u1@example.com", base),
+		seedUser("u2", "u2@example.com", base),
+		seedUser("u3", "u3@example.com", base),
+	}
+	users[2].Status = pb.UserStatus_USER_STATUS_PENDING
+	s, _ := newTestServer(t, users, WithSoftDelete())
+
+	if got := countUsers(t, s, &pb.CountUsersRequest{}); got != 3 {
+		t.Fatalf("count = %d, want 3", got)
+	}
+	if got := countUsers(t, s, &pb.CountUsersRequest{Status: pb.UserStatus_USER_STATUS_ACTIVE}); got != 2 {
+		t.Fatalf("active count = %d, want 2", got)
+	}
+
+	createUser(t, s, "New", "new@example.com")
+	if got := countUsers(t, s, &pb.CountUsersRequest{}); got != 4 {
+		t.Fatalf("count after create = %d, want 4", got)
+	}
+
+	// User soft-deleted tidak ikut dihitung
+	if _, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if got := countUsers(t, s, &pb.CountUsersRequest{}); got != 3 {
+		t.Fatalf("count after soft delete = %d, want 3", got)
+	}
+	if got := countUsers(t, s, &pb.CountUsersRequest{Status: pb.UserStatus_USER_STATUS_ACTIVE}); got != 2 {
+		t.Fatalf("active count after soft delete = %d, want 2", got)
+	}
+}
+
+func TestCountUsersEmpty(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	if got := countUsers(t, s, &pb.CountUsersRequest{}); got != 0 {
+		t.Fatalf("count = %d, want 0", got)
+	}
+}
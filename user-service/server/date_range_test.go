@@ -0,0 +1,78 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func listByDate(t *testing.T, s *UserServer, from, to time.Time, limit int32) ([]string, error) {
+	t.Helper()
+	stream := newStreamRecorder[pb.UserResponse](context.Background())
+	err := s.ListUsersByDateRange(&pb.DateRangeRequest{From: timestamppb.New(from), To: timestamppb.New(to), Limit: limit}, stream)
+	var ids []string
+	for _, resp := range stream.sent {
+		ids = append(ids, resp.User.Id)
+	}
+	return ids, err
+}
+
+func TestListUsersByDateRangeBoundaries(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("before", "before@example.com", base.Add(-time.Second)),
+		seedUser("at-from", "from@example.com", base),
+		seedUser("middle-b", "mb@example.com", base.Add(time.Hour)),
+		seedUser("middle-a", "ma@example.com", base.Add(time.Hour)),
+		seedUser("at-to", "to@example.com", base.Add(2*time.Hour)),
+		seedUser("after", "after@example.com", base.Add(2*time.Hour+time.Second)),
+	})
+
+	// from dan to sama-sama inklusif; urut created_at, ID sebagai tie-breaker
+	ids, err := listByDate(t, s, base, base.Add(2*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("ListUsersByDateRange: %v", err)
+	}
+	if want := []string{"at-from", "middle-a", "middle-b", "at-to"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+
+	// from == to → hanya user yang dibuat tepat pada instant itu
+	if ids, _ := listByDate(t, s, base, base, 0); !reflect.DeepEqual(ids, []string{"at-from"}) {
+		t.Fatalf("single-instant range = %v, want [at-from]", ids)
+	}
+
+	if ids, _ := listByDate(t, s, base, base.Add(2*time.Hour), 2); !reflect.DeepEqual(ids, []string{"at-from", "middle-a"}) {
+		t.Fatalf("limited range = %v, want first 2 by creation time", ids)
+	}
+}
+
+func TestListUsersByDateRangeEmpty(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(t, []*pb.User{seedUser("u1", "u1@example.com", base)})
+
+	ids, err := listByDate(t, s, base.Add(time.Hour), base.Add(2*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("empty range: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Fatalf("empty range returned %v", ids)
+	}
+}
+
+func TestListUsersByDateRangeInvalid(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	now := time.Now()
+
+	_, err := listByDate(t, s, now, now.Add(-time.Second), 0)
+	wantCode(t, err, codes.InvalidArgument)
+
+	err = s.ListUsersByDateRange(&pb.DateRangeRequest{From: timestamppb.New(now)}, newStreamRecorder[pb.UserResponse](context.Background()))
+	wantCode(t, err, codes.InvalidArgument)
+}
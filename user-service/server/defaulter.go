@@ -0,0 +1,57 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	pb "user-service/proto/user"
+)
+
+// UserDefaulter mengisi field CreateUserRequest yang tidak diisi client sesuai policy deployment
+// Dijalankan di CreateUser SEBELUM validasi, jadi semua aturan default ada di 1 tempat
+// (bukan tersebar di handler). Implementasi tidak boleh menimpa field yang SUDAH diisi
+type UserDefaulter interface {
+	Default(req *pb.CreateUserRequest)
+}
+
+// NoopDefaulter tidak mengubah apa-apa (default)
+type NoopDefaulter struct{}
+
+func (NoopDefaulter) Default(req *pb.CreateUserRequest) {}
+
+// StaticDefaulter mengisi age/status dengan nilai tetap dari konfigurasi
+// Nilai nol (Age 0, Status UNSPECIFIED) berarti field tersebut tidak di-default
+type StaticDefaulter struct {
+	Age    int32
+	Status pb.UserStatus
+}
+
+func (d StaticDefaulter) Default(req *pb.CreateUserRequest) {
+	if req.Age == 0 {
+		req.Age = d.Age
+	}
+	if req.Status == pb.UserStatus_USER_STATUS_UNSPECIFIED {
+		req.Status = d.Status
+	}
+}
+
+// NewStaticDefaulter membuat StaticDefaulter dari nilai config
+// statusName case-insensitive tanpa prefix (contoh: "pending"), kosong = tidak di-default
+func NewStaticDefaulter(age int, statusName string) (StaticDefaulter, error) {
+	d := StaticDefaulter{Age: int32(age)}
+	if statusName != "" {
+		v, ok := pb.UserStatus_value["USER_STATUS_"+strings.ToUpper(statusName)]
+		if !ok {
+			return StaticDefaulter{}, fmt.Errorf("unknown user status %q", statusName)
+		}
+		d.Status = pb.UserStatus(v)
+	}
+	return d, nil
+}
+
+// WithDefaulter memasang UserDefaulter yang dipakai CreateUser
+func WithDefaulter(d UserDefaulter) Option {
+	return func(s *UserServer) {
+		s.defaulter = d
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+// defaultAgeDefaulter adalah defaulter custom: age kosong diisi nilai tetap
+type defaultAgeDefaulter struct{ age int32 }
+
+func (d defaultAgeDefaulter) Default(req *pb.CreateUserRequest) {
+	if req.Age == 0 {
+		req.Age = d.age
+	}
+}
+
+func TestCustomDefaulterSetsDefaultAge(t *testing.T) {
+	s, _ := newTestServer(t, nil, WithDefaulter(defaultAgeDefaulter{age: 18}))
+
+	resp, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if resp.User.Age != 18 {
+		t.Fatalf("age = %d, want default 18", resp.User.Age)
+	}
+
+	// Field yang sudah diisi client tidak ditimpa
+	resp, err = s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Bob", Email: "bob@example.com", Age: 42})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if resp.User.Age != 42 {
+		t.Fatalf("age = %d, want client value 42", resp.User.Age)
+	}
+}
+
+func TestDefaulterRunsBeforeValidation(t *testing.T) {
+	// Nilai default juga divalidasi: default di luar range → InvalidArgument
+	s, _ := newTestServer(t, nil, WithDefaulter(defaultAgeDefaulter{age: maxAge + 1}))
+
+	_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com"})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestNoopDefaulterIsDefault(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+
+	resp, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Alice", Email: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if resp.User.Age != 0 {
+		t.Fatalf("age = %d, want 0 without a defaulter", resp.User.Age)
+	}
+}
+
+func TestNewStaticDefaulter(t *testing.T) {
+	d, err := NewStaticDefaulter(21, "Pending")
+	if err != nil {
+		t.Fatalf("NewStaticDefaulter: %v", err)
+	}
+	req := &pb.CreateUserRequest{}
+	d.Default(req)
+	if req.Age != 21 || req.Status != pb.UserStatus_USER_STATUS_PENDING {
+		t.Fatalf("defaulted request = age %d, status %v; want 21, PENDING", req.Age, req.Status)
+	}
+
+	if _, err := NewStaticDefaulter(0, "banned"); err == nil {
+		t.Fatal("unknown status: want error")
+	}
+}
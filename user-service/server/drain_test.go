@@ -0,0 +1,112 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// drainAfterFirstSend meniru SIGTERM di tengah stream: flag draining di-set
+// tepat setelah message pertama terkirim ke client
+type drainAfterFirstSend struct {
+	grpc.ServerStream
+	draining *atomic.Bool
+}
+
+func (s *drainAfterFirstSend) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	s.draining.Store(true)
+	return err
+}
+
+// startDrainingServer menjalankan UserServer lewat gRPC sungguhan, return client-nya
+func startDrainingServer(t *testing.T, s *UserServer) pb.UserServiceClient {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	grpcServer := grpc.NewServer(grpc.StreamInterceptor(
+		func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &drainAfterFirstSend{ServerStream: ss, draining: s.DrainingFlag()})
+		}))
+	pb.RegisterUserServiceServer(grpcServer, s)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewUserServiceClient(conn)
+}
+
+func TestListUsersStopsWithUnavailableWhileDraining(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("u1", "u1@example.com", base),
+		seedUser("u2", "u2@example.com", base.Add(time.Hour)),
+		seedUser("u3", "u3@example.com", base.Add(2*time.Hour)),
+	})
+	client := startDrainingServer(t, s)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.ListUsers(ctx, &pb.ListUsersRequest{})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+
+	var received []string
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				t.Fatalf("stream ended cleanly after %v, want shutdown status", received)
+			}
+			if st := status.Convert(err); st.Code() != codes.Unavailable || st.Message() != "server shutting down" {
+				t.Fatalf("stream err = %v, want Unavailable \"server shutting down\"", err)
+			}
+			break
+		}
+		received = append(received, resp.User.Id)
+	}
+	// Message yang sudah terkirim sebelum drain tetap sampai, sisanya tidak dikirim
+	if len(received) != 1 || received[0] != "u1" {
+		t.Fatalf("received users = %v, want only u1 before the shutdown status", received)
+	}
+}
+
+func TestListUsersChecksDrainingFlag(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("u1", "u1@example.com", base),
+		seedUser("u2", "u2@example.com", base.Add(time.Hour)),
+	})
+
+	stream := newStreamRecorder[pb.UserResponse](context.Background())
+	if err := s.ListUsers(&pb.ListUsersRequest{}, stream); err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if len(stream.sent) != 2 {
+		t.Fatalf("sent %d users, want 2", len(stream.sent))
+	}
+
+	s.DrainingFlag().Store(true)
+	stream = newStreamRecorder[pb.UserResponse](context.Background())
+	if err := s.ListUsers(&pb.ListUsersRequest{}, stream); status.Code(err) != codes.Unavailable || len(stream.sent) != 0 {
+		t.Fatalf("ListUsers while draining: err = %v, sent %d; want Unavailable before any user", err, len(stream.sent))
+	}
+}
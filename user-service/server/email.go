@@ -0,0 +1,87 @@
+package server
+
+import (
+	"strings"
+)
+
+// EmailCanonicalizer mengubah email menjadi bentuk kanonik untuk uniqueness index
+// Contoh (rule default): "A.B+promo@GoogleMail.com" → "ab@gmail.com"
+// Email asli tetap disimpan di User.Email untuk ditampilkan
+type EmailCanonicalizer struct {
+	ignoreDots    map[string]bool   // Domain yang mengabaikan titik di local part (gmail)
+	stripPlus     map[string]bool   // Domain yang mendukung plus-addressing (user+tag@...)
+	stripPlusAll  bool              // "*" di daftar stripPlus = semua domain
+	domainAliases map[string]string // Domain alias → domain utama (googlemail.com → gmail.com)
+}
+
+// NewEmailCanonicalizer membuat canonicalizer dari daftar rule per provider
+// aliases berformat "alias=domain" (contoh: "googlemail.com=gmail.com")
+// Rule dots/plus dicocokkan dengan domain SETELAH alias di-resolve
+func NewEmailCanonicalizer(ignoreDotsDomains, stripPlusDomains, aliases []string) *EmailCanonicalizer {
+	c := &EmailCanonicalizer{
+		ignoreDots:    make(map[string]bool),
+		stripPlus:     make(map[string]bool),
+		domainAliases: make(map[string]string),
+	}
+	for _, d := range ignoreDotsDomains {
+		c.ignoreDots[strings.ToLower(d)] = true
+	}
+	for _, d := range stripPlusDomains {
+		if d == "*" {
+			c.stripPlusAll = true
+			continue
+		}
+		c.stripPlus[strings.ToLower(d)] = true
+	}
+	for _, pair := range aliases {
+		if alias, domain, ok := strings.Cut(pair, "="); ok {
+			c.domainAliases[strings.ToLower(alias)] = strings.ToLower(domain)
+		}
+	}
+	return c
+}
+
+// Canonical return bentuk kanonik email
+// Email tanpa "@" dikembalikan apa adanya (lowercase) — validasi format bukan tugas fungsi ini
+func (c *EmailCanonicalizer) Canonical(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+
+	if main, ok := c.domainAliases[domain]; ok {
+		domain = main
+	}
+	if c.stripPlusAll || c.stripPlus[domain] {
+		local, _, _ = strings.Cut(local, "+")
+	}
+	if c.ignoreDots[domain] {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	return local + "@" + domain
+}
+
+// emailKey return key email index untuk uniqueness:
+// bentuk kanonik kalau canonicalization aktif, selain itu email lowercase tanpa spasi
+// (A@X.co dan a@x.co selalu dianggap email yang sama)
+func (s *UserServer) emailKey(email string) string {
+	if s.canonicalizer != nil {
+		return s.canonicalizer.Canonical(email)
+	}
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// Option mengkonfigurasi UserServer saat dibuat (functional options)
+type Option func(*UserServer)
+
+// WithEmailCanonicalizer mengaktifkan uniqueness email berdasarkan bentuk kanonik
+// Dua email yang kanoniknya sama dianggap akun yang sama (CreateUser → AlreadyExists)
+func WithEmailCanonicalizer(c *EmailCanonicalizer) Option {
+	return func(s *UserServer) {
+		s.canonicalizer = c
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+func gmailCanonicalizer() *EmailCanonicalizer {
+	return NewEmailCanonicalizer([]string{"gmail.com"}, []string{"gmail.com"}, []string{"googlemail.com=gmail.com"})
+}
+
+func TestEmailCanonical(t *testing.T) {
+	c := gmailCanonicalizer()
+	tests := []struct{ email, want string }{
+		{"a.b@gmail.com", "ab@gmail.com"},
+		{"AB+promo@Gmail.com", "ab@gmail.com"},
+		{"a.b+x@googlemail.com", "ab@gmail.com"},
+		{" a.b@example.com ", "a.b@example.com"}, // Domain lain: titik & plus tidak diubah
+		{"a+b@example.com", "a+b@example.com"},
+		{"not-an-email", "not-an-email"},
+	}
+	for _, tt := range tests {
+		if got := c.Canonical(tt.email); got != tt.want {
+			t.Errorf("Canonical(%q) = %q, want %q", tt.email, got, tt.want)
+		}
+	}
+
+	if got := NewEmailCanonicalizer(nil, []string{"*"}, nil).Canonical("a+b@example.com"); got != "a@example.com" {
+		t.Errorf("strip plus for all domains: got %q", got)
+	}
+}
+
+func TestCanonicalEmailConflict(t *testing.T) {
+	s, _ := newTestServer(t, nil, WithEmailCanonicalizer(gmailCanonicalizer()))
+
+	first := createUser(t, s, "Alice", "a.b@gmail.com")
+	if first.Email != "a.b@gmail.com" || first.CanonicalEmail != "ab@gmail.com" {
+		t.Fatalf("email = %q, canonical = %q; want display email kept and canonical ab@gmail.com", first.Email, first.CanonicalEmail)
+	}
+
+	for _, email := range []string{"ab@gmail.com", "A.B+news@googlemail.com"} {
+		_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Dup", Email: email, Age: 30})
+		wantCode(t, err, codes.AlreadyExists)
+	}
+}
+
+func TestCanonicalEmailOffByDefault(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+
+	createUser(t, s, "Alice", "a.b@gmail.com")
+	second := createUser(t, s, "Bob", "ab@gmail.com") // Tanpa canonicalization → akun berbeda
+	if second.CanonicalEmail != "" {
+		t.Fatalf("canonical email = %q, want empty when canonicalization is off", second.CanonicalEmail)
+	}
+
+	// Beda huruf besar/kecil saja tetap dianggap email yang sama
+	_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Dup", Email: "A.B@gmail.com", Age: 30})
+	wantCode(t, err, codes.AlreadyExists)
+}
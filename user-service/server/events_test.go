@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	pb "user-service/proto/user"
+)
+
+func TestMutationsPublishEvents(t *testing.T) {
+	events := &recordingPublisher{}
+	s, _ := newTestServer(t, nil, WithEventPublisher(events))
+	ctx := context.Background()
+
+	user := createUser(t, s, "Alice", "alice@example.com")
+	if _, err := s.UpdateUser(ctx, &pb.UpdateUserRequest{Id: user.Id, Name: "Alice B", Email: user.Email, Age: 31, ExpectedVersion: user.Version}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+	// Update yang gagal tidak boleh menghasilkan event
+	s.UpdateUser(ctx, &pb.UpdateUserRequest{Id: user.Id, Name: "Stale", Email: user.Email, ExpectedVersion: user.Version})
+	if _, err := s.DeleteUser(ctx, &pb.DeleteUserRequest{Id: user.Id}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	var types []pb.UserEventType
+	for _, event := range events.events {
+		types = append(types, event.Type)
+		if event.User.Id != user.Id {
+			t.Fatalf("%v event for user %q, want %q", event.Type, event.User.Id, user.Id)
+		}
+	}
+	want := []pb.UserEventType{
+		pb.UserEventType_USER_EVENT_TYPE_CREATED,
+		pb.UserEventType_USER_EVENT_TYPE_UPDATED,
+		pb.UserEventType_USER_EVENT_TYPE_DELETED,
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Fatalf("events = %v, want %v", types, want)
+	}
+	if got := events.events[1].User.Name; got != "Alice B" {
+		t.Fatalf("updated event carries name %q, want the new value", got)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"strings"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// userFields adalah descriptor message User, sumber nama field yang valid untuk field_mask
+var userFields = (&pb.User{}).ProtoReflect().Descriptor().Fields()
+
+// validateFieldMask cek semua path di field_mask adalah nama field User
+// Path yang tidak dikenal dikumpulkan semua, supaya client bisa memperbaiki sekaligus
+func validateFieldMask(paths []string) error {
+	var unknown []string
+	for _, path := range paths {
+		if userFields.ByName(protoreflect.Name(path)) == nil {
+			unknown = append(unknown, path)
+		}
+	}
+	if len(unknown) > 0 {
+		return status.Errorf(codes.InvalidArgument, "unknown field_mask paths: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// applyFieldMask mengembalikan COPY user yang hanya berisi field di paths (mask kosong = user apa adanya)
+// Tidak boleh clear in-place: user adalah pointer milik store yang juga dibaca RPC lain
+func applyFieldMask(user *pb.User, paths []string) *pb.User {
+	if len(paths) == 0 {
+		return user
+	}
+
+	keep := make(map[protoreflect.Name]bool, len(paths))
+	for _, path := range paths {
+		keep[protoreflect.Name(path)] = true
+	}
+
+	masked := proto.Clone(user).(*pb.User)
+	m := masked.ProtoReflect()
+	for i := 0; i < userFields.Len(); i++ {
+		if field := userFields.Get(i); !keep[field.Name()] {
+			m.Clear(field)
+		}
+	}
+	return masked
+}
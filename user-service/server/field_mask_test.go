@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGetUserFieldMask(t *testing.T) {
+	stored := seedUser("u1", "alice@example.com", time.Now())
+	s, _ := newTestServer(t, []*pb.User{stored})
+
+	// Mask kosong = semua field
+	resp, err := s.GetUser(context.Background(), &pb.GetUserRequest{Id: "u1"})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if !proto.Equal(resp.User, stored) {
+		t.Fatalf("empty mask returned %v, want full user %v", resp.User, stored)
+	}
+
+	resp, err = s.GetUser(context.Background(), &pb.GetUserRequest{Id: "u1", FieldMask: []string{"name", "email"}})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	want := &pb.User{Name: stored.Name, Email: stored.Email}
+	if !proto.Equal(resp.User, want) {
+		t.Fatalf("masked user = %v, want %v", resp.User, want)
+	}
+
+	// Mask tidak mengubah user yang tersimpan
+	resp, _ = s.GetUser(context.Background(), &pb.GetUserRequest{Id: "u1"})
+	if resp.User.Id != "u1" || resp.User.Age != stored.Age {
+		t.Fatalf("stored user changed by field mask: %v", resp.User)
+	}
+}
+
+func TestGetUserFieldMaskUnknownField(t *testing.T) {
+	s, _ := newTestServer(t, []*pb.User{seedUser("u1", "alice@example.com", time.Now())})
+
+	// Semua path yang salah disebut, dan tetap InvalidArgument walaupun user tidak ada
+	for _, id := range []string{"u1", "missing"} {
+		_, err := s.GetUser(context.Background(), &pb.GetUserRequest{Id: id, FieldMask: []string{"name", "password", "nickname"}})
+		wantCode(t, err, codes.InvalidArgument)
+		msg := status.Convert(err).Message()
+		if !strings.Contains(msg, "password") || !strings.Contains(msg, "nickname") || strings.Contains(msg, "name,") {
+			t.Fatalf("message = %q, want only the unknown paths", msg)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"errors"
+	"strings"
+
+	pb "user-service/proto/user"
+)
+
+// userFilter adalah filter pencarian ListUsers & CountUsers (semua kondisi digabung dengan AND)
+// Field kosong / nil = tidak memfilter
+type userFilter struct {
+	nameContains string // Sudah lowercase
+	emailDomain  string // Sudah lowercase, tanpa "@" di depan
+	minAge       *int32
+	maxAge       *int32
+}
+
+// newUserFilter memvalidasi & menormalisasi field filter dari request
+func newUserFilter(nameContains, emailDomain string, minAge, maxAge *int32) (userFilter, error) {
+	if minAge != nil && *minAge < 0 {
+		return userFilter{}, errors.New("min_age must not be negative")
+	}
+	if maxAge != nil && *maxAge < 0 {
+		return userFilter{}, errors.New("max_age must not be negative")
+	}
+	if minAge != nil && maxAge != nil && *minAge > *maxAge {
+		return userFilter{}, errors.New("min_age must be less than or equal to max_age")
+	}
+
+	return userFilter{
+		nameContains: strings.ToLower(strings.TrimSpace(nameContains)),
+		// "@example.com" juga diterima, sama dengan "example.com"
+		emailDomain: strings.ToLower(strings.TrimPrefix(strings.TrimSpace(emailDomain), "@")),
+		minAge:      minAge,
+		maxAge:      maxAge,
+	}, nil
+}
+
+// empty = tidak ada filter sama sekali (semua user cocok)
+func (f userFilter) empty() bool {
+	return f.nameContains == "" && f.emailDomain == "" && f.minAge == nil && f.maxAge == nil
+}
+
+// matches cek apakah user lolos semua kondisi filter
+func (f userFilter) matches(user *pb.User) bool {
+	if f.nameContains != "" && !strings.Contains(strings.ToLower(user.Name), f.nameContains) {
+		return false
+	}
+	if f.emailDomain != "" {
+		at := strings.LastIndex(user.Email, "@")
+		if at < 0 || strings.ToLower(user.Email[at+1:]) != f.emailDomain {
+			return false
+		}
+	}
+	if f.minAge != nil && user.Age < *f.minAge {
+		return false
+	}
+	if f.maxAge != nil && user.Age > *f.maxAge {
+		return false
+	}
+	return true
+}
+
+// apply mengembalikan user yang cocok (slice baru, urutan dipertahankan)
+func (f userFilter) apply(users []*pb.User) []*pb.User {
+	if f.empty() {
+		return users
+	}
+	out := make([]*pb.User, 0, len(users))
+	for _, user := range users {
+		if f.matches(user) {
+			out = append(out, user)
+		}
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+func filterUsers() []*pb.User {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := []*pb.User{
+		seedUser("u1", "alice@example.com", base),
+		seedUser("u2", "albert@corp.io", base.Add(time.Hour)),
+		seedUser("u3", "bob@Example.com", base.Add(2*time.Hour)),
+		seedUser("u4", "carol@example.com", base.Add(3*time.Hour)),
+	}
+	users[0].Name, users[0].Age = "Alice", 17
+	users[1].Name, users[1].Age = "Albert", 45
+	users[2].Name, users[2].Age = "Bob", 30
+	users[3].Name, users[3].Age = "Carol Malone", 22
+	return users
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestListUsersFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.ListUsersRequest
+		want []string
+	}{
+		{"no filter", &pb.ListUsersRequest{}, []string{"u1", "u2", "u3", "u4"}},
+		{"name case-insensitive", &pb.ListUsersRequest{NameContains: "AL"}, []string{"u1", "u2", "u4"}},
+		{"email domain", &pb.ListUsersRequest{EmailDomain: "example.com"}, []string{"u1", "u3", "u4"}},
+		{"email domain with @", &pb.ListUsersRequest{EmailDomain: "@corp.io"}, []string{"u2"}},
+		{"min age inclusive", &pb.ListUsersRequest{MinAge: int32Ptr(30)}, []string{"u2", "u3"}},
+		{"max age inclusive", &pb.ListUsersRequest{MaxAge: int32Ptr(22)}, []string{"u1", "u4"}},
+		{"age range", &pb.ListUsersRequest{MinAge: int32Ptr(18), MaxAge: int32Ptr(30)}, []string{"u3", "u4"}},
+		{"name + domain + age", &pb.ListUsersRequest{NameContains: "al", EmailDomain: "example.com", MinAge: int32Ptr(18)}, []string{"u4"}},
+		{"filter + limit", &pb.ListUsersRequest{NameContains: "al", Limit: 2}, []string{"u1", "u2"}},
+		{"no match", &pb.ListUsersRequest{NameContains: "zed"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestServer(t, filterUsers())
+			ids, err := listUserIDs(t, s, tt.req)
+			if err != nil {
+				t.Fatalf("ListUsers: %v", err)
+			}
+			if !reflect.DeepEqual(ids, tt.want) {
+				t.Fatalf("ids = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountUsersAppliesFilters(t *testing.T) {
+	s, _ := newTestServer(t, filterUsers())
+	req := &pb.CountUsersRequest{NameContains: "al", EmailDomain: "example.com"}
+	if got := countUsers(t, s, req); got != 2 {
+		t.Fatalf("count = %d, want 2", got)
+	}
+}
+
+func TestListUsersRejectsInvalidFilter(t *testing.T) {
+	s, _ := newTestServer(t, filterUsers())
+	for _, req := range []*pb.ListUsersRequest{
+		{MinAge: int32Ptr(-1)},
+		{MaxAge: int32Ptr(-1)},
+		{MinAge: int32Ptr(40), MaxAge: int32Ptr(20)},
+	} {
+		_, err := listUserIDs(t, s, req)
+		wantCode(t, err, codes.InvalidArgument)
+	}
+}
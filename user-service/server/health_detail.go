@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	pb "user-service/proto/user"
+)
+
+// componentCheckTimeout adalah batas waktu 1 health check komponen
+// Komponen yang hang tidak boleh membuat RPC HealthDetail ikut hang
+const componentCheckTimeout = 2 * time.Second
+
+// componentCheck adalah health check untuk 1 komponen (store, cache, broker, dll)
+// Dependency baru cukup menambahkan check-nya di componentChecks()
+type componentCheck struct {
+	name  string
+	check func(ctx context.Context) error // nil = komponen tidak dikonfigurasi ("disabled")
+}
+
+// healthTracker menyimpan error terakhir tiap komponen antar pemanggilan HealthDetail
+type healthTracker struct {
+	mu      sync.Mutex
+	lastErr map[string]string
+}
+
+func (t *healthTracker) record(name string, err error) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastErr == nil {
+		t.lastErr = make(map[string]string)
+	}
+	if err != nil {
+		t.lastErr[name] = err.Error()
+	}
+	return t.lastErr[name]
+}
+
+// componentChecks return daftar komponen yang dicek oleh HealthDetail
+func (s *UserServer) componentChecks() []componentCheck {
+	return []componentCheck{
+		{name: "store", check: s.checkStore},
+	}
+}
+
+// checkStore memastikan store bisa dibaca (read lock tidak macet)
+// dan, untuk store berbasis database, koneksinya masih hidup
+func (s *UserServer) checkStore(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.mu.RLock()
+		s.mu.RUnlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("store lock not acquired: %w", ctx.Err())
+	}
+
+	if pinger, ok := s.store.(interface{ Ping(context.Context) error }); ok {
+		if err := pinger.Ping(ctx); err != nil {
+			return fmt.Errorf("store ping failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// HealthDetail mengimplementasikan RPC diagnostic per komponen
+// Semua check jalan paralel, masing-masing dengan timeout sendiri
+func (s *UserServer) HealthDetail(ctx context.Context, req *pb.HealthDetailRequest) (*pb.HealthDetailResponse, error) {
+	checks := s.componentChecks()
+	components := make([]*pb.ComponentHealth, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c componentCheck) {
+			defer wg.Done()
+			components[i] = s.runCheck(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return &pb.HealthDetailResponse{Components: components}, nil
+}
+
+// runCheck menjalankan 1 check dengan timeout dan mencatat latency + error terakhir
+func (s *UserServer) runCheck(ctx context.Context, c componentCheck) *pb.ComponentHealth {
+	if c.check == nil {
+		return &pb.ComponentHealth{Name: c.name, Status: "disabled"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, componentCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	errCh := make(chan error, 1) // Buffered: goroutine check yang telat tidak bocor selamanya
+	go func() { errCh <- c.check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", componentCheckTimeout)
+	}
+
+	result := &pb.ComponentHealth{
+		Name:      c.name,
+		Status:    "ok",
+		LatencyMs: time.Since(start).Milliseconds(),
+		LastError: s.health.record(c.name, err),
+	}
+	if err != nil {
+		result.Status = "error"
+	}
+	return result
+}
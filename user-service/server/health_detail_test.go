@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+	"user-service/store"
+)
+
+// pingStore adalah MemoryStore dengan Ping yang bisa diinjeksi gagal atau hang
+type pingStore struct {
+	*store.MemoryStore
+	fail atomic.Bool
+	hang bool
+}
+
+func (s *pingStore) Ping(ctx context.Context) error {
+	if s.hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if s.fail.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func storeHealth(t *testing.T, ctx context.Context, s *UserServer) *pb.ComponentHealth {
+	t.Helper()
+	resp, err := s.HealthDetail(ctx, &pb.HealthDetailRequest{})
+	if err != nil {
+		t.Fatalf("HealthDetail: %v", err)
+	}
+	for _, c := range resp.Components {
+		if c.Name == "store" {
+			return c
+		}
+	}
+	t.Fatalf("store component missing: %v", resp.Components)
+	return nil
+}
+
+func TestHealthDetailStoreFailure(t *testing.T) {
+	st := &pingStore{MemoryStore: store.NewMemoryStore()}
+	s := NewUserServer(st)
+
+	if c := storeHealth(t, context.Background(), s); c.Status != "ok" || c.LastError != "" {
+		t.Fatalf("healthy store = %+v, want ok without last error", c)
+	}
+
+	st.fail.Store(true)
+	c := storeHealth(t, context.Background(), s)
+	if c.Status != "error" || c.LastError == "" {
+		t.Fatalf("failing store = %+v, want error with last error", c)
+	}
+
+	// Setelah pulih status kembali ok, tapi error terakhir tetap terlihat untuk diagnosis
+	st.fail.Store(false)
+	if recovered := storeHealth(t, context.Background(), s); recovered.Status != "ok" || recovered.LastError != c.LastError {
+		t.Fatalf("recovered store = %+v, want ok with last error %q", recovered, c.LastError)
+	}
+}
+
+func TestHealthDetailHungStoreDoesNotHang(t *testing.T) {
+	s := NewUserServer(&pingStore{MemoryStore: store.NewMemoryStore(), hang: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	c := storeHealth(t, ctx, s)
+	if elapsed := time.Since(start); elapsed > componentCheckTimeout {
+		t.Fatalf("HealthDetail took %s with a hung store", elapsed)
+	}
+	if c.Status != "error" {
+		t.Fatalf("hung store = %+v, want error", c)
+	}
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	pb "user-service/proto/user"
+)
+
+// Field yang bisa dipakai di ListUsersRequest.order_by (tambah suffix "_desc" untuk descending)
+const (
+	orderByCreatedAt = "created_at"
+	orderByName      = "name"
+)
+
+// listOrder adalah hasil parse order_by: field yang diurutkan + arah
+// Urutan selalu total (id sebagai tie-breaker), jadi hasil ListUsers deterministik
+type listOrder struct {
+	field string
+	desc  bool
+}
+
+// parseListOrder membaca order_by dari request (kosong = created_at ascending)
+func parseListOrder(orderBy string) (listOrder, error) {
+	if orderBy == "" {
+		return listOrder{field: orderByCreatedAt}, nil
+	}
+
+	field, desc := strings.CutSuffix(orderBy, "_desc")
+	switch field {
+	case orderByCreatedAt, orderByName:
+		return listOrder{field: field, desc: desc}, nil
+	default:
+		return listOrder{}, fmt.Errorf("invalid order_by %q (want created_at, name, created_at_desc, or name_desc)", orderBy)
+	}
+}
+
+// String mengembalikan bentuk order_by yang kanonik (dipakai di page token)
+func (o listOrder) String() string {
+	if o.desc {
+		return o.field + "_desc"
+	}
+	return o.field
+}
+
+// key mengembalikan nilai field yang diurutkan milik user (disimpan di page token)
+func (o listOrder) key(user *pb.User) string {
+	if o.field == orderByName {
+		return user.Name
+	}
+	return createdAtTime(user).Format(time.RFC3339Nano)
+}
+
+// less membandingkan 2 user sesuai order; id (ascending) sebagai tie-breaker
+func (o listOrder) less(a, b *pb.User) bool {
+	var cmp int
+	if o.field == orderByName {
+		cmp = strings.Compare(a.Name, b.Name)
+	} else {
+		cmp = createdAtTime(a).Compare(createdAtTime(b))
+	}
+
+	if cmp != 0 {
+		return (cmp < 0) != o.desc
+	}
+	return a.Id < b.Id
+}
+
+// createdAtTime return CreatedAt user sebagai time.Time; nil/tidak valid dianggap zero time
+// (tetap bisa dibandingkan, dan selalu muncul paling awal di urutan ascending)
+func createdAtTime(user *pb.User) time.Time {
+	if !user.CreatedAt.IsValid() {
+		return time.Time{}
+	}
+	return user.CreatedAt.AsTime()
+}
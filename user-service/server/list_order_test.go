@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+func listUserIDs(t *testing.T, s *UserServer, req *pb.ListUsersRequest) ([]string, error) {
+	t.Helper()
+	stream := newStreamRecorder[pb.UserResponse](context.Background())
+	err := s.ListUsers(req, stream)
+	var ids []string
+	for _, resp := range stream.sent {
+		ids = append(ids, resp.User.Id)
+	}
+	return ids, err
+}
+
+// orderedUsers: created_at sama untuk b & a (urutan ditentukan id), nama sengaja tidak searah created_at
+func orderedUsers() []*pb.User {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := []*pb.User{
+		seedUser("c", "c@example.com", base),
+		seedUser("b", "b@example.com", base.Add(time.Hour)),
+		seedUser("a", "a@example.com", base.Add(time.Hour)),
+		seedUser("d", "d@example.com", base.Add(2*time.Hour)),
+	}
+	users[0].Name, users[1].Name, users[2].Name, users[3].Name = "Dave", "Carol", "Bob", "Alice"
+	return users
+}
+
+func TestListUsersOrder(t *testing.T) {
+	tests := []struct {
+		orderBy string
+		want    []string
+	}{
+		{"", []string{"c", "a", "b", "d"}}, // Default: created_at, lalu id
+		{"created_at", []string{"c", "a", "b", "d"}},
+		{"created_at_desc", []string{"d", "a", "b", "c"}}, // Tie-breaker id tetap ascending
+		{"name", []string{"d", "a", "b", "c"}},
+		{"name_desc", []string{"c", "b", "a", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.orderBy, func(t *testing.T) {
+			s, _ := newTestServer(t, orderedUsers())
+			// Diulang: map di store tidak boleh membuat urutan berubah antar call
+			for i := 0; i < 5; i++ {
+				ids, err := listUserIDs(t, s, &pb.ListUsersRequest{OrderBy: tt.orderBy})
+				if err != nil {
+					t.Fatalf("ListUsers: %v", err)
+				}
+				if !reflect.DeepEqual(ids, tt.want) {
+					t.Fatalf("order_by %q = %v, want %v", tt.orderBy, ids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListUsersOrderHonorsLimit(t *testing.T) {
+	s, _ := newTestServer(t, orderedUsers())
+	ids, err := listUserIDs(t, s, &pb.ListUsersRequest{OrderBy: "name", Limit: 2})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if want := []string{"d", "a"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ids = %v, want %v", ids, want)
+	}
+}
+
+func TestListUsersInvalidOrder(t *testing.T) {
+	s, _ := newTestServer(t, orderedUsers())
+	_, err := listUserIDs(t, s, &pb.ListUsersRequest{OrderBy: "email"})
+	wantCode(t, err, codes.InvalidArgument)
+}
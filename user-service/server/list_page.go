@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultListPageSize dipakai ListUsersPage kalau page_size tidak diisi
+	defaultListPageSize = 50
+	// maxListPageSize membatasi 1 response unary (beda dengan stream, dikirim sekaligus)
+	maxListPageSize = 1000
+)
+
+// listQuery adalah parameter yang sama antara ListUsers (stream) dan ListUsersPage (unary)
+type listQuery struct {
+	limit          int // 0 = semua
+	pageToken      string
+	orderBy        string
+	filter         userFilter
+	includeDeleted bool
+}
+
+// listPage adalah 1 halaman hasil listQuery
+type listPage struct {
+	users         []*pb.User
+	nextPageToken string // Kosong = halaman terakhir
+	total         int    // Jumlah user yang cocok filter, sebelum pagination
+}
+
+// queryUsers mengambil 1 halaman user sesuai q
+// Error yang dikembalikan sudah berupa gRPC status (InvalidArgument / Internal)
+func (s *UserServer) queryUsers(ctx context.Context, q listQuery) (listPage, error) {
+	order, err := parseListOrder(q.orderBy)
+	if err != nil {
+		return listPage{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	last, err := decodePageToken(order, q.pageToken)
+	if err != nil {
+		return listPage{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Ambil snapshot dari store di bawah read lock, lalu lepas lock sebelum send
+	// (client yang lambat tidak boleh menahan lock)
+	s.mu.RLock()
+	users, err := s.store.List(ctx, 0)
+	s.mu.RUnlock()
+	if err != nil {
+		return listPage{}, s.storeError(err)
+	}
+
+	// Filter diterapkan sebelum pagination, jadi limit & page_token berlaku atas hasil filter
+	if !q.includeDeleted {
+		users = activeUsers(users)
+	}
+	users = q.filter.apply(users)
+	total := len(users)
+
+	// Store tidak menjamin urutan (MemoryStore = map), jadi urutkan sendiri
+	// supaya hasil & halaman stabil antar panggilan,
+	// lalu lewati semua user sampai (dan termasuk) user terakhir milik halaman sebelumnya
+	sort.Slice(users, func(i, j int) bool { return order.less(users[i], users[j]) })
+	if last != nil {
+		start := sort.Search(len(users), func(i int) bool { return order.less(last, users[i]) })
+		users = users[start:]
+	}
+
+	// Limit 0 berarti unlimited
+	page := listPage{users: users, total: total}
+	if q.limit > 0 && len(users) > q.limit {
+		page.users = users[:q.limit]
+		page.nextPageToken = encodePageToken(order, page.users[len(page.users)-1])
+	}
+	return page, nil
+}
+
+// ListUsersPage adalah versi unary ListUsers (Unary RPC)
+// 1 halaman dikirim dalam 1 response, plus total_size untuk UI pagination
+func (s *UserServer) ListUsersPage(ctx context.Context, req *pb.ListUsersPageRequest) (*pb.ListUsersPageResponse, error) {
+	log.Printf("📋 Listing users page with page_size: %d, order_by: %q", req.PageSize, req.OrderBy)
+
+	if req.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultListPageSize
+	}
+	if pageSize > maxListPageSize {
+		pageSize = maxListPageSize
+	}
+
+	filter, err := newUserFilter(req.NameContains, req.EmailDomain, req.MinAge, req.MaxAge)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	page, err := s.queryUsers(ctx, listQuery{
+		limit:          pageSize,
+		pageToken:      req.PageToken,
+		orderBy:        req.OrderBy,
+		filter:         filter,
+		includeDeleted: req.IncludeDeleted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Returning %d of %d users", len(page.users), page.total)
+
+	return &pb.ListUsersPageResponse{
+		Users:         page.users,
+		NextPageToken: page.nextPageToken,
+		TotalSize:     int64(page.total),
+	}, nil
+}
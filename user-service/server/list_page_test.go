@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+func pageUsers(n int) []*pb.User {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var users []*pb.User
+	for i := 1; i <= n; i++ {
+		users = append(users, seedUser(fmt.Sprintf("u%d", i), fmt.Sprintf("u%d@example.com", i), base.Add(time.Duration(i)*time.Minute)))
+	}
+	return users
+}
+
+func TestListUsersPageWalksAllPages(t *testing.T) {
+	s, _ := newTestServer(t, pageUsers(5))
+
+	var pages [][]string
+	token := ""
+	for {
+		resp, err := s.ListUsersPage(context.Background(), &pb.ListUsersPageRequest{PageSize: 2, PageToken: token})
+		if err != nil {
+			t.Fatalf("ListUsersPage: %v", err)
+		}
+		if resp.TotalSize != 5 {
+			t.Fatalf("total_size = %d, want 5", resp.TotalSize)
+		}
+		var ids []string
+		for _, user := range resp.Users {
+			ids = append(ids, user.Id)
+		}
+		pages = append(pages, ids)
+		if resp.NextPageToken == "" {
+			break
+		}
+		token = resp.NextPageToken
+	}
+
+	want := [][]string{{"u1", "u2"}, {"u3", "u4"}, {"u5"}}
+	if !reflect.DeepEqual(pages, want) {
+		t.Fatalf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestListUsersPageEmpty(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+
+	resp, err := s.ListUsersPage(context.Background(), &pb.ListUsersPageRequest{})
+	if err != nil {
+		t.Fatalf("ListUsersPage: %v", err)
+	}
+	if len(resp.Users) != 0 || resp.NextPageToken != "" || resp.TotalSize != 0 {
+		t.Fatalf("empty store: %v, want no users, no token and total 0", resp)
+	}
+}
+
+func TestListUsersPageDefaultsAndLimits(t *testing.T) {
+	s, _ := newTestServer(t, pageUsers(defaultListPageSize+1))
+
+	resp, err := s.ListUsersPage(context.Background(), &pb.ListUsersPageRequest{})
+	if err != nil {
+		t.Fatalf("ListUsersPage: %v", err)
+	}
+	if len(resp.Users) != defaultListPageSize || resp.NextPageToken == "" {
+		t.Fatalf("page_size 0: %d users, token %q; want default %d with a next page", len(resp.Users), resp.NextPageToken, defaultListPageSize)
+	}
+
+	_, err = s.ListUsersPage(context.Background(), &pb.ListUsersPageRequest{PageSize: -1})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = s.ListUsersPage(context.Background(), &pb.ListUsersPageRequest{PageToken: "not-a-token"})
+	wantCode(t, err, codes.InvalidArgument)
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readOnlyMessage harus sama dengan interceptor.ReadOnlyMessage (gateway mengenali error dari pesannya)
+const readOnlyMessage = "service in read-only mode"
+
+// expectedEmailIndex membangun email index dari nol berdasarkan isi store
+// Store adalah source of truth; index hanya turunan
+// Harus dipanggil dengan s.mu (read atau write) sudah di-lock
+func (s *UserServer) expectedEmailIndex(ctx context.Context) (map[string]string, error) {
+	users, err := s.store.List(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]string, len(users))
+	for _, user := range users {
+		index[s.emailKey(user.Email)] = user.Id
+	}
+	return index, nil
+}
+
+// diffEmailIndex membandingkan index saat ini dengan index yang seharusnya
+// Hasil diurutkan berdasarkan key supaya output stabil
+func (s *UserServer) diffEmailIndex(expected map[string]string) []*pb.IndexMismatch {
+	var mismatches []*pb.IndexMismatch
+	for key, id := range s.emailIndex {
+		if expected[key] != id {
+			mismatches = append(mismatches, &pb.IndexMismatch{Index: "email", Key: key, IndexedId: id, ExpectedId: expected[key]})
+		}
+	}
+	for key, id := range expected {
+		if _, ok := s.emailIndex[key]; !ok {
+			mismatches = append(mismatches, &pb.IndexMismatch{Index: "email", Key: key, ExpectedId: id})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Key < mismatches[j].Key })
+	return mismatches
+}
+
+// checkIntegrity scan store dan secondary index, return semua mismatch
+// repair = true → index langsung di-rebuild dari store (self-healing)
+func (s *UserServer) checkIntegrity(ctx context.Context, repair bool) (mismatches []*pb.IndexMismatch, repaired bool, err error) {
+	if !repair {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+		expected, err := s.expectedEmailIndex(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		return s.diffEmailIndex(expected), false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	expected, err := s.expectedEmailIndex(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	mismatches = s.diffEmailIndex(expected)
+	if len(mismatches) > 0 {
+		s.emailIndex = expected
+		repaired = true
+	}
+	return mismatches, repaired, nil
+}
+
+// VerifyIntegrity mengimplementasikan RPC admin untuk cek (dan repair) konsistensi index
+// Juga dipanggil langsung dari main saat startup kalau VERIFY_INTEGRITY_ON_STARTUP aktif
+// repair = true adalah write (index di-rebuild), jadi ditolak selama read-only mode;
+// cek saja (repair = false) tetap boleh. Dicek di sini, bukan di interceptor ReadOnly,
+// karena interceptor hanya melihat nama method
+func (s *UserServer) VerifyIntegrity(ctx context.Context, req *pb.VerifyIntegrityRequest) (*pb.VerifyIntegrityResponse, error) {
+	if req.Repair && s.readOnly.Load() {
+		return nil, status.Error(codes.FailedPrecondition, readOnlyMessage)
+	}
+
+	mismatches, repaired, err := s.checkIntegrity(ctx, req.Repair)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	if len(mismatches) == 0 {
+		log.Println("✅ Integrity check passed: indexes consistent")
+	} else if repaired {
+		log.Printf("🩹 Integrity check found %d index mismatch(es), indexes rebuilt", len(mismatches))
+	} else {
+		log.Printf("⚠️  Integrity check found %d index mismatch(es)", len(mismatches))
+	}
+
+	return &pb.VerifyIntegrityResponse{Mismatches: mismatches, Repaired: repaired}, nil
+}
+
+// Compact mengimplementasikan RPC admin untuk maintenance store
+// Jalan di bawah write lock: semua write lain menunggu sampai rebuild selesai
+func (s *UserServer) Compact(ctx context.Context, req *pb.CompactRequest) (*pb.CompactResponse, error) {
+	log.Println("🧹 Compacting store")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// User soft-deleted sengaja TIDAK di-purge (disimpan untuk audit & RestoreUser),
+	// jadi yang bisa "dibersihkan" hanyalah drift di secondary index
+	expected, err := s.expectedEmailIndex(ctx)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	var stale, missing int32
+	for _, m := range s.diffEmailIndex(expected) {
+		if m.IndexedId != "" {
+			stale++
+		} else {
+			missing++
+		}
+	}
+	s.emailIndex = expected
+
+	log.Printf("✅ Compaction done: %d index entries (%d stale removed, %d missing added)", len(expected), stale, missing)
+
+	return &pb.CompactResponse{
+		PurgedRecords:       0,
+		IndexEntries:        int32(len(expected)),
+		StaleIndexEntries:   stale,
+		MissingIndexEntries: missing,
+	}, nil
+}
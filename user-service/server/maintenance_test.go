@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+// corruptEmailIndex membuat drift: 1 entry basi (user tidak ada) dan 1 entry hilang
+func corruptEmailIndex(s *UserServer, missingEmail string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emailIndex["ghost@example.com"] = "ghost"
+	delete(s.emailIndex, s.emailKey(missingEmail))
+}
+
+func TestCompactRebuildsEmailIndex(t *testing.T) {
+	now := time.Now()
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("u1", "alice@example.com", now),
+		seedUser("u2", "bob@example.com", now),
+	})
+	corruptEmailIndex(s, "bob@example.com")
+
+	resp, err := s.Compact(context.Background(), &pb.CompactRequest{})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if resp.IndexEntries != 2 || resp.StaleIndexEntries != 1 || resp.MissingIndexEntries != 1 {
+		t.Fatalf("Compact = %+v, want 2 entries, 1 stale, 1 missing", resp)
+	}
+
+	// Setelah compaction index konsisten dengan store
+	s.mu.RLock()
+	expected, err := s.expectedEmailIndex(context.Background())
+	mismatches := s.diffEmailIndex(expected)
+	s.mu.RUnlock()
+	if err != nil || len(mismatches) != 0 {
+		t.Fatalf("mismatches after Compact = %v (err: %v)", mismatches, err)
+	}
+
+	// Email yang tadinya hilang dari index kembali dijaga uniqueness-nya,
+	// email dari entry basi bisa dipakai lagi
+	_, err = s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Bob 2", Email: "bob@example.com", Age: 30})
+	wantCode(t, err, codes.AlreadyExists)
+	createUser(t, s, "Ghost", "ghost@example.com")
+}
+
+func TestCompactOnConsistentIndexIsNoop(t *testing.T) {
+	s, _ := newTestServer(t, []*pb.User{seedUser("u1", "alice@example.com", time.Now())})
+
+	resp, err := s.Compact(context.Background(), &pb.CompactRequest{})
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+	if resp.IndexEntries != 1 || resp.StaleIndexEntries != 0 || resp.MissingIndexEntries != 0 {
+		t.Fatalf("Compact = %+v, want 1 entry and no drift", resp)
+	}
+}
+
+func TestRepairBlockedInReadOnlyMode(t *testing.T) {
+	s, _ := newTestServer(t, []*pb.User{seedUser("u1", "alice@example.com", time.Now())})
+	s.ReadOnlyFlag().Store(true)
+
+	_, err := s.VerifyIntegrity(context.Background(), &pb.VerifyIntegrityRequest{Repair: true})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	// Cek tanpa repair tetap boleh
+	if _, err := s.VerifyIntegrity(context.Background(), &pb.VerifyIntegrityRequest{}); err != nil {
+		t.Fatalf("VerifyIntegrity without repair in read-only mode: %v", err)
+	}
+}
+
+func TestVerifyIntegrityDetectsAndRepairs(t *testing.T) {
+	now := time.Now()
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("u1", "alice@example.com", now),
+		seedUser("u2", "bob@example.com", now),
+	})
+	corruptEmailIndex(s, "bob@example.com")
+
+	// Tanpa repair: hanya laporan, index tidak disentuh
+	resp, err := s.VerifyIntegrity(context.Background(), &pb.VerifyIntegrityRequest{})
+	if err != nil {
+		t.Fatalf("VerifyIntegrity: %v", err)
+	}
+	if resp.Repaired || len(resp.Mismatches) != 2 {
+		t.Fatalf("check = %+v, want 2 mismatches and no repair", resp)
+	}
+	want := []*pb.IndexMismatch{
+		{Index: "email", Key: "bob@example.com", ExpectedId: "u2"},
+		{Index: "email", Key: "ghost@example.com", IndexedId: "ghost"},
+	}
+	for i, m := range resp.Mismatches {
+		if m.Index != want[i].Index || m.Key != want[i].Key || m.IndexedId != want[i].IndexedId || m.ExpectedId != want[i].ExpectedId {
+			t.Fatalf("mismatch %d = %+v, want %+v", i, m, want[i])
+		}
+	}
+
+	resp, err = s.VerifyIntegrity(context.Background(), &pb.VerifyIntegrityRequest{Repair: true})
+	if err != nil || !resp.Repaired || len(resp.Mismatches) != 2 {
+		t.Fatalf("repair = %+v (err: %v), want repaired with 2 mismatches", resp, err)
+	}
+
+	resp, err = s.VerifyIntegrity(context.Background(), &pb.VerifyIntegrityRequest{})
+	if err != nil || len(resp.Mismatches) != 0 {
+		t.Fatalf("after repair = %+v (err: %v), want no mismatches", resp, err)
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// errInvalidPageToken dikembalikan kalau page_token tidak bisa di-decode
+// atau dibuat untuk order_by yang berbeda
+var errInvalidPageToken = errors.New("invalid page_token")
+
+// pageCursor adalah isi page token: posisi user terakhir di halaman sebelumnya
+// Key ikut disimpan (bukan cuma id) supaya halaman berikutnya tetap benar
+// walaupun user terakhir itu sudah dihapus
+type pageCursor struct {
+	OrderBy string `json:"order_by"`
+	Key     string `json:"key"`
+	ID      string `json:"id"`
+}
+
+// encodePageToken membuat token halaman berikutnya dari user terakhir di halaman ini
+// Format: base64url tanpa padding dari JSON pageCursor (opaque bagi client, cukup dikirim balik apa adanya)
+func encodePageToken(order listOrder, last *pb.User) string {
+	raw, _ := json.Marshal(pageCursor{OrderBy: order.String(), Key: order.key(last), ID: last.Id})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodePageToken membaca posisi user terakhir dari token (token kosong = halaman pertama → nil)
+// Cursor dikembalikan sebagai *pb.User parsial supaya bisa dibandingkan dengan listOrder.less
+func decodePageToken(order listOrder, token string) (*pb.User, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" || cursor.OrderBy != order.String() {
+		return nil, errInvalidPageToken
+	}
+
+	last := &pb.User{Id: cursor.ID}
+	if order.field == orderByName {
+		last.Name = cursor.Key
+	} else {
+		// RFC3339Nano juga menerima token lama yang presisinya detik
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Key)
+		if err != nil {
+			return nil, errInvalidPageToken
+		}
+		last.CreatedAt = timestamppb.New(createdAt)
+	}
+	return last, nil
+}
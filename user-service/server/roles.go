@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+
+	pb "user-service/proto/user"
+	"user-service/store"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	maxRoles          = 16
+	maxRoleNameLength = 32
+)
+
+// normalizeRoles memvalidasi nama role lalu mengembalikan daftar yang sudah unik & terurut
+// Nama role: huruf kecil, angka, "-" dan "_" (ikut ke JWT dan dicocokkan persis oleh interceptor.Authorize)
+// Koma juga ditolak karena SQLite store menyimpan roles sebagai teks dipisah koma
+func normalizeRoles(roles []string) ([]string, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	seen := make(map[string]bool, len(roles))
+	for i, role := range roles {
+		if !validRoleName(role) {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       fmt.Sprintf("roles[%d]", i),
+				Description: fmt.Sprintf("role must be 1-%d characters of a-z, 0-9, '-' or '_'", maxRoleNameLength),
+			})
+			continue
+		}
+		seen[role] = true
+	}
+	if len(seen) > maxRoles {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       "roles",
+			Description: fmt.Sprintf("at most %d roles are allowed", maxRoles),
+		})
+	}
+	if err := badRequest(violations); err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, 0, len(seen))
+	for role := range seen {
+		normalized = append(normalized, role)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func validRoleName(role string) bool {
+	if role == "" || len(role) > maxRoleNameLength {
+		return false
+	}
+	for _, c := range role {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SetUserRoles mengganti seluruh role user (Unary RPC)
+// Dibatasi untuk role admin lewat policy interceptor.Authorize di main
+// Role baru baru berlaku di token berikutnya (token lama tetap membawa role lama sampai expired)
+func (s *UserServer) SetUserRoles(ctx context.Context, req *pb.SetUserRolesRequest) (*pb.SetUserRolesResponse, error) {
+	log.Printf("🎭 Setting roles for user %s: %v", req.Id, req.Roles)
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	roles, err := normalizeRoles(req.Roles)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.getActiveUser(ctx, req.Id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return nil, status.Errorf(codes.NotFound, "user with id %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	updated := proto.Clone(existing).(*pb.User)
+	updated.Roles = roles
+	updated.UpdatedAt = timestamppb.Now()
+	updated.Version++
+	if err := s.store.Update(ctx, updated); err != nil {
+		return nil, s.storeError(err)
+	}
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_UPDATED, updated)
+
+	log.Printf("✅ Roles updated for user %s: %v", updated.Id, roles)
+
+	return &pb.SetUserRolesResponse{User: updated}, nil
+}
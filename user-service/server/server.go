@@ -2,16 +2,28 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	// Import proto yang sudah di-generate
 	// pb = protocol buffer (naming convention umum)
+	"user-service/events"
 	pb "user-service/proto/user"
+	"user-service/redact"
+	"user-service/store"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // UserServer adalah struct yang mengimplementasikan gRPC service
@@ -19,16 +31,126 @@ import (
 // Artinya: jika di masa depan ada method baru di proto, code ini tidak akan break
 type UserServer struct {
 	pb.UnimplementedUserServiceServer // Embedded untuk safety
-	users map[string]*pb.User          // In-memory storage (dalam produksi pakai database)
-	mu    sync.RWMutex                 // Mutex untuk thread-safety (concurrent access)
+	store store.UserStore              // Storage user (lihat package store)
+	mu    sync.RWMutex                 // Koordinasi operasi multi-langkah (store + email index)
+
+	readOnly atomic.Bool   // Safe-mode: kalau true, interceptor menolak semua RPC mutasi
+	draining atomic.Bool   // Di-set saat shutdown: stream list yang sedang jalan dihentikan (lihat checkStream)
+	health   healthTracker // Error terakhir per komponen untuk RPC HealthDetail
+
+	defaulter UserDefaulter // Default field CreateUser sesuai policy deployment (default: no-op)
+
+	// Email canonicalization (opsional, nil = disabled)
+	canonicalizer *EmailCanonicalizer
+	emailIndex    map[string]string // Email key (lihat emailKey) → user ID, dijaga di bawah mu
+
+	watchers []*watcher // Subscriber WatchUsers (lihat watch.go), dijaga di bawah mu
+
+	events events.Publisher // Domain event ke sistem lain (default: no-op)
+
+	softDelete bool // DeleteUser/BulkDeleteUsers hanya mengisi deleted_at (lihat WithSoftDelete)
+
+	issueToken TokenIssuer // Token untuk Authenticate (nil = Authenticate disabled, lihat WithTokenIssuer)
+
+	maxGetUsersByIDs int // Batas id per GetUsersByIds (lihat WithMaxGetUsersByIDs)
 }
 
 // NewUserServer adalah constructor function untuk membuat instance UserServer
 // Pattern ini umum digunakan di Go untuk inisialisasi struct
-func NewUserServer() *UserServer {
-	return &UserServer{
-		users: make(map[string]*pb.User), // Initialize map
+// Storage di-inject lewat interface (store.NewMemoryStore, store.NewSQLiteStore, mock, dll)
+// Fitur opsional diaktifkan lewat Option (contoh: WithEmailCanonicalizer)
+func NewUserServer(userStore store.UserStore, opts ...Option) *UserServer {
+	s := &UserServer{
+		store:      userStore,
+		defaulter:  NoopDefaulter{},
+		events:     events.Noop{},
+		emailIndex: make(map[string]string),
+
+		maxGetUsersByIDs: DefaultMaxGetUsersByIDs,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Store persistent mungkin sudah berisi data → email index dibangun ulang dari isinya
+	if index, err := s.expectedEmailIndex(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to build email index from store: %v", err)
+	} else {
+		s.emailIndex = index
+	}
+	return s
+}
+
+// storeError mengubah error dari UserStore (selain not found) menjadi gRPC Internal
+// Error juga dicatat sebagai error terakhir komponen "store" di HealthDetail
+func (s *UserServer) storeError(err error) error {
+	// Pelanggaran unique email di database bukan gangguan store, jadi tidak dicatat ke health
+	if errors.Is(err, store.ErrEmailTaken) {
+		return status.Error(codes.AlreadyExists, "email is already registered")
+	}
+	s.health.record("store", err)
+	return status.Errorf(codes.Internal, "store error: %v", err)
+}
+
+// ReadOnlyFlag return flag read-only mode milik server
+// Dipakai oleh interceptor ReadOnly untuk memutuskan apakah write harus ditolak
+func (s *UserServer) ReadOnlyFlag() *atomic.Bool {
+	return &s.readOnly
+}
+
+// DrainingFlag return flag shutdown milik server
+// Di-set main saat menerima SIGTERM, sebelum GracefulStop menunggu RPC yang sedang jalan
+func (s *UserServer) DrainingFlag() *atomic.Bool {
+	return &s.draining
+}
+
+// errShuttingDown dikembalikan stream yang dihentikan karena server sedang shutdown
+// Unavailable = aman di-retry ke replica lain
+var errShuttingDown = status.Error(codes.Unavailable, "server shutting down")
+
+// checkStream dipanggil di setiap iterasi loop Send stream list (ListUsers, ListUsersByDateRange):
+//   - client sudah cancel / deadline lewat → berhenti tanpa mengirim sisa data
+//   - server sedang shutdown → berhenti dengan Unavailable, bukan terputus paksa saat drain timeout
+func (s *UserServer) checkStream(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	default:
+	}
+	if s.draining.Load() {
+		return errShuttingDown
+	}
+	return nil
+}
+
+// SetReadOnly mengimplementasikan RPC admin untuk toggle read-only mode saat runtime
+// Contoh: aktifkan saat maintenance/incident, matikan lagi setelah selesai
+func (s *UserServer) SetReadOnly(ctx context.Context, req *pb.SetReadOnlyRequest) (*pb.SetReadOnlyResponse, error) {
+	s.readOnly.Store(req.Enabled)
+
+	if req.Enabled {
+		log.Println("🔒 Read-only mode ENABLED: write RPCs will be rejected")
+	} else {
+		log.Println("🔓 Read-only mode DISABLED: write RPCs accepted again")
+	}
+
+	return &pb.SetReadOnlyResponse{Enabled: s.readOnly.Load()}, nil
+}
+
+// Warmup menyiapkan resource sebelum service menerima traffic
+// (contoh: membuka koneksi DB di pool, mengisi cache)
+// Selama warmup berjalan, health status = NOT_SERVING
+// Store dibaca sekali penuh: koneksi database terbuka & halaman data sudah di-cache OS
+func (s *UserServer) Warmup(ctx context.Context) error {
+	s.mu.RLock()
+	users, err := s.store.List(ctx, 0)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("warmup store: %w", err)
+	}
+
+	log.Printf("🔥 Warmup complete (%d users in store)", len(users))
+	return ctx.Err()
 }
 
 // CreateUser mengimplementasikan RPC method CreateUser dari proto
@@ -38,36 +160,80 @@ func NewUserServer() *UserServer {
 // - Return 1: Response message (*pb.CreateUserResponse)
 // - Return 2: error
 func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	log.Printf("📝 Creating user: %s", req.Name)
+	log.Printf("📝 Creating user: %s", redact.Field("name", req.Name))
+
+	// Isi field yang kosong sesuai policy deployment (lihat defaulter.go)
+	// Jalan sebelum validasi, jadi nilai default juga ikut divalidasi
+	// (defaulter & validasi tidak menyentuh state server, jadi tidak perlu lock)
+	s.defaulter.Default(req)
+
+	// Validasi input (lihat validation.go)
+	// Best practice: selalu validasi data dari client
+	if err := validateNewUser(req.Name, req.Email, req.Age, req.Password); err != nil {
+		// Return response dengan success=false DAN error InvalidArgument + detail per field
+		// (gateway memetakan code ini ke HTTP 400, error biasa akan jadi Unknown → 500)
+		return &pb.CreateUserResponse{
+			Success: false,
+			Message: status.Convert(err).Message(),
+		}, err
+	}
+
+	// Hash password SEBELUM lock: bcrypt sengaja lambat, tidak boleh menahan RPC lain
+	// Password asli tidak disimpan maupun di-log, hanya hash-nya (lihat store.SetPasswordHash)
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to hash password: %v", err)
+	}
 
 	// Lock untuk write operation (thread-safe)
 	// Penting jika ada multiple concurrent requests
 	s.mu.Lock()
 	defer s.mu.Unlock() // Unlock otomatis saat function selesai
 
-	// Validasi input
-	// Best practice: selalu validasi data dari client
-	if req.Name == "" || req.Email == "" {
-		// Return response dengan success=false DAN error
-		// Client bisa cek dari status code atau dari response.Success
-		return &pb.CreateUserResponse{
-			Success: false,
-			Message: "Name and email are required",
-		}, fmt.Errorf("validation error")
+	// Status tidak diisi → default ACTIVE
+	userStatus := req.Status
+	if userStatus == pb.UserStatus_USER_STATUS_UNSPECIFIED {
+		userStatus = pb.UserStatus_USER_STATUS_ACTIVE
+	}
+
+	// Email wajib unik: cek O(1) lewat email index (masih di bawah write lock,
+	// jadi 2 CreateUser bersamaan dengan email sama tidak bisa lolos dua-duanya)
+	// Kalau canonicalization aktif, a.b+x@gmail.com dan ab@gmail.com dianggap email yang sama
+	key := s.emailKey(req.Email)
+	if _, taken := s.emailIndex[key]; taken {
+		return nil, status.Errorf(codes.AlreadyExists, "email %s is already registered", req.Email)
+	}
+	canonicalEmail := ""
+	if s.canonicalizer != nil {
+		canonicalEmail = key
 	}
 
 	// Buat user baru
 	// Perhatikan: kita membuat struct sesuai dengan message User di proto
 	user := &pb.User{
-		Id:        uuid.New().String(),           // Generate unique ID
-		Name:      req.Name,                      // Ambil dari request
-		Email:     req.Email,                     // Ambil dari request
-		Age:       req.Age,                       // Ambil dari request
-		CreatedAt: time.Now().Format(time.RFC3339), // Timestamp
+		Id:             uuid.New().String(),           // Generate unique ID
+		Name:           req.Name,                      // Ambil dari request
+		Email:          req.Email,                     // Ambil dari request (bentuk asli, untuk display)
+		Age:            req.Age,                       // Ambil dari request
+		CreatedAt:      timestamppb.Now(),             // Timestamp
+		Status:         userStatus,                    // Status akun
+		CanonicalEmail: canonicalEmail,                // Key uniqueness (kosong kalau canonicalization off)
+		Version:        1,                             // Naik di setiap perubahan (optimistic concurrency)
 	}
 
-	// Simpan ke "database" (map)
-	s.users[user.Id] = user
+	// Simpan ke store (map atau database)
+	if err := s.store.Create(ctx, user); err != nil {
+		return nil, s.storeError(err)
+	}
+	if passwordHash != "" {
+		if err := s.store.SetPasswordHash(ctx, user.Id, passwordHash); err != nil {
+			// Jangan tinggalkan user yang tidak bisa login padahal client mengirim password
+			s.store.Delete(ctx, user.Id)
+			return nil, s.storeError(err)
+		}
+	}
+	s.emailIndex[key] = user.Id
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_CREATED, user)
 
 	// Return response yang sukses
 	// Response ini akan di-serialize menjadi binary oleh gRPC
@@ -83,22 +249,194 @@ func (s *UserServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
 	log.Printf("🔍 Getting user: %s", req.Id)
 
+	// Validasi field_mask sebelum lookup: path salah tetap InvalidArgument walaupun user tidak ada
+	if err := validateFieldMask(req.FieldMask); err != nil {
+		return nil, err
+	}
+
 	// RLock untuk read operation (multiple readers bisa akses bersamaan)
 	// Lebih efisien daripada Lock() untuk read-only operation
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Cari user di map
-	user, exists := s.users[req.Id]
-	if !exists {
+	// Cari user di store (user soft-deleted dianggap tidak ada)
+	user, err := s.getActiveUser(ctx, req.Id)
+	if errors.Is(err, store.ErrUserNotFound) {
 		// Return nil response DAN error
-		// gRPC akan convert error ini menjadi status code
-		return nil, fmt.Errorf("user with id %s not found", req.Id)
+		// status.Errorf membawa code NotFound ke client (error biasa akan jadi Unknown)
+		return nil, status.Errorf(codes.NotFound, "user with id %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, s.storeError(err)
 	}
 
-	// Return response dengan user yang ditemukan
+	// Return response dengan user yang ditemukan (hanya field yang diminta di field_mask)
 	return &pb.GetUserResponse{
-		User: user,
+		User: applyFieldMask(user, req.FieldMask),
+	}, nil
+}
+
+// DefaultMaxGetUsersByIDs adalah batas default jumlah id (unik) per GetUsersByIds
+// Lebih dari itu sebaiknya pakai ListUsers dengan pagination
+const DefaultMaxGetUsersByIDs = 100
+
+// WithMaxGetUsersByIDs mengganti batas jumlah id (unik) per GetUsersByIds (GET_USERS_BY_IDS_MAX)
+// Nilai < 1 diabaikan (tetap DefaultMaxGetUsersByIDs)
+func WithMaxGetUsersByIDs(n int) Option {
+	return func(s *UserServer) {
+		if n > 0 {
+			s.maxGetUsersByIDs = n
+		}
+	}
+}
+
+// GetUsersByIds mengambil beberapa user sekaligus (Unary RPC)
+// Id yang tidak ada TIDAK menggagalkan request: dicatat di missing_ids,
+// jadi client bisa menampilkan sebagian data sekaligus menandai yang hilang
+func (s *UserServer) GetUsersByIds(ctx context.Context, req *pb.GetUsersByIdsRequest) (*pb.GetUsersByIdsResponse, error) {
+	// Id duplikat/kosong cukup diproses sekali, urutan request dipertahankan
+	ids := make([]string, 0, len(req.Ids))
+	seen := make(map[string]bool, len(req.Ids))
+	for _, id := range req.Ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids must contain at least one id")
+	}
+	if len(ids) > s.maxGetUsersByIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "too many ids: %d (max %d)", len(ids), s.maxGetUsersByIDs)
+	}
+
+	log.Printf("🔍 Getting %d users by id", len(ids))
+
+	// 1 RLock untuk semua lookup: hasilnya snapshot yang konsisten
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &pb.GetUsersByIdsResponse{}
+	for _, id := range ids {
+		user, err := s.getActiveUser(ctx, id)
+		if errors.Is(err, store.ErrUserNotFound) {
+			resp.MissingIds = append(resp.MissingIds, id)
+			continue
+		}
+		if err != nil {
+			return nil, s.storeError(err)
+		}
+		resp.Users = append(resp.Users, user)
+	}
+	return resp, nil
+}
+
+// UpdateUser mengimplementasikan RPC method UpdateUser (Unary RPC)
+// Menimpa name/email/age user yang sudah ada, status & created_at tetap
+// Optimistic concurrency: expected_version wajib sama dengan version saat ini,
+// kalau tidak → Aborted, client harus baca ulang user lalu ulangi update-nya
+func (s *UserServer) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
+	log.Printf("✏️  Updating user: %s", req.Id)
+
+	// Validasi input (aturan sama dengan CreateUser)
+	if err := validateUserFields(req.Name, req.Email, req.Age); err != nil {
+		return nil, err
+	}
+	if req.ExpectedVersion <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "expected_version is required (version from the last read of this user)")
+	}
+
+	// Lock untuk write operation
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// User soft-deleted harus di-RestoreUser dulu sebelum bisa di-update
+	existing, err := s.getActiveUser(ctx, req.Id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return nil, status.Errorf(codes.NotFound, "user with id %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	// Compare-and-swap di bawah write lock: kalau user sudah diubah request lain sejak
+	// client membacanya, update ditolak (bukan diam-diam menimpa perubahan itu)
+	if existing.Version != req.ExpectedVersion {
+		return nil, status.Errorf(codes.Aborted, "stale update: expected version %d, current version is %d", req.ExpectedVersion, existing.Version)
+	}
+
+	// Email baru tidak boleh milik user lain
+	oldKey, key := s.emailKey(existing.Email), s.emailKey(req.Email)
+	if ownerID, taken := s.emailIndex[key]; taken && ownerID != req.Id {
+		return nil, status.Errorf(codes.AlreadyExists, "email %s is already registered", req.Email)
+	}
+	canonicalEmail := ""
+	if s.canonicalizer != nil {
+		canonicalEmail = key
+	}
+
+	// Copy, bukan ubah in-place: response yang sedang di-serialize di goroutine lain
+	// masih memegang pointer user lama
+	updated := proto.Clone(existing).(*pb.User)
+	updated.Name = req.Name
+	updated.Email = req.Email
+	updated.Age = req.Age
+	updated.CanonicalEmail = canonicalEmail
+	updated.UpdatedAt = timestamppb.Now()
+	updated.Version++
+
+	if err := s.store.Update(ctx, updated); err != nil {
+		return nil, s.storeError(err)
+	}
+	if oldKey != key {
+		delete(s.emailIndex, oldKey)
+	}
+	s.emailIndex[key] = updated.Id
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_UPDATED, updated)
+
+	log.Printf("✅ User updated: %s (%s)", updated.Id, redact.Field("email", updated.Email))
+
+	return &pb.UpdateUserResponse{
+		User:    updated,
+		Message: "User updated successfully",
+	}, nil
+}
+
+// DeleteUser mengimplementasikan RPC method DeleteUser (Unary RPC)
+// Idempotent: menghapus id yang tidak ada BUKAN error, jadi client aman retry
+func (s *UserServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	log.Printf("🗑️  Deleting user: %s", req.Id)
+
+	if req.Id == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "id is required")
+	}
+
+	// Lock untuk write operation
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// User yang sudah di-soft-delete juga "already deleted"
+	user, err := s.getActiveUser(ctx, req.Id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return &pb.DeleteUserResponse{
+			Deleted: false,
+			Message: "already deleted",
+		}, nil
+	}
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	// Soft delete (SOFT_DELETE=true) atau hapus permanen, lihat removeUserLocked
+	if err := s.removeUserLocked(ctx, user); err != nil {
+		return nil, s.storeError(err)
+	}
+
+	log.Printf("✅ User deleted: %s", req.Id)
+
+	return &pb.DeleteUserResponse{
+		Deleted: true,
+		Message: "User deleted successfully",
 	}, nil
 }
 
@@ -107,24 +445,44 @@ func (s *UserServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.G
 // Signature berbeda: parameter ke-2 adalah stream object, bukan request biasa
 // stream = channel untuk mengirim data bertahap
 func (s *UserServer) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_ListUsersServer) error {
-	log.Printf("📋 Listing users with limit: %d", req.Limit)
+	log.Printf("📋 Listing users with limit: %d, order_by: %q", req.Limit, req.OrderBy)
 
-	// Lock untuk read operation
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+	filter, err := newUserFilter(req.NameContains, req.EmailDomain, req.MinAge, req.MaxAge)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Filter, urutan & pagination sama dengan ListUsersPage (lihat queryUsers)
+	page, err := s.queryUsers(stream.Context(), listQuery{
+		limit:          int(req.Limit),
+		pageToken:      req.PageToken,
+		orderBy:        req.OrderBy,
+		filter:         filter,
+		includeDeleted: req.IncludeDeleted,
+	})
+	if err != nil {
+		return err
+	}
+	users := page.users
 
 	count := int32(0)
 	
 	// Iterate semua users
-	for _, user := range s.users {
-		// Cek limit (jika 0, berarti unlimited)
-		if req.Limit > 0 && count >= req.Limit {
-			break // Stop jika sudah mencapai limit
+	for i, user := range users {
+		if err := s.checkStream(stream.Context()); err != nil {
+			log.Printf("⚠️  ListUsers stopped after %d users: %v", count, err)
+			return err
+		}
+
+		resp := &pb.UserResponse{User: user}
+		// Message terakhir membawa token halaman berikutnya (kalau masih ada)
+		if i == len(users)-1 {
+			resp.NextPageToken = page.nextPageToken
 		}
 
 		// Send user satu per satu melalui stream
 		// stream.Send() adalah blocking call sampai data terkirim
-		if err := stream.Send(&pb.UserResponse{User: user}); err != nil {
+		if err := stream.Send(resp); err != nil {
 			return err // Return error jika gagal send
 		}
 		count++
@@ -141,6 +499,283 @@ func (s *UserServer) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_L
 	return nil
 }
 
+// CountUsers mengembalikan jumlah user (Unary RPC), untuk dashboard & total halaman di UI
+// Tanpa filter langsung pakai store.Count (SQLite: SELECT COUNT(*)),
+// dengan filter (status / pencarian seperti ListUsers) semua user di-scan
+func (s *UserServer) CountUsers(ctx context.Context, req *pb.CountUsersRequest) (*pb.CountUsersResponse, error) {
+	filter, err := newUserFilter(req.NameContains, req.EmailDomain, req.MinAge, req.MaxAge)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if req.Status == pb.UserStatus_USER_STATUS_UNSPECIFIED && filter.empty() {
+		n, err := s.store.Count(ctx)
+		if err != nil {
+			return nil, s.storeError(err)
+		}
+		return &pb.CountUsersResponse{Count: int64(n)}, nil
+	}
+
+	users, err := s.store.List(ctx, 0)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	var n int64
+	for _, user := range filter.apply(activeUsers(users)) {
+		if req.Status == pb.UserStatus_USER_STATUS_UNSPECIFIED || user.Status == req.Status {
+			n++
+		}
+	}
+	return &pb.CountUsersResponse{Count: n}, nil
+}
+
+// ListUsersByDateRange stream user yang CreatedAt-nya ada di rentang [from, to] (Server Streaming RPC)
+// Kedua ujung inklusif, hasil diurutkan dari yang paling lama dibuat
+func (s *UserServer) ListUsersByDateRange(req *pb.DateRangeRequest, stream pb.UserService_ListUsersByDateRangeServer) error {
+	// Validasi range
+	if req.From == nil || req.To == nil {
+		return status.Error(codes.InvalidArgument, "from and to are required")
+	}
+	if err := req.From.CheckValid(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid from: %v", err)
+	}
+	if err := req.To.CheckValid(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid to: %v", err)
+	}
+	from, to := req.From.AsTime(), req.To.AsTime()
+	if from.After(to) {
+		return status.Error(codes.InvalidArgument, "from must be before or equal to to")
+	}
+
+	log.Printf("📅 Listing users created between %s and %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+
+	// Kumpulkan dulu yang cocok di bawah read lock, lalu lepas lock sebelum send
+	// (client yang lambat tidak boleh menahan lock)
+	type match struct {
+		user      *pb.User
+		createdAt time.Time
+	}
+	var matches []match
+
+	s.mu.RLock()
+	users, err := s.store.List(stream.Context(), 0)
+	s.mu.RUnlock()
+	if err != nil {
+		return s.storeError(err)
+	}
+
+	for _, user := range activeUsers(users) {
+		if !user.CreatedAt.IsValid() {
+			continue // CreatedAt kosong/rusak tidak bisa dibandingkan, lewati
+		}
+		createdAt := user.CreatedAt.AsTime()
+		if createdAt.Before(from) || createdAt.After(to) {
+			continue
+		}
+		matches = append(matches, match{user: user, createdAt: createdAt})
+	}
+
+	// Urut waktu pembuatan; ID sebagai tie-breaker (data lama presisinya hanya detik)
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].createdAt.Equal(matches[j].createdAt) {
+			return matches[i].createdAt.Before(matches[j].createdAt)
+		}
+		return matches[i].user.Id < matches[j].user.Id
+	})
+
+	if req.Limit > 0 && len(matches) > int(req.Limit) {
+		matches = matches[:req.Limit]
+	}
+
+	for _, m := range matches {
+		if err := s.checkStream(stream.Context()); err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.UserResponse{User: m.user}); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("✅ Sent %d users in date range", len(matches))
+	return nil
+}
+
+// BulkDeleteUsers menghapus semua user yang cocok dengan filter (Unary RPC)
+// Dipakai untuk admin cleanup, contoh: hapus semua user @example.com yang dibuat sebelum 2024
+// Semua filter digabung dengan AND, dan minimal 1 filter wajib diisi
+// supaya tidak ada "hapus semua user" karena request kosong
+func (s *UserServer) BulkDeleteUsers(ctx context.Context, req *pb.BulkDeleteRequest) (*pb.BulkDeleteResponse, error) {
+	log.Printf("🗑️  Bulk deleting users (older_than: %q, email_domain: %q)", req.OlderThan, redact.Field("email_domain", req.EmailDomain))
+
+	if req.OlderThan == "" && req.EmailDomain == "" {
+		return nil, status.Error(codes.InvalidArgument, "at least one filter (older_than, email_domain) is required")
+	}
+
+	var olderThan time.Time
+	if req.OlderThan != "" {
+		t, err := time.Parse(time.RFC3339, req.OlderThan)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "older_than must be RFC3339: %v", err)
+		}
+		olderThan = t
+	}
+	domain := strings.ToLower(strings.TrimPrefix(req.EmailDomain, "@"))
+
+	// Write lock: filter + delete harus atomic
+	// (tidak boleh ada user baru/berubah di tengah proses)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users, err := s.store.List(ctx, 0)
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	deleted := int32(0)
+	for _, user := range activeUsers(users) {
+		if !olderThan.IsZero() {
+			if !user.CreatedAt.IsValid() || !user.CreatedAt.AsTime().Before(olderThan) {
+				continue
+			}
+		}
+		if domain != "" && !hasEmailDomain(user.Email, domain) {
+			continue
+		}
+
+		if err := s.removeUserLocked(ctx, user); err != nil {
+			return nil, s.storeError(err)
+		}
+		deleted++
+	}
+
+	log.Printf("✅ Bulk deleted %d users", deleted)
+
+	return &pb.BulkDeleteResponse{
+		DeletedCount: deleted,
+	}, nil
+}
+
+// hasEmailDomain cek apakah email berakhiran @domain (case-insensitive)
+func hasEmailDomain(email, domain string) bool {
+	at := strings.LastIndex(email, "@")
+	return at >= 0 && strings.ToLower(email[at+1:]) == domain
+}
+
+// TransferEmail memindahkan email from_id ke to_id, dan email to_id ke from_id (swap)
+// Contoh multi-record update yang atomic: kedua user di-update di bawah 1 write lock,
+// jadi tidak pernah ada momen di mana 2 user punya email yang sama
+func (s *UserServer) TransferEmail(ctx context.Context, req *pb.TransferEmailRequest) (*pb.TransferEmailResponse, error) {
+	log.Printf("🔁 Transferring email: %s → %s", req.FromId, req.ToId)
+
+	if req.FromId == "" || req.ToId == "" {
+		return nil, status.Error(codes.InvalidArgument, "from_id and to_id are required")
+	}
+	if req.FromId == req.ToId {
+		return nil, status.Error(codes.InvalidArgument, "from_id and to_id must be different users")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	from, err := s.getActiveUser(ctx, req.FromId)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return nil, status.Errorf(codes.NotFound, "user with id %s not found", req.FromId)
+	}
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	to, err := s.getActiveUser(ctx, req.ToId)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return nil, status.Errorf(codes.NotFound, "user with id %s not found", req.ToId)
+	}
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+
+	// Email sama persis → swap tidak mengubah apa-apa, dan menandakan data sudah duplikat
+	if from.Email == to.Email {
+		return nil, status.Errorf(codes.FailedPrecondition, "users %s and %s already share the same email", req.FromId, req.ToId)
+	}
+
+	// Clone dulu, jangan ubah object lama di tempat:
+	// object lama mungkin sedang di-serialize oleh RPC lain (misal ListUsers)
+	newFrom := proto.Clone(from).(*pb.User)
+	newTo := proto.Clone(to).(*pb.User)
+	newFrom.Email, newTo.Email = to.Email, from.Email
+	newFrom.CanonicalEmail, newTo.CanonicalEmail = to.CanonicalEmail, from.CanonicalEmail
+	newFrom.Version++
+	newTo.Version++
+	now := time.Now()
+	newFrom.UpdatedAt, newTo.UpdatedAt = timestamppb.New(now), timestamppb.New(now)
+
+	// Kedua perubahan di-commit atomic (UpdateAll): tidak pernah ada keadaan di mana
+	// hanya 1 user yang sudah berganti email (email dobel)
+	if err := s.store.UpdateAll(ctx, []*pb.User{newFrom, newTo}); err != nil {
+		return nil, s.storeError(err)
+	}
+	s.emailIndex[s.emailKey(newFrom.Email)] = newFrom.Id
+	s.emailIndex[s.emailKey(newTo.Email)] = newTo.Id
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_UPDATED, newFrom)
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_UPDATED, newTo)
+
+	log.Printf("✅ Email transferred between %s and %s", req.FromId, req.ToId)
+
+	return &pb.TransferEmailResponse{
+		FromUser: newFrom,
+		ToUser:   newTo,
+	}, nil
+}
+
+// BatchCreateUsers mengimplementasikan RPC BatchCreateUsers (Client Streaming RPC)
+// Client mengirim banyak CreateUserRequest lewat stream, server membalas 1x setelah client selesai (io.EOF)
+// Setiap item diproses seperti CreateUser biasa (defaulter, validasi, email unik);
+// item yang gagal dicatat di errors dan TIDAK menghentikan item berikutnya
+func (s *UserServer) BatchCreateUsers(stream pb.UserService_BatchCreateUsersServer) error {
+	log.Println("📦 Batch creating users")
+
+	resp := &pb.BatchCreateUsersResponse{}
+	for index := int32(0); ; index++ {
+		// stream.Recv() blocking sampai message berikutnya datang,
+		// io.EOF = client sudah memanggil CloseSend (tidak ada item lagi)
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err // Stream putus / context di-cancel
+		}
+
+		created, err := s.CreateUser(stream.Context(), req)
+		if err != nil {
+			resp.Failed++
+			resp.Errors = append(resp.Errors, &pb.BatchItemError{
+				Index:   index,
+				Message: batchItemMessage(created, err),
+			})
+			continue
+		}
+		resp.Created++
+		resp.Ids = append(resp.Ids, created.User.Id)
+	}
+
+	log.Printf("✅ Batch create finished: %d created, %d failed", resp.Created, resp.Failed)
+
+	// SendAndClose: kirim 1 response lalu tutup stream
+	return stream.SendAndClose(resp)
+}
+
+// batchItemMessage mengambil pesan error yang paling jelas untuk 1 item batch
+// CreateUser mengembalikan pesan validasi di response.Message (error-nya generic)
+func batchItemMessage(resp *pb.CreateUserResponse, err error) string {
+	if resp != nil && resp.Message != "" {
+		return resp.Message
+	}
+	return status.Convert(err).Message()
+}
+
 /*
 📚 CATATAN PENTING tentang RPC Types:
 
@@ -152,19 +787,20 @@ func (s *UserServer) ListUsers(req *pb.ListUsersRequest, stream pb.UserService_L
    - Client send 1 request → Server send MULTIPLE responses
    - Berguna untuk: list data besar, real-time updates, progress tracking
    
-3. Client Streaming RPC (tidak ada di contoh ini):
+3. Client Streaming RPC (BatchCreateUsers):
    - Client send MULTIPLE requests → Server send 1 response
    - Berguna untuk: upload file besar, batch insert
    
-4. Bidirectional Streaming RPC (tidak ada di contoh ini):
+4. Bidirectional Streaming RPC (WatchUsers):
    - Client dan Server send MULTIPLE messages bolak-balik
    - Berguna untuk: chat, real-time collaboration
 
 🔐 Thread Safety:
-- sync.RWMutex digunakan karena map di Go TIDAK thread-safe
-- Lock() untuk write (Create)
-- RLock() untuk read (Get, List)
-- Dalam produksi dengan database, biasanya tidak perlu mutex manual
+- Setiap UserStore wajib thread-safe per operasi (MemoryStore pakai RWMutex
+  karena map di Go TIDAK thread-safe, SQLiteStore mengandalkan database)
+- s.mu di UserServer menjaga operasi MULTI-langkah tetap atomic:
+  Lock() untuk write (Create, Update, Transfer, Bulk delete + email index)
+  RLock() untuk read (Get, List)
 
 🎯 Error Handling:
 - Return error untuk invalid input atau server error
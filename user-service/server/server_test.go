@@ -0,0 +1,425 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+	"user-service/redact"
+	"user-service/store"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Helper bersama untuk test package server: UserServer di atas MemoryStore,
+// handler RPC dipanggil langsung (tanpa network, interceptor tidak ikut)
+
+// newTestServer membuat UserServer dengan store yang sudah berisi users
+// (email index dibangun dari isi store, sama seperti saat restart dengan database persistent)
+func newTestServer(t *testing.T, users []*pb.User, opts ...Option) (*UserServer, *store.MemoryStore) {
+	t.Helper()
+	memory := store.NewMemoryStore()
+	for _, user := range users {
+		if err := memory.Create(context.Background(), user); err != nil {
+			t.Fatalf("seed user %s: %v", user.Id, err)
+		}
+	}
+	return NewUserServer(memory, opts...), memory
+}
+
+// seedUser membuat user aktif (version 1) dengan created_at tertentu
+func seedUser(id, email string, createdAt time.Time) *pb.User {
+	return &pb.User{
+		Id:        id,
+		Name:      "User " + id,
+		Email:     email,
+		Age:       30,
+		Status:    pb.UserStatus_USER_STATUS_ACTIVE,
+		CreatedAt: timestamppb.New(createdAt),
+		Version:   1,
+	}
+}
+
+// createUser memanggil RPC CreateUser dan menggagalkan test kalau error
+func createUser(t *testing.T, s *UserServer, name, email string) *pb.User {
+	t.Helper()
+	resp, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: name, Email: email, Age: 30})
+	if err != nil {
+		t.Fatalf("CreateUser(%s): %v", email, err)
+	}
+	return resp.User
+}
+
+// wantCode menggagalkan test kalau code gRPC err bukan want
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("code = %v, want %v (err: %v)", got, want, err)
+	}
+}
+
+// recordingPublisher adalah events.Publisher yang mencatat semua event yang dipublish
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []*pb.UserEvent
+}
+
+func (p *recordingPublisher) Publish(eventType pb.UserEventType, user *pb.User) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, &pb.UserEvent{Type: eventType, User: user})
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+// userIDs return id semua event dengan tipe eventType, sesuai urutan publish
+func (p *recordingPublisher) userIDs(eventType pb.UserEventType) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var ids []string
+	for _, event := range p.events {
+		if event.Type == eventType {
+			ids = append(ids, event.User.Id)
+		}
+	}
+	return ids
+}
+
+func TestBulkDeleteUsersFilters(t *testing.T) {
+	jan := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	jun := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	seed := func() []*pb.User {
+		return []*pb.User{
+			seedUser("old-example", "a@example.com", jan),
+			seedUser("new-example", "b@EXAMPLE.com", jun),
+			seedUser("old-other", "c@other.com", jan),
+			seedUser("new-other", "d@other.com", jun),
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  *pb.BulkDeleteRequest
+		want []string // Id user yang terhapus
+	}{
+		{"domain", &pb.BulkDeleteRequest{EmailDomain: "example.com"}, []string{"old-example", "new-example"}},
+		{"domain with @", &pb.BulkDeleteRequest{EmailDomain: "@Example.com"}, []string{"old-example", "new-example"}},
+		{"older than", &pb.BulkDeleteRequest{OlderThan: "2024-01-01T00:00:00Z"}, []string{"old-example", "old-other"}},
+		{"both filters (AND)", &pb.BulkDeleteRequest{OlderThan: "2024-01-01T00:00:00Z", EmailDomain: "other.com"}, []string{"old-other"}},
+		{"nothing matches", &pb.BulkDeleteRequest{EmailDomain: "nowhere.com"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := &recordingPublisher{}
+			s, memory := newTestServer(t, seed(), WithEventPublisher(events))
+
+			resp, err := s.BulkDeleteUsers(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("BulkDeleteUsers: %v", err)
+			}
+			if int(resp.DeletedCount) != len(tt.want) {
+				t.Fatalf("deleted_count = %d, want %d", resp.DeletedCount, len(tt.want))
+			}
+
+			// 1 event DELETED per user yang terhapus
+			deleted := events.userIDs(pb.UserEventType_USER_EVENT_TYPE_DELETED)
+			if len(deleted) != len(tt.want) {
+				t.Fatalf("DELETED events = %v, want %v", deleted, tt.want)
+			}
+			for _, id := range tt.want {
+				if _, err := memory.Get(context.Background(), id); err != store.ErrUserNotFound {
+					t.Fatalf("user %s still in store (err: %v)", id, err)
+				}
+			}
+			if count, _ := memory.Count(context.Background()); count != 4-len(tt.want) {
+				t.Fatalf("remaining users = %d, want %d", count, 4-len(tt.want))
+			}
+		})
+	}
+}
+
+func TestBulkDeleteUsersRejectsInvalidFilter(t *testing.T) {
+	s, memory := newTestServer(t, []*pb.User{seedUser("u1", "a@example.com", time.Now())})
+
+	_, err := s.BulkDeleteUsers(context.Background(), &pb.BulkDeleteRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = s.BulkDeleteUsers(context.Background(), &pb.BulkDeleteRequest{OlderThan: "yesterday"})
+	wantCode(t, err, codes.InvalidArgument)
+
+	if count, _ := memory.Count(context.Background()); count != 1 {
+		t.Fatalf("users = %d after rejected bulk delete, want 1", count)
+	}
+}
+
+func TestBulkDeleteUsersFreesEmail(t *testing.T) {
+	s, _ := newTestServer(t, []*pb.User{seedUser("u1", "a@example.com", time.Now())})
+
+	if _, err := s.BulkDeleteUsers(context.Background(), &pb.BulkDeleteRequest{EmailDomain: "example.com"}); err != nil {
+		t.Fatalf("BulkDeleteUsers: %v", err)
+	}
+	// Email index ikut dibersihkan, jadi email yang sama bisa didaftarkan lagi
+	createUser(t, s, "Again", "a@example.com")
+}
+
+func TestWarmupReadsStore(t *testing.T) {
+	s, _ := newTestServer(t, []*pb.User{seedUser("u1", "a@example.com", time.Now())})
+	if err := s.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Warmup(ctx); err == nil {
+		t.Fatal("Warmup with cancelled context: want error")
+	}
+}
+
+func TestTransferEmailSwapsAtomically(t *testing.T) {
+	events := &recordingPublisher{}
+	s, _ := newTestServer(t, nil, WithEventPublisher(events))
+	alice := createUser(t, s, "Alice", "alice@example.com")
+	bob := createUser(t, s, "Bob", "bob@example.com")
+
+	resp, err := s.TransferEmail(context.Background(), &pb.TransferEmailRequest{FromId: alice.Id, ToId: bob.Id})
+	if err != nil {
+		t.Fatalf("TransferEmail: %v", err)
+	}
+	if resp.FromUser.Email != "bob@example.com" || resp.ToUser.Email != "alice@example.com" {
+		t.Fatalf("emails = %s / %s, want swapped", resp.FromUser.Email, resp.ToUser.Email)
+	}
+	if resp.FromUser.Version != alice.Version+1 || resp.ToUser.Version != bob.Version+1 {
+		t.Fatalf("versions = %d / %d, want both bumped", resp.FromUser.Version, resp.ToUser.Version)
+	}
+	if alice.UpdatedAt != nil || resp.FromUser.UpdatedAt == nil || resp.ToUser.UpdatedAt == nil {
+		t.Fatalf("updated_at = %v / %v, want set on both users (unset before the transfer)", resp.FromUser.UpdatedAt, resp.ToUser.UpdatedAt)
+	}
+
+	// Data di store & email index ikut berubah
+	got, err := s.GetUser(context.Background(), &pb.GetUserRequest{Id: alice.Id})
+	if err != nil || got.User.Email != "bob@example.com" {
+		t.Fatalf("GetUser(alice) = %v, %v; want email bob@example.com", got, err)
+	}
+	for _, email := range []string{"alice@example.com", "bob@example.com"} {
+		_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Eve", Email: email})
+		wantCode(t, err, codes.AlreadyExists)
+	}
+	if ids := events.userIDs(pb.UserEventType_USER_EVENT_TYPE_UPDATED); len(ids) != 2 {
+		t.Fatalf("UPDATED events = %v, want 1 per user", ids)
+	}
+}
+
+func TestTransferEmailRejects(t *testing.T) {
+	s, _ := newTestServer(t, nil, WithSoftDelete())
+	alice := createUser(t, s, "Alice", "alice@example.com")
+	bob := createUser(t, s, "Bob", "bob@example.com")
+	gone := createUser(t, s, "Gone", "gone@example.com")
+	if _, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: gone.Id}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     codes.Code
+	}{
+		{"missing from_id", "", bob.Id, codes.InvalidArgument},
+		{"missing to_id", alice.Id, "", codes.InvalidArgument},
+		{"same user", alice.Id, alice.Id, codes.InvalidArgument},
+		{"unknown from", "nope", bob.Id, codes.NotFound},
+		{"unknown to", alice.Id, "nope", codes.NotFound},
+		{"soft-deleted user", alice.Id, gone.Id, codes.NotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.TransferEmail(context.Background(), &pb.TransferEmailRequest{FromId: tt.from, ToId: tt.to})
+			wantCode(t, err, tt.want)
+		})
+	}
+
+	// Tidak ada yang berubah setelah semua penolakan
+	got, _ := s.GetUser(context.Background(), &pb.GetUserRequest{Id: alice.Id})
+	if got.User.Email != "alice@example.com" || got.User.Version != alice.Version {
+		t.Fatalf("alice changed after rejected transfers: %v", got.User)
+	}
+}
+
+func TestTransferEmailSameEmailConflict(t *testing.T) {
+	// Data lama yang sudah dobel (ditulis sebelum email unik dijaga) tidak boleh di-swap
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("u1", "dup@example.com", time.Now()),
+		seedUser("u2", "dup@example.com", time.Now()),
+	})
+	_, err := s.TransferEmail(context.Background(), &pb.TransferEmailRequest{FromId: "u1", ToId: "u2"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+// failingUpdateAllStore gagal di UpdateAll, untuk memastikan swap tidak setengah jalan
+type failingUpdateAllStore struct {
+	*store.MemoryStore
+}
+
+func (failingUpdateAllStore) UpdateAll(ctx context.Context, users []*pb.User) error {
+	return errors.New("disk full")
+}
+
+func TestTransferEmailStoreFailureLeavesNoPartialWrite(t *testing.T) {
+	memory := store.NewMemoryStore()
+	s := NewUserServer(failingUpdateAllStore{memory})
+	alice := createUser(t, s, "Alice", "alice@example.com")
+	bob := createUser(t, s, "Bob", "bob@example.com")
+
+	_, err := s.TransferEmail(context.Background(), &pb.TransferEmailRequest{FromId: alice.Id, ToId: bob.Id})
+	wantCode(t, err, codes.Internal)
+
+	for id, email := range map[string]string{alice.Id: "alice@example.com", bob.Id: "bob@example.com"} {
+		user, _ := memory.Get(context.Background(), id)
+		if user.Email != email {
+			t.Fatalf("user %s email = %s after failed transfer, want %s", id, user.Email, email)
+		}
+	}
+	// Email index juga tidak berubah: email lama masih milik pemilik lama
+	_, err = s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Eve", Email: "alice@example.com"})
+	wantCode(t, err, codes.AlreadyExists)
+}
+
+// streamRecorder adalah server stream palsu (grpc.ServerStreamingServer[T]) yang mencatat semua message yang dikirim
+type streamRecorder[T any] struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*T
+}
+
+func newStreamRecorder[T any](ctx context.Context) *streamRecorder[T] {
+	return &streamRecorder[T]{ctx: ctx}
+}
+
+func (r *streamRecorder[T]) Context() context.Context { return r.ctx }
+
+func (r *streamRecorder[T]) Send(msg *T) error {
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+func TestRPCLogsRedactPII(t *testing.T) {
+	// Sama dengan main saat PII_LOG_REDACTION=true
+	var out bytes.Buffer
+	redact.Enable("test-salt", []string{"email", "name"})
+	log.SetOutput(redact.Writer(&out))
+	t.Cleanup(func() {
+		redact.Enable("", nil) // Tanpa field PII = redaction tidak berefek
+		log.SetOutput(os.Stderr)
+	})
+
+	s, _ := newTestServer(t, nil)
+	user := createUser(t, s, "Alice Smith", "alice.smith@example.com")
+	if _, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+		Id: user.Id, Name: "Alice Smith", Email: "alice@example.org", Age: 31, ExpectedVersion: user.Version,
+	}); err != nil {
+		t.Fatalf("UpdateUser: %v", err)
+	}
+
+	logs := out.String()
+	if logs == "" {
+		t.Fatal("no log output captured")
+	}
+	for _, raw := range []string{"alice.smith@example.com", "alice@example.org", "Alice Smith"} {
+		if strings.Contains(logs, raw) {
+			t.Fatalf("server log contains %q:\n%s", raw, logs)
+		}
+	}
+}
+
+func TestDeleteUserTwiceIsIdempotent(t *testing.T) {
+	s, memory := newTestServer(t, []*pb.User{seedUser("u1", "alice@example.com", time.Now())})
+
+	first, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: "u1"})
+	if err != nil || !first.Deleted {
+		t.Fatalf("first delete = %+v, %v; want deleted", first, err)
+	}
+	if _, err := memory.Get(context.Background(), "u1"); !errors.Is(err, store.ErrUserNotFound) {
+		t.Fatalf("store Get after delete: err = %v, want ErrUserNotFound", err)
+	}
+
+	// Retry (atau id yang memang tidak pernah ada) tetap sukses
+	for _, id := range []string{"u1", "never-existed"} {
+		resp, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: id})
+		if err != nil {
+			t.Fatalf("delete %s again: %v", id, err)
+		}
+		if resp.Deleted || resp.Message != "already deleted" {
+			t.Fatalf("delete %s again = %+v, want deleted=false with \"already deleted\"", id, resp)
+		}
+	}
+
+	_, err = s.DeleteUser(context.Background(), &pb.DeleteUserRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestCreateUserDuplicateEmail(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	createUser(t, s, "Alice", "alice@example.com")
+
+	// Email sama (huruf besar/kecil diabaikan) → AlreadyExists, user kedua tidak dibuat
+	for _, email := range []string{"alice@example.com", "ALICE@Example.com"} {
+		resp, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Other", Email: email})
+		wantCode(t, err, codes.AlreadyExists)
+		if resp != nil && resp.User != nil {
+			t.Fatalf("duplicate %q returned user %v", email, resp.User)
+		}
+	}
+	if n := len(s.emailIndex); n != 1 {
+		t.Fatalf("email index has %d entries, want 1", n)
+	}
+}
+
+func TestEmailIndexConsistentAfterDelete(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	alice := createUser(t, s, "Alice", "alice@example.com")
+	bob := createUser(t, s, "Bob", "bob@example.com")
+
+	if _, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: alice.Id}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, ok := s.emailIndex["alice@example.com"]; ok {
+		t.Fatal("deleted user's email still in the index")
+	}
+	if owner := s.emailIndex["bob@example.com"]; owner != bob.Id {
+		t.Fatalf("bob's email owner = %q, want %q", owner, bob.Id)
+	}
+
+	// Email yang sudah bebas boleh dipakai lagi, email bob tetap terkunci
+	again := createUser(t, s, "Alice Again", "alice@example.com")
+	if owner := s.emailIndex["alice@example.com"]; owner != again.Id {
+		t.Fatalf("reused email owner = %q, want %q", owner, again.Id)
+	}
+	_, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: "Other", Email: "bob@example.com"})
+	wantCode(t, err, codes.AlreadyExists)
+}
+
+func TestUpdateUserDuplicateEmail(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	createUser(t, s, "Alice", "alice@example.com")
+	bob := createUser(t, s, "Bob", "bob@example.com")
+
+	_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+		Id: bob.Id, Name: "Bob", Email: "Alice@example.com", ExpectedVersion: bob.Version,
+	})
+	wantCode(t, err, codes.AlreadyExists)
+
+	// Update dengan email sendiri tetap boleh
+	if _, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+		Id: bob.Id, Name: "Bobby", Email: "bob@example.com", ExpectedVersion: bob.Version,
+	}); err != nil {
+		t.Fatalf("UpdateUser with own email: %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	pb "user-service/proto/user"
+	"user-service/store"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// WithSoftDelete membuat DeleteUser & BulkDeleteUsers hanya mengisi deleted_at (audit trail)
+// alih-alih menghapus record dari store. User soft-deleted disembunyikan dari semua read
+// (kecuali ListUsers include_deleted) dan bisa dipulihkan lewat RestoreUser
+//
+// Email user soft-deleted tetap tercatat di email index, jadi RestoreUser tidak pernah
+// bentrok; konsekuensinya email itu belum bisa dipakai CreateUser sampai dihapus permanen
+func WithSoftDelete() Option {
+	return func(s *UserServer) {
+		s.softDelete = true
+	}
+}
+
+// isDeleted = user sudah di-soft-delete
+func isDeleted(user *pb.User) bool {
+	return user.DeletedAt != nil
+}
+
+// activeUsers membuang user yang sudah di-soft-delete (slice baru, urutan dipertahankan)
+func activeUsers(users []*pb.User) []*pb.User {
+	out := make([]*pb.User, 0, len(users))
+	for _, user := range users {
+		if !isDeleted(user) {
+			out = append(out, user)
+		}
+	}
+	return out
+}
+
+// getActiveUser seperti store.Get, tapi user soft-deleted dianggap tidak ada (ErrUserNotFound)
+// Caller wajib memegang s.mu (read atau write)
+func (s *UserServer) getActiveUser(ctx context.Context, id string) (*pb.User, error) {
+	user, err := s.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if isDeleted(user) {
+		return nil, store.ErrUserNotFound
+	}
+	return user, nil
+}
+
+// removeUserLocked menghapus user sesuai mode: soft delete (isi deleted_at) atau hapus permanen
+// Event DELETED dikirim di kedua mode. Caller wajib memegang s.mu (write lock)
+func (s *UserServer) removeUserLocked(ctx context.Context, user *pb.User) error {
+	if !s.softDelete {
+		if err := s.store.Delete(ctx, user.Id); err != nil && !errors.Is(err, store.ErrUserNotFound) {
+			return err
+		}
+		delete(s.emailIndex, s.emailKey(user.Email))
+		s.publish(pb.UserEventType_USER_EVENT_TYPE_DELETED, user)
+		return nil
+	}
+
+	// Clone: object lama mungkin sedang di-serialize RPC lain
+	deleted := proto.Clone(user).(*pb.User)
+	deleted.DeletedAt = timestamppb.Now()
+	deleted.Version++
+	if err := s.store.Update(ctx, deleted); err != nil && !errors.Is(err, store.ErrUserNotFound) {
+		return err
+	}
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_DELETED, deleted)
+	return nil
+}
+
+// RestoreUser membatalkan soft delete (Unary RPC)
+// User yang tidak ada (atau sudah dihapus permanen) → NotFound,
+// user yang tidak sedang dihapus → FailedPrecondition
+func (s *UserServer) RestoreUser(ctx context.Context, req *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	log.Printf("♻️  Restoring user: %s", req.Id)
+
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, err := s.store.Get(ctx, req.Id)
+	if errors.Is(err, store.ErrUserNotFound) {
+		return nil, status.Errorf(codes.NotFound, "user with id %s not found", req.Id)
+	}
+	if err != nil {
+		return nil, s.storeError(err)
+	}
+	if !isDeleted(user) {
+		return nil, status.Errorf(codes.FailedPrecondition, "user %s is not deleted", req.Id)
+	}
+
+	restored := proto.Clone(user).(*pb.User)
+	restored.DeletedAt = nil
+	restored.Version++
+	if err := s.store.Update(ctx, restored); err != nil {
+		return nil, s.storeError(err)
+	}
+	s.publish(pb.UserEventType_USER_EVENT_TYPE_RESTORED, restored)
+
+	log.Printf("✅ User restored: %s", req.Id)
+
+	return &pb.RestoreUserResponse{User: restored}, nil
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+func softDeleteUsers() []*pb.User {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*pb.User{
+		seedUser("u1", "u1@example.com", base),
+		seedUser("u2", "u2@example.com", base.Add(time.Hour)),
+	}
+}
+
+func TestSoftDeleteThenListExcludes(t *testing.T) {
+	s, memory := newTestServer(t, softDeleteUsers(), WithSoftDelete())
+	ctx := context.Background()
+
+	if _, err := s.DeleteUser(ctx, &pb.DeleteUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	ids, err := listUserIDs(t, s, &pb.ListUsersRequest{})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if want := []string{"u2"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ListUsers = %v, want %v", ids, want)
+	}
+	ids, err = listUserIDs(t, s, &pb.ListUsersRequest{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("ListUsers include_deleted: %v", err)
+	}
+	if want := []string{"u1", "u2"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ListUsers include_deleted = %v, want %v", ids, want)
+	}
+
+	_, err = s.GetUser(ctx, &pb.GetUserRequest{Id: "u1"})
+	wantCode(t, err, codes.NotFound)
+
+	// Record tetap ada di store, hanya ditandai deleted_at
+	stored, err := memory.Get(ctx, "u1")
+	if err != nil {
+		t.Fatalf("store.Get: %v", err)
+	}
+	if stored.DeletedAt == nil || stored.Version != 2 {
+		t.Fatalf("stored user = %v, want deleted_at set and version 2", stored)
+	}
+}
+
+func TestRestoreThenListIncludes(t *testing.T) {
+	s, _ := newTestServer(t, softDeleteUsers(), WithSoftDelete())
+	ctx := context.Background()
+
+	if _, err := s.DeleteUser(ctx, &pb.DeleteUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	resp, err := s.RestoreUser(ctx, &pb.RestoreUserRequest{Id: "u1"})
+	if err != nil {
+		t.Fatalf("RestoreUser: %v", err)
+	}
+	if resp.User.DeletedAt != nil || resp.User.Version != 3 {
+		t.Fatalf("restored user = %v, want deleted_at cleared and version 3", resp.User)
+	}
+
+	ids, err := listUserIDs(t, s, &pb.ListUsersRequest{})
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	if want := []string{"u1", "u2"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("ListUsers = %v, want %v", ids, want)
+	}
+	if _, err := s.GetUser(ctx, &pb.GetUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("GetUser after restore: %v", err)
+	}
+}
+
+func TestRestoreUserErrors(t *testing.T) {
+	s, _ := newTestServer(t, softDeleteUsers(), WithSoftDelete())
+	ctx := context.Background()
+
+	_, err := s.RestoreUser(ctx, &pb.RestoreUserRequest{Id: "u1"})
+	wantCode(t, err, codes.FailedPrecondition) // Tidak sedang dihapus
+	_, err = s.RestoreUser(ctx, &pb.RestoreUserRequest{Id: "nope"})
+	wantCode(t, err, codes.NotFound)
+	_, err = s.RestoreUser(ctx, &pb.RestoreUserRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestHardDeleteByDefault(t *testing.T) {
+	s, memory := newTestServer(t, softDeleteUsers())
+	ctx := context.Background()
+
+	if _, err := s.DeleteUser(ctx, &pb.DeleteUserRequest{Id: "u1"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if _, err := memory.Get(ctx, "u1"); err == nil {
+		t.Fatal("user still in store after hard delete")
+	}
+	_, err := s.RestoreUser(ctx, &pb.RestoreUserRequest{Id: "u1"})
+	wantCode(t, err, codes.NotFound)
+}
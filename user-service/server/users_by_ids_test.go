@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetUsersByIdsMixedExistingAndMissing(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s, _ := newTestServer(t, []*pb.User{
+		seedUser("u1", "u1@example.com", base),
+		seedUser("u2", "u2@example.com", base),
+		seedUser("u3", "u3@example.com", base),
+	}, WithSoftDelete())
+	if _, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: "u3"}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+
+	// Urutan request dipertahankan, duplikat & id kosong diabaikan, soft-deleted dianggap missing
+	resp, err := s.GetUsersByIds(context.Background(), &pb.GetUsersByIdsRequest{
+		Ids: []string{"u2", "nope", "u1", "", "u2", "u3"},
+	})
+	if err != nil {
+		t.Fatalf("GetUsersByIds: %v", err)
+	}
+	var found []string
+	for _, user := range resp.Users {
+		found = append(found, user.Id)
+	}
+	if want := []string{"u2", "u1"}; !reflect.DeepEqual(found, want) {
+		t.Fatalf("users = %v, want %v", found, want)
+	}
+	if want := []string{"nope", "u3"}; !reflect.DeepEqual(resp.MissingIds, want) {
+		t.Fatalf("missing_ids = %v, want %v", resp.MissingIds, want)
+	}
+}
+
+func TestGetUsersByIdsRejectsInvalid(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+
+	_, err := s.GetUsersByIds(context.Background(), &pb.GetUsersByIdsRequest{Ids: []string{"", ""}})
+	wantCode(t, err, codes.InvalidArgument)
+
+	ids := make([]string, DefaultMaxGetUsersByIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("u%d", i)
+	}
+	_, err = s.GetUsersByIds(context.Background(), &pb.GetUsersByIdsRequest{Ids: ids})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestGetUsersByIdsConfiguredLimit(t *testing.T) {
+	s, _ := newTestServer(t, nil, WithMaxGetUsersByIDs(2))
+
+	_, err := s.GetUsersByIds(context.Background(), &pb.GetUsersByIdsRequest{Ids: []string{"a", "b", "c"}})
+	wantCode(t, err, codes.InvalidArgument)
+	if !strings.Contains(err.Error(), "max 2") {
+		t.Fatalf("err = %v, want the configured limit in the message", err)
+	}
+	// Id duplikat dihitung sekali
+	if _, err := s.GetUsersByIds(context.Background(), &pb.GetUsersByIdsRequest{Ids: []string{"a", "b", "a"}}); err != nil {
+		t.Fatalf("GetUsersByIds within limit: %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	maxNameLength = 100
+	maxAge        = 150
+
+	minPasswordLength = 8
+	maxPasswordBytes  = 72 // bcrypt hanya memakai 72 byte pertama, sisanya diam-diam diabaikan
+)
+
+// validateUserFields memvalidasi field user yang dikirim client (CreateUser & UpdateUser)
+// Semua field dicek sekaligus, jadi client langsung tahu SEMUA yang salah dalam 1 round trip
+// Return nil kalau valid, atau InvalidArgument dengan detail google.rpc.BadRequest
+// (1 FieldViolation per field yang salah)
+func validateUserFields(name, email string, age int32) error {
+	return badRequest(userFieldViolations(name, email, age))
+}
+
+// validateNewUser = validateUserFields + password (CreateUser), tetap dilaporkan sekaligus
+// Password kosong valid: user tanpa password hanya tidak bisa Authenticate
+func validateNewUser(name, email string, age int32, password string) error {
+	violations := userFieldViolations(name, email, age)
+	if password != "" {
+		// Pesan tidak pernah menyertakan nilai password
+		if n := utf8.RuneCountInString(password); n < minPasswordLength {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "password",
+				Description: fmt.Sprintf("password must be at least %d characters", minPasswordLength),
+			})
+		} else if len(password) > maxPasswordBytes {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "password",
+				Description: fmt.Sprintf("password must be at most %d bytes", maxPasswordBytes),
+			})
+		}
+	}
+	return badRequest(violations)
+}
+
+// userFieldViolations mengumpulkan semua field user yang tidak valid
+func userFieldViolations(name, email string, age int32) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	add := func(field, description string) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+	}
+
+	// Panjang dihitung per karakter (rune), bukan byte, supaya nama non-ASCII tidak dirugikan
+	if n := utf8.RuneCountInString(strings.TrimSpace(name)); n == 0 {
+		add("name", "name is required")
+	} else if n > maxNameLength {
+		add("name", fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	}
+
+	// mail.ParseAddress juga menerima "Budi <budi@example.com>", jadi hasilnya harus
+	// sama persis dengan input supaya yang tersimpan memang alamat email saja
+	if email == "" {
+		add("email", "email is required")
+	} else if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		add("email", "email must be a valid address like name@example.com")
+	}
+
+	if age < 0 || age > maxAge {
+		add("age", fmt.Sprintf("age must be between 0 and %d", maxAge))
+	}
+	return violations
+}
+
+// badRequest membuat error InvalidArgument dengan detail google.rpc.BadRequest (nil kalau tidak ada violation)
+func badRequest(violations []*errdetails.BadRequest_FieldViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	// Message tetap berisi semua alasan, untuk client yang tidak membaca details
+	reasons := make([]string, len(violations))
+	for i, v := range violations {
+		reasons[i] = v.Description
+	}
+	st := status.New(codes.InvalidArgument, strings.Join(reasons, "; "))
+	if withDetails, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// violatedFields return nama field dari detail google.rpc.BadRequest, sesuai urutan
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	wantCode(t, err, codes.InvalidArgument)
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+		for _, v := range badRequest.FieldViolations {
+			if v.Description == "" {
+				t.Fatalf("field %q: empty description", v.Field)
+			}
+			fields = append(fields, v.Field)
+		}
+	}
+	return fields
+}
+
+func TestCreateUserFieldValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.CreateUserRequest
+		want []string
+	}{
+		{"empty name", &pb.CreateUserRequest{Name: "  ", Email: "a@example.com"}, []string{"name"}},
+		{"name too long", &pb.CreateUserRequest{Name: strings.Repeat("a", maxNameLength+1), Email: "a@example.com"}, []string{"name"}},
+		{"missing email", &pb.CreateUserRequest{Name: "Alice"}, []string{"email"}},
+		{"invalid email", &pb.CreateUserRequest{Name: "Alice", Email: "not-an-email"}, []string{"email"}},
+		{"display name email", &pb.CreateUserRequest{Name: "Alice", Email: "Alice <a@example.com>"}, []string{"email"}},
+		{"negative age", &pb.CreateUserRequest{Name: "Alice", Email: "a@example.com", Age: -1}, []string{"age"}},
+		{"age too high", &pb.CreateUserRequest{Name: "Alice", Email: "a@example.com", Age: maxAge + 1}, []string{"age"}},
+		{"short password", &pb.CreateUserRequest{Name: "Alice", Email: "a@example.com", Password: "short"}, []string{"password"}},
+		{"all at once", &pb.CreateUserRequest{Name: "", Email: "nope", Age: 200, Password: "x"}, []string{"name", "email", "age", "password"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, memory := newTestServer(t, nil)
+			resp, err := s.CreateUser(context.Background(), tt.req)
+			if got := violatedFields(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("violated fields = %v, want %v", got, tt.want)
+			}
+			if resp == nil || resp.Success || resp.Message == "" {
+				t.Fatalf("response = %+v, want success=false with a message", resp)
+			}
+			if n, _ := memory.Count(context.Background()); n != 0 {
+				t.Fatalf("invalid user stored (%d users)", n)
+			}
+		})
+	}
+}
+
+func TestCreateUserValidBoundaries(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	// Batas atas panjang nama dihitung per karakter, bukan byte
+	name := strings.Repeat("é", maxNameLength)
+	if _, err := s.CreateUser(context.Background(), &pb.CreateUserRequest{Name: name, Email: "a@example.com", Age: maxAge}); err != nil {
+		t.Fatalf("CreateUser at the limits: %v", err)
+	}
+}
+
+func TestUpdateUserFieldValidation(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	user := createUser(t, s, "Alice", "alice@example.com")
+
+	_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{
+		Id: user.Id, Name: "", Email: "bad", Age: -5, ExpectedVersion: user.Version,
+	})
+	if got, want := violatedFields(t, err), []string{"name", "email", "age"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("violated fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateUserStaleVersionRejected(t *testing.T) {
+	user := seedUser("u1", "u1@example.com", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s, _ := newTestServer(t, []*pb.User{user})
+	ctx := context.Background()
+
+	// 2 client membaca user yang sama (version 1), lalu sama-sama update
+	first, err := s.UpdateUser(ctx, &pb.UpdateUserRequest{Id: "u1", Name: "First", Email: user.Email, Age: 30, ExpectedVersion: 1})
+	if err != nil {
+		t.Fatalf("first UpdateUser: %v", err)
+	}
+	if first.User.Version != 2 {
+		t.Fatalf("version after update = %d, want 2", first.User.Version)
+	}
+
+	_, err = s.UpdateUser(ctx, &pb.UpdateUserRequest{Id: "u1", Name: "Second", Email: user.Email, Age: 30, ExpectedVersion: 1})
+	wantCode(t, err, codes.Aborted)
+
+	got, err := s.GetUser(ctx, &pb.GetUserRequest{Id: "u1"})
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if got.User.Name != "First" || got.User.Version != 2 {
+		t.Fatalf("user = %v, want the first update to win", got.User)
+	}
+
+	// Baca ulang lalu ulangi dengan version terbaru → berhasil
+	if _, err := s.UpdateUser(ctx, &pb.UpdateUserRequest{Id: "u1", Name: "Second", Email: user.Email, Age: 30, ExpectedVersion: 2}); err != nil {
+		t.Fatalf("retry with current version: %v", err)
+	}
+}
+
+func TestUpdateUserRequiresExpectedVersion(t *testing.T) {
+	user := seedUser("u1", "u1@example.com", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s, _ := newTestServer(t, []*pb.User{user})
+
+	_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: "u1", Name: "X", Email: user.Email})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestConcurrentUpdatesSameVersionOnlyOneWins(t *testing.T) {
+	user := seedUser("u1", "u1@example.com", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s, _ := newTestServer(t, []*pb.User{user})
+
+	const writers = 20
+	codesSeen := make(chan codes.Code, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.UpdateUser(context.Background(), &pb.UpdateUserRequest{Id: "u1", Name: "Writer", Email: user.Email, Age: 30, ExpectedVersion: 1})
+			codesSeen <- status.Code(err)
+		}()
+	}
+	wg.Wait()
+	close(codesSeen)
+
+	var ok, aborted int
+	for code := range codesSeen {
+		switch code {
+		case codes.OK:
+			ok++
+		case codes.Aborted:
+			aborted++
+		default:
+			t.Fatalf("unexpected code %v", code)
+		}
+	}
+	if ok != 1 || aborted != writers-1 {
+		t.Fatalf("ok = %d, aborted = %d; want exactly 1 winner", ok, aborted)
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"io"
+	"log"
+
+	"user-service/events"
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// watchBufferSize adalah jumlah event yang boleh antre per subscriber
+// Subscriber yang tertinggal lebih dari ini diputus (lihat publish)
+const watchBufferSize = 64
+
+// watcher adalah 1 subscriber WatchUsers
+// events ditutup oleh server saat subscriber dilepas
+type watcher struct {
+	events chan *pb.UserEvent
+}
+
+// subscribe mendaftarkan subscriber baru ke feed event
+func (s *UserServer) subscribe() *watcher {
+	w := &watcher{events: make(chan *pb.UserEvent, watchBufferSize)}
+
+	s.mu.Lock()
+	s.watchers = append(s.watchers, w)
+	s.mu.Unlock()
+	return w
+}
+
+// unsubscribe melepas subscriber (aman dipanggil walaupun sudah diputus publish)
+func (s *UserServer) unsubscribe(w *watcher) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeWatcherLocked(w)
+}
+
+// removeWatcherLocked menghapus w dari daftar dan menutup channel-nya, caller wajib memegang s.mu
+func (s *UserServer) removeWatcherLocked(w *watcher) {
+	for i, existing := range s.watchers {
+		if existing == w {
+			s.watchers = append(s.watchers[:i], s.watchers[i+1:]...)
+			close(w.events)
+			return
+		}
+	}
+}
+
+// publish mengirim event ke semua subscriber, caller wajib memegang s.mu (write lock)
+// Tidak pernah blocking: subscriber yang buffer-nya penuh diputus supaya
+// 1 client lambat tidak menahan CreateUser/UpdateUser/DeleteUser
+// Event yang sama juga diteruskan ke events.Publisher (NATS), yang juga non-blocking
+func (s *UserServer) publish(eventType pb.UserEventType, user *pb.User) {
+	event := &pb.UserEvent{Type: eventType, User: user}
+	s.events.Publish(eventType, user)
+
+	for _, w := range append([]*watcher(nil), s.watchers...) {
+		select {
+		case w.events <- event:
+		default:
+			log.Printf("⚠️  Watcher too slow, disconnecting (%d events queued)", watchBufferSize)
+			s.removeWatcherLocked(w)
+		}
+	}
+}
+
+// WithEventPublisher memasang publisher domain event (contoh: events.NATSPublisher)
+func WithEventPublisher(p events.Publisher) Option {
+	return func(s *UserServer) {
+		s.events = p
+	}
+}
+
+// WatchUsers mengimplementasikan RPC WatchUsers (Bidirectional Streaming RPC)
+// Client dan server kirim message secara independen di stream yang sama:
+//   - client → server: WatchRequest (filter), boleh kapan saja
+//   - server → client: UserEvent setiap ada perubahan user yang lolos filter
+//
+// Stream berakhir saat client cancel / disconnect; subscriber & goroutine Recv ikut dibersihkan
+func (s *UserServer) WatchUsers(stream pb.UserService_WatchUsersServer) error {
+	ctx := stream.Context()
+	log.Println("👀 Watcher connected")
+
+	w := s.subscribe()
+	defer s.unsubscribe(w)
+
+	// Goroutine terpisah untuk Recv (blocking), filter baru diteruskan lewat channel
+	// Recv langsung return error begitu handler selesai, jadi goroutine ini tidak bocor
+	filters := make(chan *pb.WatchRequest)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case filters <- req:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var types map[pb.UserEventType]bool // nil = semua jenis event
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("👋 Watcher disconnected")
+			return status.FromContextError(ctx.Err()).Err()
+
+		case err := <-recvErr:
+			// Client selesai kirim filter (CloseSend) → tetap push event dengan filter terakhir
+			recvErr = nil
+			if err != io.EOF {
+				return err
+			}
+
+		case req := <-filters:
+			types = nil
+			if len(req.Types) > 0 {
+				types = make(map[pb.UserEventType]bool, len(req.Types))
+				for _, t := range req.Types {
+					types[t] = true
+				}
+			}
+
+		case event, ok := <-w.events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watcher too slow, events dropped")
+			}
+			if types != nil && !types[event.Type] {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
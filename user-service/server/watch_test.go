@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// watchStream adalah bidi stream palsu untuk WatchUsers
+// Filter dari client dikirim lewat requests, event dari server diterima di sent
+type watchStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests chan *pb.WatchRequest
+	recvs    chan struct{} // Sinyal setiap kali server memanggil Recv
+	sent     chan *pb.UserEvent
+}
+
+func newWatchStream(ctx context.Context) *watchStream {
+	return &watchStream{
+		ctx:      ctx,
+		requests: make(chan *pb.WatchRequest),
+		recvs:    make(chan struct{}, 16),
+		sent:     make(chan *pb.UserEvent),
+	}
+}
+
+func (w *watchStream) Context() context.Context { return w.ctx }
+
+func (w *watchStream) Recv() (*pb.WatchRequest, error) {
+	w.recvs <- struct{}{}
+	select {
+	case req := <-w.requests:
+		return req, nil
+	case <-w.ctx.Done():
+		return nil, w.ctx.Err()
+	}
+}
+
+func (w *watchStream) Send(event *pb.UserEvent) error {
+	select {
+	case w.sent <- event:
+		return nil
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	}
+}
+
+// startWatch menjalankan WatchUsers di background dan menunggu sampai subscriber terdaftar
+func startWatch(t *testing.T, s *UserServer, stream *watchStream) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- s.WatchUsers(stream) }()
+	<-stream.recvs
+	waitWatchers(t, s, 1)
+	return done
+}
+
+func waitWatchers(t *testing.T, s *UserServer, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		s.mu.RLock()
+		n := len(s.watchers)
+		s.mu.RUnlock()
+		if n == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("watchers = %d, want %d", n, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func receiveEvent(t *testing.T, stream *watchStream) *pb.UserEvent {
+	t.Helper()
+	select {
+	case event := <-stream.sent:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func TestWatchUsersReceivesCreatedEvent(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newWatchStream(ctx)
+	startWatch(t, s, stream)
+
+	user := createUser(t, s, "Alice", "alice@example.com")
+	event := receiveEvent(t, stream)
+	if event.Type != pb.UserEventType_USER_EVENT_TYPE_CREATED || event.User.Id != user.Id {
+		t.Fatalf("event = %v, want CREATED for %s", event, user.Id)
+	}
+}
+
+func TestWatchUsersFilter(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newWatchStream(ctx)
+	startWatch(t, s, stream)
+
+	// Filter diterapkan begitu server sudah memanggil Recv lagi (filter sebelumnya sudah diproses)
+	stream.requests <- &pb.WatchRequest{Types: []pb.UserEventType{pb.UserEventType_USER_EVENT_TYPE_DELETED}}
+	<-stream.recvs
+
+	user := createUser(t, s, "Alice", "alice@example.com")
+	if _, err := s.DeleteUser(context.Background(), &pb.DeleteUserRequest{Id: user.Id}); err != nil {
+		t.Fatalf("DeleteUser: %v", err)
+	}
+	if event := receiveEvent(t, stream); event.Type != pb.UserEventType_USER_EVENT_TYPE_DELETED {
+		t.Fatalf("event type = %v, want only DELETED", event.Type)
+	}
+}
+
+func TestWatchUsersCleansUpOnCancel(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := startWatch(t, s, newWatchStream(ctx))
+
+	cancel()
+	select {
+	case err := <-done:
+		wantCode(t, err, codes.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("WatchUsers did not return after the client cancelled")
+	}
+	waitWatchers(t, s, 0)
+}
+
+func TestWatchUsersDisconnectsSlowSubscriber(t *testing.T) {
+	s, _ := newTestServer(t, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := newWatchStream(ctx)
+	done := startWatch(t, s, stream)
+
+	// Client tidak membaca: 1 event tertahan di Send, buffer penuh, event berikutnya memutus subscriber
+	// CreateUser tidak boleh ikut tertahan
+	finished := make(chan struct{})
+	go func() {
+		for i := 0; i < watchBufferSize+2; i++ {
+			req := &pb.CreateUserRequest{Name: "User", Email: fmt.Sprintf("user%d@example.com", i)}
+			if _, err := s.CreateUser(context.Background(), req); err != nil {
+				t.Errorf("CreateUser: %v", err)
+			}
+		}
+		close(finished)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("CreateUser blocked on a slow watcher")
+	}
+	waitWatchers(t, s, 0)
+
+	// Event yang sudah antre tetap terkirim, lalu stream berakhir ResourceExhausted
+	for received := 0; ; received++ {
+		select {
+		case <-stream.sent:
+			continue
+		case err := <-done:
+			wantCode(t, err, codes.ResourceExhausted)
+			if received > watchBufferSize+1 {
+				t.Fatalf("received %d events, want at most %d", received, watchBufferSize+1)
+			}
+			return
+		case <-time.After(time.Second):
+			t.Fatal("WatchUsers did not end after the subscriber was dropped")
+		}
+	}
+}
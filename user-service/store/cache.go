@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// consistencyKey adalah key context untuk mode konsistensi read
+type consistencyKey struct{}
+
+// WithStrongConsistency menandai context supaya read melewati cache dan selalu ke source
+// Di-set oleh interceptor.Consistency dari metadata "consistency: strong"
+func WithStrongConsistency(ctx context.Context) context.Context {
+	return context.WithValue(ctx, consistencyKey{}, true)
+}
+
+// StrongConsistency cek apakah request minta strong consistency
+func StrongConsistency(ctx context.Context) bool {
+	strong, _ := ctx.Value(consistencyKey{}).(bool)
+	return strong
+}
+
+// CachingStore adalah decorator UserStore yang meng-cache hasil Get (LRU + TTL, per proses)
+// Read default boleh dari cache (eventual consistency, maksimal basi sebesar TTL
+// kalau ada writer lain di luar proses ini), kecuali context ditandai WithStrongConsistency.
+// Write lewat decorator ini langsung meng-update/membuang entry, jadi write milik
+// proses sendiri selalu langsung terlihat
+//
+// Entry tidak pernah diganti dengan user yang Version-nya lebih lama: Get yang membaca
+// store sebelum Update tapi selesai sesudahnya tidak bisa menimpa hasil Update di cache
+type CachingStore struct {
+	next UserStore
+
+	mu    sync.Mutex // Menjaga cek version + Add di put tetap atomic
+	cache *expirable.LRU[string, *pb.User]
+
+	hits   metric.Int64Counter
+	misses metric.Int64Counter
+}
+
+// NewCachingStore membungkus next dengan read cache berisi maksimal maxSize user
+// (yang paling lama tidak dipakai dibuang duluan, maxSize 0 = tanpa batas)
+// dan mendaftarkan counter store.cache.hits & store.cache.misses di global MeterProvider
+func NewCachingStore(next UserStore, ttl time.Duration, maxSize int) (*CachingStore, error) {
+	meter := otel.Meter("user-service/store")
+	hits, err := meter.Int64Counter("store.cache.hits",
+		metric.WithDescription("Get calls served from the in-process user cache"))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64Counter("store.cache.misses",
+		metric.WithDescription("Get calls that missed the in-process user cache and read the store"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &CachingStore{
+		next:   next,
+		cache:  expirable.NewLRU[string, *pb.User](maxSize, nil, ttl),
+		hits:   hits,
+		misses: misses,
+	}, nil
+}
+
+func (c *CachingStore) Get(ctx context.Context, id string) (*pb.User, error) {
+	// Strong read tidak dihitung hit/miss: cache memang sengaja dilewati
+	if !StrongConsistency(ctx) {
+		if user, ok := c.cache.Get(id); ok {
+			c.hits.Add(ctx, 1)
+			return user, nil
+		}
+		c.misses.Add(ctx, 1)
+	}
+
+	user, err := c.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.put(user)
+	return user, nil
+}
+
+func (c *CachingStore) Create(ctx context.Context, user *pb.User) error {
+	if err := c.next.Create(ctx, user); err != nil {
+		return err
+	}
+	c.put(user)
+	return nil
+}
+
+// List tidak di-cache (hasilnya bergantung limit & berubah di setiap write)
+func (c *CachingStore) List(ctx context.Context, limit int) ([]*pb.User, error) {
+	return c.next.List(ctx, limit)
+}
+
+// Count tidak di-cache, sama seperti List
+func (c *CachingStore) Count(ctx context.Context) (int, error) {
+	return c.next.Count(ctx)
+}
+
+func (c *CachingStore) Update(ctx context.Context, user *pb.User) error {
+	// Entry dibuang dulu: kalau update gagal di tengah, read berikutnya tetap ke source
+	c.cache.Remove(user.Id)
+	if err := c.next.Update(ctx, user); err != nil {
+		return err
+	}
+	c.put(user)
+	return nil
+}
+
+func (c *CachingStore) UpdateAll(ctx context.Context, users []*pb.User) error {
+	for _, user := range users {
+		c.cache.Remove(user.Id)
+	}
+	if err := c.next.UpdateAll(ctx, users); err != nil {
+		return err
+	}
+	for _, user := range users {
+		c.put(user)
+	}
+	return nil
+}
+
+func (c *CachingStore) Delete(ctx context.Context, id string) error {
+	c.cache.Remove(id)
+	return c.next.Delete(ctx, id)
+}
+
+// Password hash tidak di-cache: hanya dibaca saat Authenticate
+func (c *CachingStore) SetPasswordHash(ctx context.Context, id, hash string) error {
+	return c.next.SetPasswordHash(ctx, id, hash)
+}
+
+func (c *CachingStore) GetPasswordHash(ctx context.Context, id string) (string, error) {
+	return c.next.GetPasswordHash(ctx, id)
+}
+
+// Ping diteruskan ke store di bawahnya (kalau didukung), dipakai HealthDetail
+func (c *CachingStore) Ping(ctx context.Context) error {
+	if pinger, ok := c.next.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// put menyimpan user, kecuali cache sudah berisi version yang lebih baru
+func (c *CachingStore) put(user *pb.User) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.cache.Peek(user.Id); ok && cached.Version > user.Version {
+		return
+	}
+	c.cache.Add(user.Id, user)
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+)
+
+// countingStore menghitung Get yang sampai ke source
+type countingStore struct {
+	*MemoryStore
+	gets atomic.Int32
+}
+
+func (s *countingStore) Get(ctx context.Context, id string) (*pb.User, error) {
+	s.gets.Add(1)
+	return s.MemoryStore.Get(ctx, id)
+}
+
+func newTestCachingStore(t *testing.T) (*CachingStore, *countingStore) {
+	t.Helper()
+	source := &countingStore{MemoryStore: NewMemoryStore()}
+	if err := source.Create(context.Background(), &pb.User{Id: "u1", Name: "Alice", Version: 1}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	cache, err := NewCachingStore(source, time.Minute, 0)
+	if err != nil {
+		t.Fatalf("NewCachingStore: %v", err)
+	}
+	return cache, source
+}
+
+func TestCachingStoreServesCachedReads(t *testing.T) {
+	cache, source := newTestCachingStore(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.Get(ctx, "u1"); err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if got := source.gets.Load(); got != 1 {
+		t.Fatalf("source Get calls = %d, want 1 (later reads from cache)", got)
+	}
+
+	// Writer lain langsung ke source: read default tetap melihat data cache (eventual)
+	if err := source.Update(ctx, &pb.User{Id: "u1", Name: "Changed", Version: 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if user, _ := cache.Get(ctx, "u1"); user.Name != "Alice" {
+		t.Fatalf("default read name = %q, want cached Alice", user.Name)
+	}
+}
+
+func TestCachingStoreStrongConsistencyBypassesCache(t *testing.T) {
+	cache, source := newTestCachingStore(t)
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "u1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := source.Update(ctx, &pb.User{Id: "u1", Name: "Changed", Version: 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	strong := WithStrongConsistency(ctx)
+	user, err := cache.Get(strong, "u1")
+	if err != nil {
+		t.Fatalf("strong Get: %v", err)
+	}
+	if user.Name != "Changed" {
+		t.Fatalf("strong read name = %q, want fresh Changed", user.Name)
+	}
+	if got := source.gets.Load(); got != 2 {
+		t.Fatalf("source Get calls = %d, want 2 (strong read hits the source)", got)
+	}
+
+	// Hasil strong read ikut menyegarkan cache untuk read default berikutnya
+	if user, _ := cache.Get(ctx, "u1"); user.Name != "Changed" {
+		t.Fatalf("default read after strong read name = %q, want Changed", user.Name)
+	}
+	if StrongConsistency(ctx) {
+		t.Fatal("plain context reported strong consistency")
+	}
+}
+
+func TestCachingStoreOwnWritesVisible(t *testing.T) {
+	cache, _ := newTestCachingStore(t)
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "u1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := cache.Update(ctx, &pb.User{Id: "u1", Name: "Updated", Version: 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if user, _ := cache.Get(ctx, "u1"); user.Name != "Updated" {
+		t.Fatalf("name = %q after write through the cache, want Updated", user.Name)
+	}
+	if err := cache.Delete(ctx, "u1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := cache.Get(ctx, "u1"); err != ErrUserNotFound {
+		t.Fatalf("Get after Delete err = %v, want ErrUserNotFound", err)
+	}
+}
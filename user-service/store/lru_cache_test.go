@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newSizedCachingStore membuat CachingStore berkapasitas maxSize di atas n user "u1".."un"
+func newSizedCachingStore(t *testing.T, n, maxSize int) (*CachingStore, *countingStore) {
+	t.Helper()
+	source := &countingStore{MemoryStore: NewMemoryStore()}
+	for i := 1; i <= n; i++ {
+		if err := source.Create(context.Background(), &pb.User{Id: fmt.Sprintf("u%d", i), Version: 1}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	cache, err := NewCachingStore(source, time.Minute, maxSize)
+	if err != nil {
+		t.Fatalf("NewCachingStore: %v", err)
+	}
+	return cache, source
+}
+
+// cached cek apakah Get(id) dilayani cache (store tidak dibaca)
+func cached(t *testing.T, cache *CachingStore, source *countingStore, id string) bool {
+	t.Helper()
+	before := source.gets.Load()
+	if _, err := cache.Get(context.Background(), id); err != nil {
+		t.Fatalf("Get(%s): %v", id, err)
+	}
+	return source.gets.Load() == before
+}
+
+func TestCachingStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, source := newSizedCachingStore(t, 3, 2)
+	ctx := context.Background()
+
+	cache.Get(ctx, "u1")
+	cache.Get(ctx, "u2")
+	cache.Get(ctx, "u1") // u1 jadi yang paling baru dipakai
+	cache.Get(ctx, "u3") // Kapasitas penuh → u2 (paling lama tidak dipakai) dibuang
+
+	if !cached(t, cache, source, "u1") {
+		t.Fatal("u1 was evicted, want it kept (recently used)")
+	}
+	if !cached(t, cache, source, "u3") {
+		t.Fatal("u3 was evicted, want it kept (just added)")
+	}
+	if cached(t, cache, source, "u2") {
+		t.Fatal("u2 still cached, want it evicted as least recently used")
+	}
+}
+
+func TestCachingStoreEvictsOnUpdateAndDelete(t *testing.T) {
+	cache, source := newSizedCachingStore(t, 2, 10)
+	ctx := context.Background()
+	cache.Get(ctx, "u1")
+	cache.Get(ctx, "u2")
+
+	// Update mengganti entry dengan version baru, bukan menyisakan yang lama
+	if err := cache.Update(ctx, &pb.User{Id: "u1", Name: "updated", Version: 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if user, _ := cache.Get(ctx, "u1"); user.Version != 2 {
+		t.Fatalf("cached user after Update = %v, want version 2", user)
+	}
+	if err := cache.Delete(ctx, "u2"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	before := source.gets.Load()
+	if _, err := cache.Get(ctx, "u2"); err == nil {
+		t.Fatal("Get after Delete served a cached user")
+	}
+	if source.gets.Load() != before+1 {
+		t.Fatal("Get after Delete did not read the store")
+	}
+}
+
+func TestCachingStoreKeepsNewerVersion(t *testing.T) {
+	cache, _ := newSizedCachingStore(t, 1, 10)
+	ctx := context.Background()
+
+	if err := cache.Update(ctx, &pb.User{Id: "u1", Name: "new", Version: 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	// Get lambat yang membaca version 1 sebelum Update tidak boleh menimpa entry version 2
+	cache.put(&pb.User{Id: "u1", Name: "old", Version: 1})
+
+	user, err := cache.Get(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if user.Version != 2 || user.Name != "new" {
+		t.Fatalf("cached user = %v, want version 2", user)
+	}
+}
+
+func TestCachingStoreHitMissMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	t.Cleanup(func() { otel.SetMeterProvider(previous) })
+
+	cache, _ := newSizedCachingStore(t, 2, 10)
+	ctx := context.Background()
+	cache.Get(ctx, "u1")                        // miss
+	cache.Get(ctx, "u1")                        // hit
+	cache.Get(ctx, "u1")                        // hit
+	cache.Get(ctx, "u2")                        // miss
+	cache.Get(WithStrongConsistency(ctx), "u2") // Tidak dihitung
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	got := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+				for _, dp := range sum.DataPoints {
+					got[m.Name] += dp.Value
+				}
+			}
+		}
+	}
+	if got["store.cache.hits"] != 2 || got["store.cache.misses"] != 2 {
+		t.Fatalf("metrics = %v, want 2 hits and 2 misses", got)
+	}
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryStore adalah UserStore in-memory (data hilang saat restart)
+// Karena itu perubahan tipe field proto (contoh: created_at string → Timestamp) tidak butuh migrasi:
+// user lama hilang bersama proses lama, user baru langsung dibuat dengan tipe baru
+// Thread-safe: map dijaga RWMutex, Lock() untuk write dan RLock() untuk read
+type MemoryStore struct {
+	mu        sync.RWMutex
+	users     map[string]*pb.User
+	passwords map[string]string // User ID → bcrypt hash
+}
+
+// NewMemoryStore membuat MemoryStore kosong
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{users: make(map[string]*pb.User), passwords: make(map[string]string)}
+}
+
+func (m *MemoryStore) Create(ctx context.Context, user *pb.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.users[user.Id] = user
+	return nil
+}
+
+func (m *MemoryStore) Get(ctx context.Context, id string) (*pb.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	user, ok := m.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (m *MemoryStore) List(ctx context.Context, limit int) ([]*pb.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	users := make([]*pb.User, 0, len(m.users))
+	for _, user := range m.users {
+		if limit > 0 && len(users) >= limit {
+			break
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+// Update menyimpan COPY dari user: pointer lama mungkin masih dipegang RPC lain
+// yang sedang men-serialize response (misal ListUsers), jadi tidak boleh diubah in-place
+func (m *MemoryStore) Update(ctx context.Context, user *pb.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[user.Id]; !ok {
+		return ErrUserNotFound
+	}
+	m.users[user.Id] = proto.Clone(user).(*pb.User)
+	return nil
+}
+
+func (m *MemoryStore) UpdateAll(ctx context.Context, users []*pb.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, user := range users {
+		if _, ok := m.users[user.Id]; !ok {
+			return ErrUserNotFound
+		}
+	}
+	for _, user := range users {
+		m.users[user.Id] = proto.Clone(user).(*pb.User)
+	}
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	delete(m.users, id)
+	delete(m.passwords, id)
+	return nil
+}
+
+func (m *MemoryStore) Count(ctx context.Context) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	n := 0
+	for _, user := range m.users {
+		if user.DeletedAt == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *MemoryStore) SetPasswordHash(ctx context.Context, id, hash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[id]; !ok {
+		return ErrUserNotFound
+	}
+	m.passwords[id] = hash
+	return nil
+}
+
+func (m *MemoryStore) GetPasswordHash(ctx context.Context, id string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.users[id]; !ok {
+		return "", ErrUserNotFound
+	}
+	return m.passwords[id], nil
+}
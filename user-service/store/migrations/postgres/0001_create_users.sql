@@ -0,0 +1,27 @@
+-- Tabel users untuk PostgresStore (kolom sama dengan SQLite, tipe native Postgres)
+-- created_at, updated_at & deleted_at TIMESTAMPTZ (presisi mikrodetik)
+-- updated_at NULL = belum pernah di-update, deleted_at NULL = tidak dihapus
+CREATE TABLE users (
+	id              TEXT PRIMARY KEY,
+	name            TEXT NOT NULL,
+	email           TEXT NOT NULL,
+	age             INTEGER NOT NULL DEFAULT 0,
+	created_at      TIMESTAMPTZ NOT NULL,
+	status          INTEGER NOT NULL DEFAULT 0,
+	canonical_email TEXT NOT NULL DEFAULT '',
+	updated_at      TIMESTAMPTZ,
+	deleted_at      TIMESTAMPTZ,
+	version         BIGINT NOT NULL DEFAULT 1,
+	roles           TEXT[] NOT NULL DEFAULT '{}',
+	password_hash   TEXT NOT NULL DEFAULT '',
+
+	-- Key uniqueness sama dengan UserServer.emailKey: canonical_email kalau ada, selain itu email lowercase
+	-- User soft-deleted tetap memegang email-nya (sama seperti email index di UserServer)
+	email_key TEXT GENERATED ALWAYS AS (COALESCE(NULLIF(canonical_email, ''), lower(btrim(email)))) STORED,
+
+	-- DEFERRABLE supaya UpdateAll (TransferEmail) bisa menukar email 2 user dalam 1 transaksi
+	CONSTRAINT users_email_key_unique UNIQUE (email_key) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+-- Urutan List (ORDER BY created_at, id)
+CREATE INDEX users_created_at_id ON users (created_at, id);
@@ -0,0 +1,160 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// redisKeyPrefix adalah prefix key Redis untuk entry user ("user-service:user:<id>")
+const redisKeyPrefix = "user-service:user:"
+
+// RedisCachingStore adalah decorator UserStore yang meng-cache hasil Get di Redis (REDIS_URL)
+// Beda dengan CachingStore (per proses), cache ini dipakai bersama semua replica,
+// jadi write dari replica manapun langsung membuang entry untuk replica lain juga
+//
+// Redis hanya cache, bukan source of truth: kalau Redis error/tidak bisa dihubungi,
+// read & write tetap jalan langsung ke store di bawahnya (hanya di-log)
+type RedisCachingStore struct {
+	next   UserStore
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisCachingStore membungkus next dengan cache Redis
+// url mengikuti format redis.ParseURL (contoh: "redis://:password@localhost:6379/0")
+func NewRedisCachingStore(ctx context.Context, next UserStore, url string, ttl time.Duration) (*RedisCachingStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+
+	// Fail fast saat startup kalau Redis tidak bisa dihubungi (salah URL/password)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisCachingStore{next: next, client: client, ttl: ttl}, nil
+}
+
+// Close menutup koneksi ke Redis (store di bawahnya ditutup oleh pemiliknya)
+func (c *RedisCachingStore) Close() error {
+	return c.client.Close()
+}
+
+func (c *RedisCachingStore) Get(ctx context.Context, id string) (*pb.User, error) {
+	if !StrongConsistency(ctx) {
+		if user := c.lookup(ctx, id); user != nil {
+			return user, nil
+		}
+	}
+
+	user, err := c.next.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.put(ctx, user)
+	return user, nil
+}
+
+func (c *RedisCachingStore) Create(ctx context.Context, user *pb.User) error {
+	if err := c.next.Create(ctx, user); err != nil {
+		return err
+	}
+	c.put(ctx, user)
+	return nil
+}
+
+// List tidak di-cache (hasilnya bergantung limit & berubah di setiap write)
+func (c *RedisCachingStore) List(ctx context.Context, limit int) ([]*pb.User, error) {
+	return c.next.List(ctx, limit)
+}
+
+// Count tidak di-cache, sama seperti List
+func (c *RedisCachingStore) Count(ctx context.Context) (int, error) {
+	return c.next.Count(ctx)
+}
+
+// Update & Delete membuang entry SETELAH write (berhasil atau tidak): replica lain bisa
+// saja mengisi ulang entry lama di antara invalidate dan write kalau dibuang sebelumnya
+func (c *RedisCachingStore) Update(ctx context.Context, user *pb.User) error {
+	err := c.next.Update(ctx, user)
+	c.invalidate(ctx, user.Id)
+	return err
+}
+
+func (c *RedisCachingStore) UpdateAll(ctx context.Context, users []*pb.User) error {
+	err := c.next.UpdateAll(ctx, users)
+	for _, user := range users {
+		c.invalidate(ctx, user.Id)
+	}
+	return err
+}
+
+func (c *RedisCachingStore) Delete(ctx context.Context, id string) error {
+	err := c.next.Delete(ctx, id)
+	c.invalidate(ctx, id)
+	return err
+}
+
+// Password hash tidak di-cache: hanya dibaca saat Authenticate
+func (c *RedisCachingStore) SetPasswordHash(ctx context.Context, id, hash string) error {
+	return c.next.SetPasswordHash(ctx, id, hash)
+}
+
+func (c *RedisCachingStore) GetPasswordHash(ctx context.Context, id string) (string, error) {
+	return c.next.GetPasswordHash(ctx, id)
+}
+
+// Ping diteruskan ke store di bawahnya (kalau didukung), dipakai HealthDetail
+// Redis sengaja tidak ikut dicek: Redis mati tidak membuat service tidak sehat
+func (c *RedisCachingStore) Ping(ctx context.Context) error {
+	if pinger, ok := c.next.(interface{ Ping(context.Context) error }); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// lookup return nil kalau miss, entry rusak, atau Redis error (→ fallback ke store)
+func (c *RedisCachingStore) lookup(ctx context.Context, id string) *pb.User {
+	data, err := c.client.Get(ctx, redisKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		log.Printf("⚠️  Redis cache get failed for %s: %v", id, err)
+		return nil
+	}
+
+	user := &pb.User{}
+	if err := proto.Unmarshal(data, user); err != nil {
+		log.Printf("⚠️  Corrupt Redis cache entry for %s: %v", id, err)
+		return nil
+	}
+	return user
+}
+
+func (c *RedisCachingStore) put(ctx context.Context, user *pb.User) {
+	data, err := proto.Marshal(user)
+	if err != nil {
+		return
+	}
+	if err := c.client.Set(ctx, redisKeyPrefix+user.Id, data, c.ttl).Err(); err != nil {
+		log.Printf("⚠️  Redis cache set failed for %s: %v", user.Id, err)
+	}
+}
+
+func (c *RedisCachingStore) invalidate(ctx context.Context, id string) {
+	// context.WithoutCancel: entry tetap dibuang walaupun request dibatalkan setelah write
+	if err := c.client.Del(context.WithoutCancel(ctx), redisKeyPrefix+id).Err(); err != nil {
+		log.Printf("⚠️  Redis cache invalidate failed for %s: %v", id, err)
+	}
+}
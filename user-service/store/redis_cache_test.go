@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func newTestRedisCachingStore(t *testing.T) (*RedisCachingStore, *countingStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	source := &countingStore{MemoryStore: NewMemoryStore()}
+	if err := source.Create(context.Background(), &pb.User{Id: "u1", Name: "Alice", Version: 1}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	cache, err := NewRedisCachingStore(context.Background(), source, "redis://"+mr.Addr(), time.Minute)
+	if err != nil {
+		t.Fatalf("NewRedisCachingStore: %v", err)
+	}
+	t.Cleanup(func() { cache.Close() })
+	return cache, source, mr
+}
+
+func TestRedisCachingStoreHitAndMiss(t *testing.T) {
+	cache, source, mr := newTestRedisCachingStore(t)
+	ctx := context.Background()
+
+	// Miss pertama mengisi Redis, berikutnya hit (store tidak dibaca lagi)
+	for i := 0; i < 3; i++ {
+		user, err := cache.Get(ctx, "u1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if user.Name != "Alice" {
+			t.Fatalf("Name = %q, want Alice", user.Name)
+		}
+	}
+	if got := source.gets.Load(); got != 1 {
+		t.Fatalf("store reads = %d, want 1 (1 miss, 2 hits)", got)
+	}
+	if !mr.Exists(redisKeyPrefix + "u1") {
+		t.Fatal("entry not written to Redis")
+	}
+	if ttl := mr.TTL(redisKeyPrefix + "u1"); ttl != time.Minute {
+		t.Fatalf("TTL = %s, want 1m", ttl)
+	}
+
+	// Setelah TTL habis → miss lagi
+	mr.FastForward(time.Minute + time.Second)
+	if _, err := cache.Get(ctx, "u1"); err != nil {
+		t.Fatalf("Get after TTL: %v", err)
+	}
+	if got := source.gets.Load(); got != 2 {
+		t.Fatalf("store reads after TTL = %d, want 2", got)
+	}
+}
+
+func TestRedisCachingStoreInvalidatesOnWrite(t *testing.T) {
+	cache, source, mr := newTestRedisCachingStore(t)
+	ctx := context.Background()
+
+	if _, err := cache.Get(ctx, "u1"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if err := cache.Update(ctx, &pb.User{Id: "u1", Name: "Alice B", Version: 2}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if mr.Exists(redisKeyPrefix + "u1") {
+		t.Fatal("entry still in Redis after Update")
+	}
+	user, err := cache.Get(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Get after update: %v", err)
+	}
+	if user.Name != "Alice B" || user.Version != 2 {
+		t.Fatalf("user = %v, want updated value", user)
+	}
+	if got := source.gets.Load(); got != 2 {
+		t.Fatalf("store reads = %d, want 2 (update forced a miss)", got)
+	}
+
+	if err := cache.Delete(ctx, "u1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := cache.Get(ctx, "u1"); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("Get after delete: err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestRedisCachingStoreFallsBackWhenRedisDown(t *testing.T) {
+	cache, source, mr := newTestRedisCachingStore(t)
+	mr.Close()
+
+	// Redis mati: read tetap dilayani store, bukan error
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Get(context.Background(), "u1"); err != nil {
+			t.Fatalf("Get with Redis down: %v", err)
+		}
+	}
+	if got := source.gets.Load(); got != 2 {
+		t.Fatalf("store reads = %d, want 2", got)
+	}
+}
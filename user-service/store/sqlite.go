@@ -0,0 +1,282 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+	_ "modernc.org/sqlite" // Driver "sqlite" (pure Go, tanpa cgo)
+)
+
+// createUsersTable dijalankan saat startup, aman diulang (IF NOT EXISTS)
+// created_at, updated_at & deleted_at disimpan sebagai string RFC3339 (lihat formatCreatedAt)
+//
+// Migrasi created_at/updated_at string → google.protobuf.Timestamp: skema tabel TIDAK berubah.
+// Baris lama (RFC3339 presisi detik) tetap terbaca oleh parseCreatedAt, baris baru
+// ditulis UTC dengan 9 digit nanodetik
+// Kolom yang ditambahkan setelah tabel pertama dibuat ada di addedColumns
+const createUsersTable = `
+CREATE TABLE IF NOT EXISTS users (
+	id              TEXT PRIMARY KEY,
+	name            TEXT NOT NULL,
+	email           TEXT NOT NULL,
+	age             INTEGER NOT NULL DEFAULT 0,
+	created_at      TEXT NOT NULL,
+	status          INTEGER NOT NULL DEFAULT 0,
+	canonical_email TEXT NOT NULL DEFAULT '',
+	updated_at      TEXT NOT NULL DEFAULT '',
+	deleted_at      TEXT NOT NULL DEFAULT '',
+	version         INTEGER NOT NULL DEFAULT 1,
+	password_hash   TEXT NOT NULL DEFAULT '',
+	roles           TEXT NOT NULL DEFAULT ''
+)`
+
+// addedColumns adalah kolom baru untuk database yang dibuat sebelum kolom itu ada
+// (CREATE TABLE IF NOT EXISTS tidak mengubah tabel lama), ditambahkan saat startup
+var addedColumns = []struct{ name, definition string }{
+	{"deleted_at", "TEXT NOT NULL DEFAULT ''"}, // Soft delete
+	{"version", "INTEGER NOT NULL DEFAULT 1"},  // Optimistic concurrency, baris lama mulai dari 1
+	{"password_hash", "TEXT NOT NULL DEFAULT ''"},
+	{"roles", "TEXT NOT NULL DEFAULT ''"}, // Dipisah koma, lihat joinRoles
+}
+
+// userColumns sengaja TIDAK berisi password_hash: hash hanya dibaca lewat GetPasswordHash
+const userColumns = `id, name, email, age, created_at, status, canonical_email, updated_at, deleted_at, version, roles`
+
+// SQLiteStore adalah UserStore yang persist ke SQLite lewat database/sql
+// Data tetap ada setelah restart (kecuali DSN ":memory:")
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore membuka database dari dsn (contoh: "file:users.db") dan membuat tabel users kalau belum ada
+func NewSQLiteStore(ctx context.Context, dsn string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+
+	// SQLite hanya mengizinkan 1 writer; 1 koneksi menghindari SQLITE_BUSY
+	// (dan wajib untuk ":memory:", yang databasenya per koneksi)
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect sqlite: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, createUsersTable); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create users table: %w", err)
+	}
+	if err := addMissingColumns(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate users table: %w", err)
+	}
+
+	return &SQLiteStore{db: db}, nil
+}
+
+// addMissingColumns menjalankan ALTER TABLE untuk addedColumns yang belum ada di tabel users
+func addMissingColumns(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM pragma_table_info('users')`)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, col := range addedColumns {
+		if existing[col.name] {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, `ALTER TABLE users ADD COLUMN `+col.name+` `+col.definition); err != nil {
+			return fmt.Errorf("add column %s: %w", col.name, err)
+		}
+	}
+	return nil
+}
+
+// Close menutup koneksi database
+func (st *SQLiteStore) Close() error {
+	return st.db.Close()
+}
+
+// Ping dipakai HealthDetail untuk cek konektivitas database
+func (st *SQLiteStore) Ping(ctx context.Context) error {
+	return st.db.PingContext(ctx)
+}
+
+func (st *SQLiteStore) Create(ctx context.Context, user *pb.User) error {
+	_, err := st.db.ExecContext(ctx,
+		`INSERT INTO users (`+userColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		user.Id, user.Name, user.Email, user.Age, formatCreatedAt(user.CreatedAt), int32(user.Status), user.CanonicalEmail, formatCreatedAt(user.UpdatedAt), formatCreatedAt(user.DeletedAt), user.Version, joinRoles(user.Roles),
+	)
+	return err
+}
+
+func (st *SQLiteStore) Get(ctx context.Context, id string) (*pb.User, error) {
+	row := st.db.QueryRowContext(ctx, `SELECT `+userColumns+` FROM users WHERE id = ?`, id)
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// List mengembalikan user urut waktu pembuatan (id sebagai tie-breaker)
+func (st *SQLiteStore) List(ctx context.Context, limit int) ([]*pb.User, error) {
+	if limit <= 0 {
+		limit = -1 // LIMIT -1 di SQLite = tanpa batas
+	}
+
+	rows, err := st.db.QueryContext(ctx, `SELECT `+userColumns+` FROM users ORDER BY created_at, id LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*pb.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+const updateUser = `UPDATE users SET name = ?, email = ?, age = ?, created_at = ?, status = ?, canonical_email = ?, updated_at = ?, deleted_at = ?, version = ?, roles = ? WHERE id = ?`
+
+func updateArgs(user *pb.User) []any {
+	return []any{user.Name, user.Email, user.Age, formatCreatedAt(user.CreatedAt), int32(user.Status), user.CanonicalEmail, formatCreatedAt(user.UpdatedAt), formatCreatedAt(user.DeletedAt), user.Version, joinRoles(user.Roles), user.Id}
+}
+
+func (st *SQLiteStore) Update(ctx context.Context, user *pb.User) error {
+	res, err := st.db.ExecContext(ctx, updateUser, updateArgs(user)...)
+	return notFoundIfNoRows(res, err)
+}
+
+func (st *SQLiteStore) UpdateAll(ctx context.Context, users []*pb.User) error {
+	tx, err := st.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, user := range users {
+		res, err := tx.ExecContext(ctx, updateUser, updateArgs(user)...)
+		if err := notFoundIfNoRows(res, err); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (st *SQLiteStore) Delete(ctx context.Context, id string) error {
+	res, err := st.db.ExecContext(ctx, `DELETE FROM users WHERE id = ?`, id)
+	return notFoundIfNoRows(res, err)
+}
+
+func (st *SQLiteStore) Count(ctx context.Context) (int, error) {
+	var n int
+	err := st.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users WHERE deleted_at = ''`).Scan(&n)
+	return n, err
+}
+
+func (st *SQLiteStore) SetPasswordHash(ctx context.Context, id, hash string) error {
+	res, err := st.db.ExecContext(ctx, `UPDATE users SET password_hash = ? WHERE id = ?`, hash, id)
+	return notFoundIfNoRows(res, err)
+}
+
+func (st *SQLiteStore) GetPasswordHash(ctx context.Context, id string) (string, error) {
+	var hash string
+	err := st.db.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE id = ?`, id).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrUserNotFound
+	}
+	return hash, err
+}
+
+// scanUser membaca 1 baris (urutan kolom = userColumns) menjadi pb.User
+func scanUser(row interface{ Scan(...any) error }) (*pb.User, error) {
+	var user pb.User
+	var userStatus int32
+	var createdAt, updatedAt, deletedAt, roles string
+	if err := row.Scan(&user.Id, &user.Name, &user.Email, &user.Age, &createdAt, &userStatus, &user.CanonicalEmail, &updatedAt, &deletedAt, &user.Version, &roles); err != nil {
+		return nil, err
+	}
+	user.Status = pb.UserStatus(userStatus)
+	user.CreatedAt = parseCreatedAt(createdAt)
+	user.UpdatedAt = parseCreatedAt(updatedAt)
+	user.DeletedAt = parseCreatedAt(deletedAt)
+	user.Roles = splitRoles(roles)
+	return &user, nil
+}
+
+// joinRoles & splitRoles: kolom roles berisi role dipisah koma ("" = tanpa role)
+// Aman karena nama role tidak boleh mengandung koma (divalidasi SetUserRoles)
+func joinRoles(roles []string) string {
+	return strings.Join(roles, ",")
+}
+
+func splitRoles(roles string) []string {
+	if roles == "" {
+		return nil
+	}
+	return strings.Split(roles, ",")
+}
+
+// createdAtLayout adalah RFC3339 dengan nanodetik lebar tetap, supaya urutan string
+// (ORDER BY created_at) sama dengan urutan waktu untuk semua baris yang ditulis UTC
+const createdAtLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// formatCreatedAt menulis Timestamp sebagai string kolom created_at / updated_at / deleted_at (nil = string kosong)
+func formatCreatedAt(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return ts.AsTime().UTC().Format(createdAtLayout)
+}
+
+// parseCreatedAt membaca kolom created_at / updated_at / deleted_at; nilai kosong/rusak menjadi nil
+// (RFC3339Nano juga menerima baris lama yang presisinya detik)
+func parseCreatedAt(raw string) *timestamppb.Timestamp {
+	t, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// notFoundIfNoRows mengubah UPDATE/DELETE yang tidak mengenai baris apapun jadi ErrUserNotFound
+func notFoundIfNoRows(res sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
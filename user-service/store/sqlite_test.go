@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestSQLiteCreatedAtRoundTrip(t *testing.T) {
+	created := timestamppb.New(time.Date(2024, 3, 1, 10, 30, 0, 123456789, time.FixedZone("WIB", 7*3600)))
+	if got := parseCreatedAt(formatCreatedAt(created)); !got.AsTime().Equal(created.AsTime()) {
+		t.Fatalf("round trip = %s, want %s", got.AsTime(), created.AsTime())
+	}
+
+	// Baris lama (string RFC3339 presisi detik, sebelum created_at jadi Timestamp) tetap terbaca
+	legacy := parseCreatedAt("2023-12-31T23:59:59Z")
+	if want := time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC); legacy == nil || !legacy.AsTime().Equal(want) {
+		t.Fatalf("legacy created_at = %v, want %s", legacy, want)
+	}
+	if parseCreatedAt("") != nil || parseCreatedAt("yesterday") != nil {
+		t.Fatal("empty or invalid created_at should parse to nil")
+	}
+}
+
+func TestSQLiteReadsLegacyCreatedAtRow(t *testing.T) {
+	ctx := context.Background()
+	st, err := NewSQLiteStore(ctx, "file::memory:")
+	if err != nil {
+		t.Fatalf("NewSQLiteStore: %v", err)
+	}
+	defer st.Close()
+
+	if _, err := st.db.ExecContext(ctx, `INSERT INTO users (id, name, email, age, created_at) VALUES ('old', 'Old', 'old@example.com', 40, '2023-01-02T03:04:05Z')`); err != nil {
+		t.Fatalf("insert legacy row: %v", err)
+	}
+	user, err := st.Get(ctx, "old")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if want := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC); !user.CreatedAt.AsTime().Equal(want) {
+		t.Fatalf("created_at = %s, want %s", user.CreatedAt.AsTime(), want)
+	}
+}
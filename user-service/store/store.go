@@ -0,0 +1,44 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	pb "user-service/proto/user"
+)
+
+// ErrUserNotFound dikembalikan UserStore kalau id tidak ada
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrEmailTaken dikembalikan store yang menjaga email unik sendiri (PostgresStore, unique constraint)
+// UserServer sudah mencegahnya lewat email index; ini lapisan terakhir kalau ada writer lain ke database yang sama
+var ErrEmailTaken = errors.New("email already registered")
+
+// UserStore adalah storage backend untuk data user (primary data / source of truth)
+// Implementasi: MemoryStore (default, map), SQLiteStore (DATABASE_DSN), dan PostgresStore (DATABASE_URL).
+// Backend lain (Postgres, Redis, mock untuk test) cukup mengimplementasikan interface ini,
+// tanpa menyentuh RPC handler di package server
+//
+// Soft delete hanyalah Update dengan DeletedAt terisi: Get/List tetap mengembalikan user itu,
+// UserServer yang menyembunyikannya. Delete selalu menghapus permanen
+//
+// Setiap method harus aman dipanggil concurrent. Koordinasi ANTAR operasi
+// (contoh: swap 2 user di TransferEmail, menjaga email index tetap sinkron)
+// tetap menjadi tanggung jawab UserServer
+type UserStore interface {
+	Create(ctx context.Context, user *pb.User) error
+	Get(ctx context.Context, id string) (*pb.User, error)    // ErrUserNotFound kalau tidak ada
+	List(ctx context.Context, limit int) ([]*pb.User, error) // limit 0 = semua
+	Update(ctx context.Context, user *pb.User) error         // ErrUserNotFound kalau tidak ada
+	Delete(ctx context.Context, id string) error             // ErrUserNotFound kalau tidak ada
+	Count(ctx context.Context) (int, error)                  // Jumlah user aktif (DeletedAt kosong), tanpa memuat datanya
+
+	// UpdateAll meng-update beberapa user secara atomic: semua berhasil atau tidak ada yang berubah
+	// (contoh: TransferEmail, yang di tengah-tengahnya 2 user sempat memegang email yang sama)
+	UpdateAll(ctx context.Context, users []*pb.User) error // ErrUserNotFound kalau salah satu tidak ada
+
+	// Password hash (bcrypt) disimpan terpisah dari pb.User, jadi tidak pernah ikut
+	// Get/List dan tidak mungkin bocor ke response, event, atau export
+	SetPasswordHash(ctx context.Context, id, hash string) error     // ErrUserNotFound kalau tidak ada
+	GetPasswordHash(ctx context.Context, id string) (string, error) // "" kalau user belum punya password
+}
@@ -0,0 +1,216 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	pb "user-service/proto/user"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// testUserStore menjalankan suite CRUD yang sama terhadap implementasi UserStore apa pun
+// newStore harus return store kosong yang baru untuk setiap subtest
+func testUserStore(t *testing.T, newStore func(t *testing.T) UserStore) {
+	ctx := context.Background()
+	created := time.Date(2024, 3, 1, 10, 30, 0, 123456000, time.UTC) // Presisi mikrodetik (batas TIMESTAMPTZ Postgres)
+	newUser := func(id, email string) *pb.User {
+		return &pb.User{
+			Id:        id,
+			Name:      "User " + id,
+			Email:     email,
+			Age:       30,
+			Status:    pb.UserStatus_USER_STATUS_ACTIVE,
+			CreatedAt: timestamppb.New(created),
+			Version:   1,
+			Roles:     []string{"admin"},
+		}
+	}
+
+	t.Run("create and get", func(t *testing.T) {
+		st := newStore(t)
+		want := newUser("u1", "alice@example.com")
+		if err := st.Create(ctx, want); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		got, err := st.Get(ctx, "u1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if !proto.Equal(got, want) {
+			t.Fatalf("Get = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		st := newStore(t)
+		if _, err := st.Get(ctx, "nope"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("Get err = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("list with limit", func(t *testing.T) {
+		st := newStore(t)
+		for _, id := range []string{"u1", "u2", "u3"} {
+			if err := st.Create(ctx, newUser(id, id+"@example.com")); err != nil {
+				t.Fatalf("Create %s: %v", id, err)
+			}
+		}
+		all, err := st.List(ctx, 0)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		var ids []string
+		for _, user := range all {
+			ids = append(ids, user.Id)
+		}
+		sort.Strings(ids)
+		if len(ids) != 3 || ids[0] != "u1" || ids[2] != "u3" {
+			t.Fatalf("List(0) ids = %v, want u1..u3", ids)
+		}
+		limited, err := st.List(ctx, 2)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(limited) != 2 {
+			t.Fatalf("List(2) returned %d users", len(limited))
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		st := newStore(t)
+		if err := st.Create(ctx, newUser("u1", "alice@example.com")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		updated := newUser("u1", "alice@example.org")
+		updated.Name = "Alice"
+		updated.Version = 2
+		updated.UpdatedAt = timestamppb.New(created.Add(time.Hour))
+		if err := st.Update(ctx, updated); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		got, err := st.Get(ctx, "u1")
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		if !proto.Equal(got, updated) {
+			t.Fatalf("Get after Update = %v, want %v", got, updated)
+		}
+
+		if err := st.Update(ctx, newUser("nope", "x@example.com")); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("Update missing err = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("update all is atomic", func(t *testing.T) {
+		st := newStore(t)
+		if err := st.Create(ctx, newUser("u1", "a@example.com")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		changed := newUser("u1", "changed@example.com")
+		err := st.UpdateAll(ctx, []*pb.User{changed, newUser("nope", "x@example.com")})
+		if !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("UpdateAll err = %v, want ErrUserNotFound", err)
+		}
+		got, _ := st.Get(ctx, "u1")
+		if got.Email != "a@example.com" {
+			t.Fatalf("email = %q after failed UpdateAll, want unchanged", got.Email)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		st := newStore(t)
+		if err := st.Create(ctx, newUser("u1", "alice@example.com")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if err := st.Delete(ctx, "u1"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := st.Get(ctx, "u1"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("Get after Delete err = %v, want ErrUserNotFound", err)
+		}
+		if err := st.Delete(ctx, "u1"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("second Delete err = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("count skips soft deleted", func(t *testing.T) {
+		st := newStore(t)
+		for _, id := range []string{"u1", "u2"} {
+			if err := st.Create(ctx, newUser(id, id+"@example.com")); err != nil {
+				t.Fatalf("Create %s: %v", id, err)
+			}
+		}
+		deleted := newUser("u2", "u2@example.com")
+		deleted.DeletedAt = timestamppb.New(created.Add(time.Hour))
+		if err := st.Update(ctx, deleted); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if n, err := st.Count(ctx); err != nil || n != 1 {
+			t.Fatalf("Count = %d, %v; want 1", n, err)
+		}
+	})
+
+	t.Run("password hash", func(t *testing.T) {
+		st := newStore(t)
+		if err := st.Create(ctx, newUser("u1", "alice@example.com")); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if hash, err := st.GetPasswordHash(ctx, "u1"); err != nil || hash != "" {
+			t.Fatalf("GetPasswordHash = %q, %v; want empty", hash, err)
+		}
+		if err := st.SetPasswordHash(ctx, "u1", "$2a$10$hash"); err != nil {
+			t.Fatalf("SetPasswordHash: %v", err)
+		}
+		if hash, err := st.GetPasswordHash(ctx, "u1"); err != nil || hash != "$2a$10$hash" {
+			t.Fatalf("GetPasswordHash = %q, %v", hash, err)
+		}
+		if err := st.SetPasswordHash(ctx, "nope", "x"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("SetPasswordHash missing err = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		st := newStore(t)
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				id := string(rune('a' + i))
+				if err := st.Create(ctx, newUser(id, id+"@example.com")); err != nil {
+					t.Errorf("Create %s: %v", id, err)
+				}
+				if _, err := st.List(ctx, 0); err != nil {
+					t.Errorf("List: %v", err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		if n, err := st.Count(ctx); err != nil || n != 20 {
+			t.Fatalf("Count = %d, %v; want 20", n, err)
+		}
+	})
+}
+
+func TestMemoryStoreConformance(t *testing.T) {
+	testUserStore(t, func(t *testing.T) UserStore {
+		return NewMemoryStore()
+	})
+}
+
+func TestSQLiteStoreConformance(t *testing.T) {
+	testUserStore(t, func(t *testing.T) UserStore {
+		st, err := NewSQLiteStore(context.Background(), "file::memory:")
+		if err != nil {
+			t.Fatalf("NewSQLiteStore: %v", err)
+		}
+		t.Cleanup(func() { st.Close() })
+		return st
+	})
+}
@@ -0,0 +1,62 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// Mode verifikasi client certificate (TLS_CLIENT_AUTH)
+const (
+	ClientAuthNone    = "none"    // Tidak minta client cert (TLS biasa)
+	ClientAuthVerify  = "verify"  // Client cert opsional, tapi kalau dikirim harus valid
+	ClientAuthRequire = "require" // Mutual TLS: client cert wajib & harus valid
+)
+
+// Server membuat tls.Config untuk gRPC server
+// clientCAFile hanya dipakai kalau clientAuth = verify/require
+func Server(certFile, keyFile, clientCAFile, clientAuth string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load server certificate: %w", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	switch clientAuth {
+	case ClientAuthNone, "":
+		cfg.ClientAuth = tls.NoClientCert
+		return cfg, nil
+	case ClientAuthVerify:
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unknown client auth mode %q", clientAuth)
+	}
+
+	pool, err := loadCertPool(clientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("load client CA: %w", err)
+	}
+	cfg.ClientCAs = pool
+
+	return cfg, nil
+}
+
+// loadCertPool membaca file PEM berisi 1 atau lebih CA certificate
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pemData, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemData) {
+		return nil, fmt.Errorf("no valid certificates in %s", file)
+	}
+	return pool, nil
+}
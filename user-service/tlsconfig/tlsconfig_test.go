@@ -0,0 +1,214 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"user-service/interceptor"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// testCA adalah CA sementara untuk menerbitkan server & client cert di test
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue menerbitkan leaf cert, return cert & key dalam format PEM
+func (ca *testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// startTLSServer menjalankan health server dengan config dari Server();
+// identitas client yang terbaca interceptor.Identity dikirim ke channel identities
+func startTLSServer(t *testing.T, ca *testCA, clientAuth string) (string, <-chan string) {
+	t.Helper()
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, "user-service", x509.ExtKeyUsageServerAuth)
+	tlsCfg, err := Server(
+		writeFile(t, dir, "server.pem", certPEM),
+		writeFile(t, dir, "server-key.pem", keyPEM),
+		writeFile(t, dir, "client-ca.pem", ca.pem),
+		clientAuth,
+	)
+	if err != nil {
+		t.Fatalf("Server: %v", err)
+	}
+
+	identities := make(chan string, 10)
+	identityUnary, _ := interceptor.Identity()
+	record := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id, _ := interceptor.ClientIdentityFromContext(ctx)
+		identities <- id.String()
+		return handler(ctx, req)
+	}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsCfg)), grpc.ChainUnaryInterceptor(identityUnary, record))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String(), identities
+}
+
+// check memanggil Health.Check lewat TLS, dengan client cert kalau clientCert != nil
+func check(t *testing.T, addr string, ca *testCA, clientCert *tls.Certificate) error {
+	t.Helper()
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(ca.pem)
+	tlsCfg := &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	if clientCert != nil {
+		// Selalu kirim cert, walaupun issuer-nya tidak ada di daftar CA yang diminta server
+		// (Certificates biasa akan diam-diam tidak dikirim untuk cert dari CA asing)
+		tlsCfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return clientCert, nil
+		}
+	}
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func clientCert(t *testing.T, ca *testCA, cn string) *tls.Certificate {
+	t.Helper()
+	certPEM, keyPEM := ca.issue(t, cn, x509.ExtKeyUsageClientAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &cert
+}
+
+func TestMutualTLSRequire(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	addr, identities := startTLSServer(t, ca, ClientAuthRequire)
+
+	if err := check(t, addr, ca, clientCert(t, ca, "api-gateway")); err != nil {
+		t.Fatalf("valid client cert: %v", err)
+	}
+	if id := <-identities; id != "api-gateway" {
+		t.Fatalf("client identity = %q, want api-gateway", id)
+	}
+
+	// Cert dari CA lain dan tanpa cert sama sekali ditolak saat handshake
+	rogue := newTestCA(t, "rogue-ca")
+	if err := check(t, addr, ca, clientCert(t, rogue, "api-gateway")); err == nil {
+		t.Fatal("client cert from untrusted CA: want error")
+	}
+	if err := check(t, addr, ca, nil); err == nil {
+		t.Fatal("missing client cert: want error")
+	}
+	if len(identities) != 0 {
+		t.Fatal("rejected connection reached the handler")
+	}
+}
+
+func TestMutualTLSVerifyIfGiven(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	addr, identities := startTLSServer(t, ca, ClientAuthVerify)
+
+	// verify: tanpa cert boleh (tanpa identitas), cert yang dikirim tetap harus valid
+	if err := check(t, addr, ca, nil); err != nil {
+		t.Fatalf("missing client cert in verify mode: %v", err)
+	}
+	if id := <-identities; id != "" {
+		t.Fatalf("client identity = %q, want none", id)
+	}
+	if err := check(t, addr, ca, clientCert(t, newTestCA(t, "rogue-ca"), "api-gateway")); err == nil {
+		t.Fatal("client cert from untrusted CA in verify mode: want error")
+	}
+}
+
+func TestServerRejectsUnknownClientAuth(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	dir := t.TempDir()
+	certPEM, keyPEM := ca.issue(t, "user-service", x509.ExtKeyUsageServerAuth)
+	_, err := Server(writeFile(t, dir, "server.pem", certPEM), writeFile(t, dir, "server-key.pem", keyPEM), "", "optional")
+	if err == nil {
+		t.Fatal("unknown TLS_CLIENT_AUTH: want error")
+	}
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	sampledParent    = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	notSampledParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+)
+
+// startSpan memulai span seperti request masuk: traceparent (kalau ada) di-extract dulu
+func startSpan(sampleRate float64, traceparent string) bool {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(NewSampler(sampleRate)))
+	defer tp.Shutdown(context.Background())
+
+	ctx := context.Background()
+	if traceparent != "" {
+		header := http.Header{"Traceparent": {traceparent}}
+		ctx = propagation.TraceContext{}.Extract(ctx, propagation.HeaderCarrier(header))
+	}
+	_, span := tp.Tracer("test").Start(ctx, "request")
+	defer span.End()
+	return span.SpanContext().IsSampled()
+}
+
+func TestSamplerRespectsIncomingSampledFlag(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampleRate  float64
+		traceparent string
+		want        bool
+	}{
+		{"sampled parent, rate 0", 0, sampledParent, true},
+		{"not sampled parent, rate 1", 1, notSampledParent, false},
+		{"root span, rate 0", 0, "", false},
+		{"root span, rate 1", 1, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := startSpan(tt.sampleRate, tt.traceparent); got != tt.want {
+				t.Fatalf("sampled = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplerRatioForRootSpans(t *testing.T) {
+	const n = 2000
+	sampled := 0
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(NewSampler(0.1)))
+	defer tp.Shutdown(context.Background())
+	for i := 0; i < n; i++ {
+		_, span := tp.Tracer("test").Start(context.Background(), "request")
+		if span.SpanContext().IsSampled() {
+			sampled++
+		}
+		span.End()
+	}
+	// Trace ID acak, jadi cukup cek kira-kira 10% (toleransi lebar supaya tidak flaky)
+	if sampled < n*5/100 || sampled > n*15/100 {
+		t.Fatalf("sampled %d of %d root spans, want about 10%%", sampled, n)
+	}
+}